@@ -1,6 +1,6 @@
 module github.com/biodoia/NEXUS-SWARM
 
-go 1.22
+go 1.23
 
 require (
 	github.com/charmbracelet/bubbletea v1.2.4
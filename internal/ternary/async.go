@@ -0,0 +1,20 @@
+package ternary
+
+// EvaluateAsync evaluates a rule in a new goroutine and returns a channel
+// that delivers its result and is then closed. The channel is buffered, so
+// the goroutine finishes even if the result is never received. The inputs
+// are copied before EvaluateAsync returns.
+//
+// The evaluation takes the engine lock like Evaluate, so it is safe to
+// run alongside any other call, but async decisions are recorded in the
+// history in the order they complete, not the order EvaluateAsync was
+// called.
+func (e *Engine) EvaluateAsync(ruleName string, inputs ...Trit) <-chan TernaryResult {
+  	inputs = append([]Trit(nil), inputs...)
+  	out := make(chan TernaryResult, 1)
+  	go func() {
+      		defer close(out)
+      		out <- e.Evaluate(ruleName, inputs...)
+      	}()
+  	return out
+  }
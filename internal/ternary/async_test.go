@@ -0,0 +1,110 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  	"time"
+  )
+
+func TestEvaluateAsyncOneResult(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		rule       string
+      		want       Trit
+      		wantReason string
+      	}{
+      		{name: "evaluated", rule: "AND", want: TRUE, wantReason: "Rule[AND] evaluated 2 inputs"},
+      		{name: "not found", rule: "nope", want: UNKNOWN, wantReason: "not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			out := NewEngine().EvaluateAsync(tt.rule, TRUE, TRUE)
+                    			select {
+                              			case r := <-out:
+                              				if r.Value != tt.want || !strings.Contains(r.Reason, tt.wantReason) {
+                                          					t.Errorf("EvaluateAsync(%s) = %v %q, want %v with %q", tt.rule, r.Value, r.Reason, tt.want, tt.wantReason)
+                                          				}
+                              			case <-time.After(time.Second):
+                              				t.Fatal("no result within a second")
+                              			}
+                    			if r, ok := <-out; ok {
+                              				t.Errorf("second receive = %v, want a closed channel", r)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateAsyncUnreceived(t *testing.T) {
+  	e := NewEngine()
+  	e.EvaluateAsync("AND", TRUE)
+  	// the buffered send lets the evaluation finish and be recorded unread
+  	deadline := time.Now().Add(time.Second)
+  	for len(e.GetDecisions(DecisionFilter{})) == 0 {
+      		if time.Now().After(deadline) {
+            			t.Fatal("unreceived evaluation never recorded")
+            		}
+      		time.Sleep(time.Millisecond)
+      	}
+  }
+
+func TestEvaluateAsyncCompletionOrder(t *testing.T) {
+  	// the clock is read under the engine lock, so each timestamp is the
+  	// evaluation's completion sequence number
+  	var tick int64
+  	e := NewEngine(WithClock(func() time.Time {
+                    		tick++
+                    		return time.Unix(tick, 0)
+                    	}))
+
+  	e.mu.Lock()
+  	chans := make([]<-chan TernaryResult, 20)
+  	for i := range chans {
+      		chans[i] = e.EvaluateAsync("AND", TRUE)
+      	}
+  	e.mu.Unlock()
+
+  	completed := make(map[string]int64)
+  	for _, c := range chans {
+      		r := <-c
+      		completed[r.ID] = r.Timestamp.Unix()
+      	}
+  	history := e.GetDecisions(DecisionFilter{})
+  	if len(history) != len(chans) {
+      		t.Fatalf("recorded %d decisions, want %d", len(history), len(chans))
+      	}
+  	for i, d := range history {
+      		seq, ok := completed[d.ID]
+      		if !ok {
+            			t.Fatalf("decision %d (%s) was not delivered", i, d.ID)
+            		}
+      		if i > 0 && seq < history[i-1].Timestamp.Unix() {
+            			t.Errorf("decision %d completed at %d, before its predecessor", i, seq)
+            		}
+      	}
+  }
+
+func TestEvaluateAsyncCopiesInputs(t *testing.T) {
+  	e := NewEngine()
+  	in := []Trit{TRUE, TRUE}
+  	out := e.EvaluateAsync("AND", in...)
+  	in[1] = FALSE
+  	if r := <-out; r.Value != TRUE {
+      		t.Errorf("EvaluateAsync saw a later write to its inputs: %v", r.Value)
+      	}
+  }
+
+func TestEvaluateAsyncConcurrent(t *testing.T) {
+  	e := NewEngine()
+  	chans := make([]<-chan TernaryResult, 50)
+  	for i := range chans {
+      		chans[i] = e.EvaluateAsync("CONSENSUS", TRUE, FALSE, TRUE)
+      	}
+  	for i, c := range chans {
+      		if r := <-c; r.Value != TRUE {
+            			t.Errorf("result %d = %v, want TRUE", i, r.Value)
+            		}
+      	}
+  	if got := e.Stats()["total_evaluations"]; got != uint64(len(chans)) {
+      		t.Errorf("total_evaluations = %v, want %d", got, len(chans))
+      	}
+  }
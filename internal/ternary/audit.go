@@ -0,0 +1,173 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sort"
+  	"strings"
+  )
+
+// maxEnumArity bounds exhaustive input enumeration (3^10 = 59049 vectors)
+const maxEnumArity = 10
+
+// forEachInput calls fn with every input vector of length arity, in
+// lexicographic FALSE < UNKNOWN < TRUE order, until fn returns false. The
+// slice passed to fn is reused between calls.
+func forEachInput(arity int, fn func(inputs []Trit) bool) {
+  	inputs := make([]Trit, arity)
+  	for i := range inputs {
+      		inputs[i] = FALSE
+      	}
+  	for {
+      		if !fn(inputs) {
+            			return
+            		}
+      		i := arity - 1
+      		for ; i >= 0 && inputs[i] == TRUE; i-- {
+            			inputs[i] = FALSE
+            		}
+      		if i < 0 {
+            			return
+            		}
+      		inputs[i]++
+      	}
+  }
+
+// safeEvaluate runs rule over inputs, holding the rule's mutex if it is
+// NotThreadSafe and converting a panic into an error
+func safeEvaluate(rule TernaryRule, inputs []Trit) (value Trit, err error) {
+  	if rule.mu != nil {
+      		rule.mu.Lock()
+      		defer rule.mu.Unlock()
+      	}
+  	defer func() {
+      		if r := recover(); r != nil {
+            			err = fmt.Errorf("panic: %v", r)
+            		}
+      	}()
+  	return rule.Evaluate(inputs...), nil
+  }
+
+// AuditRules exercises every registered rule with all input vectors of
+// length 0 through arityToCheck (capped at 10), or only those of its Arity
+// for a rule with a positive Arity as Evaluate never calls it with any
+// other, and returns, per rule, the problems found: invalid output trits, panics, and different outputs for
+// the same inputs across two runs. Each kind of problem is reported once per
+// rule with the first inputs that triggered it. Rules without problems are
+// omitted, so an empty map means the audit passed.
+func (e *Engine) AuditRules(arityToCheck int) map[string][]string {
+  	if arityToCheck > maxEnumArity {
+      		arityToCheck = maxEnumArity
+      	}
+
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	report := make(map[string][]string)
+  	for name, rule := range e.rules {
+      		seen := make(map[string]bool)
+      		add := func(kind, detail string) {
+            			if !seen[kind] {
+                    				seen[kind] = true
+                    				report[name] = append(report[name], detail)
+                    			}
+            		}
+
+      		for n := 0; n <= arityToCheck; n++ {
+            			if rule.Arity > 0 && n != rule.Arity {
+                    				continue
+                    			}
+            			forEachInput(n, func(inputs []Trit) bool {
+                              				args := formatInputs(inputs)
+                              				first, err := safeEvaluate(rule, inputs)
+                              				if err != nil {
+                                          					add("panic", fmt.Sprintf("%s(%s): %v", name, args, err))
+                                          					return true
+                                          				}
+                              				if !IsValid(first) {
+                                          					add("invalid", fmt.Sprintf("%s(%s): invalid output %d", name, args, first))
+                                          				}
+                              				second, err := safeEvaluate(rule, inputs)
+                              				if err == nil && second != first {
+                                          					add("nondeterministic", fmt.Sprintf("%s(%s): nondeterministic output %s then %s",
+                                                                        						name, args, tritName(first), tritName(second)))
+                                          				}
+                              				return true
+                              			})
+            		}
+      	}
+
+  	for name := range report {
+      		sort.Strings(report[name])
+      	}
+  	return report
+  }
+
+// formatInputs renders inputs as a comma-separated list of constant names
+func formatInputs(inputs []Trit) string {
+  	names := make([]string, len(inputs))
+  	for i, t := range inputs {
+      		names[i] = tritName(t)
+      	}
+  	return strings.Join(names, ", ")
+  }
+
+// PreimageOf returns every input vector of length arity on which ruleName
+// evaluates to target, in lexicographic FALSE < UNKNOWN < TRUE order. It
+// returns nil for unknown or disabled rules and for arities outside
+// [0, 10], which would need more than 3^10 evaluations.
+func (e *Engine) PreimageOf(ruleName string, target Trit, arity int) [][]Trit {
+  	if arity < 0 || arity > maxEnumArity {
+      		return nil
+      	}
+
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	rule, _, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return nil
+      	}
+
+  	var matches [][]Trit
+  	forEachInput(arity, func(inputs []Trit) bool {
+            		if v, _ := e.ruleValueLocked(ruleName, rule, inputs); v == target {
+                    			matches = append(matches, append([]Trit(nil), inputs...))
+                    		}
+            		return true
+            	})
+  	return matches
+  }
+
+// CheckDeterminism evaluates ruleName over inputs runs times, without
+// recording, and reports whether every run produced the same value along
+// with the distinct values observed in order of first appearance. It
+// returns false and no values for unknown or disabled rules.
+func (e *Engine) CheckDeterminism(ruleName string, inputs []Trit, runs int) (bool, []Trit) {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	rule, _, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return false, nil
+      	}
+  	inputs, _, ok = e.sanitizeLocked(ruleName, inputs)
+  	if !ok {
+      		return false, nil
+      	}
+
+  	var distinct []Trit
+  	for i := 0; i < runs; i++ {
+      		v := e.computeLocked(rule, inputs)
+      		seen := false
+      		for _, d := range distinct {
+            			if d == v {
+                    				seen = true
+                    				break
+                    			}
+            		}
+      		if !seen {
+            			distinct = append(distinct, v)
+            		}
+      	}
+  	return len(distinct) <= 1, distinct
+  }
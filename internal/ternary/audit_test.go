@@ -0,0 +1,178 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestAuditRules(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		rule      func(inputs ...Trit) Trit
+      		ruleArity int
+      		arity     int
+      		want      []string // substrings of the sorted problems reported for BAD
+      	}{
+      		{name: "clean", rule: func(...Trit) Trit { return TRUE }, arity: 3},
+      		{name: "invalid output", rule: func(...Trit) Trit { return Trit(9) }, arity: 2, want: []string{"BAD(): invalid output 9"}},
+      		{
+            			name: "panic",
+            			rule: func(inputs ...Trit) Trit {
+                    				if len(inputs) == 2 {
+                              					panic("two")
+                              				}
+                    				return TRUE
+                    			},
+            			arity: 2,
+            			want:  []string{"BAD(FALSE, FALSE): panic: two"},
+            		},
+      		{
+            			name: "nondeterministic",
+            			rule: func() func(...Trit) Trit {
+                    				n := 0
+                    				return func(...Trit) Trit {
+                              					n++
+                              					return Trit(n%2*2 - 1)
+                              				}
+                    			}(),
+            			arity: 0,
+            			want:  []string{"BAD(): nondeterministic output TRUE then FALSE"},
+            		},
+      		{
+            			name: "one report per kind",
+            			rule: func(inputs ...Trit) Trit {
+                    				if len(inputs) == 1 {
+                              					panic("one")
+                              				}
+                    				return Trit(7)
+                    			},
+            			arity: 2,
+            			want:  []string{"BAD(): invalid output 7", "BAD(FALSE): panic: one"},
+            		},
+      		{
+            			name: "other arities skipped",
+            			rule: func(inputs ...Trit) Trit {
+                    				return min(inputs[0], inputs[1])
+                    			},
+            			ruleArity: 2,
+            			arity:     3,
+            		},
+      		{
+            			name: "declared arity checked",
+            			rule: func(inputs ...Trit) Trit {
+                    				if inputs[1] == TRUE {
+                              					panic("guarded")
+                              				}
+                    				return inputs[0]
+                    			},
+            			ruleArity: 2,
+            			arity:     3,
+            			want:      []string{"BAD(FALSE, TRUE): panic: guarded"},
+            		},
+      		{
+            			name:      "declared arity above the bound",
+            			rule:      func(...Trit) Trit { return Trit(9) },
+            			ruleArity: 4,
+            			arity:     3,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if report := e.AuditRules(tt.arity); len(report) != 0 {
+                              				t.Fatalf("built-in rules fail the audit: %v", report)
+                              			}
+                    			e.AddRule("BAD", TernaryRule{Name: "BAD", Evaluate: tt.rule, Arity: tt.ruleArity, NotThreadSafe: true})
+                    			report := e.AuditRules(tt.arity)
+                    			if len(tt.want) == 0 {
+                              				if len(report) != 0 {
+                                          					t.Errorf("AuditRules = %v, want no problems", report)
+                                          				}
+                              				return
+                              			}
+                    			got := report["BAD"]
+                    			if len(report) != 1 || len(got) != len(tt.want) {
+                              				t.Fatalf("AuditRules = %v, want %d problems for BAD", report, len(tt.want))
+                              			}
+                    			for i, want := range tt.want {
+                              				if !strings.Contains(got[i], want) {
+                                          					t.Errorf("problem %d = %q, want %q", i, got[i], want)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestForEachInput(t *testing.T) {
+  	var got [][]Trit
+  	forEachInput(2, func(inputs []Trit) bool {
+            		got = append(got, append([]Trit(nil), inputs...))
+            		return len(got) < 4
+            	})
+  	want := [][]Trit{{FALSE, FALSE}, {FALSE, UNKNOWN}, {FALSE, TRUE}, {UNKNOWN, FALSE}}
+  	if !reflect.DeepEqual(got, want) {
+      		t.Errorf("forEachInput = %v, want %v", got, want)
+      	}
+  }
+
+func TestPreimageOf(t *testing.T) {
+  	tests := []struct {
+      		rule   string
+      		target Trit
+      		arity  int
+      		want   [][]Trit
+      	}{
+      		{"AND", TRUE, 2, [][]Trit{{TRUE, TRUE}}},
+      		{"AND", UNKNOWN, 2, [][]Trit{{UNKNOWN, UNKNOWN}, {UNKNOWN, TRUE}, {TRUE, UNKNOWN}}},
+      		{"AND", FALSE, 2, [][]Trit{{FALSE, FALSE}, {FALSE, UNKNOWN}, {FALSE, TRUE}, {UNKNOWN, FALSE}, {TRUE, FALSE}}},
+      		{"NOT", TRUE, 1, [][]Trit{{FALSE}}},
+      		{"OR", FALSE, 3, [][]Trit{{FALSE, FALSE, FALSE}}},
+      		{"missing", TRUE, 1, nil},
+      		{"AND", TRUE, -1, nil},
+      		{"AND", TRUE, maxEnumArity + 1, nil},
+      	}
+  	for _, tt := range tests {
+      		got := NewEngine().PreimageOf(tt.rule, tt.target, tt.arity)
+      		if !reflect.DeepEqual(got, tt.want) {
+            			t.Errorf("PreimageOf(%q, %v, %d) = %v, want %v", tt.rule, tt.target, tt.arity, got, tt.want)
+            		}
+      	}
+  }
+
+func TestCheckDeterminism(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		rule   string
+      		inputs []Trit
+      		runs   int
+      		wantOK bool
+      		want   []Trit
+      	}{
+      		{name: "deterministic", rule: "AND", inputs: []Trit{TRUE, UNKNOWN}, runs: 10, wantOK: true, want: []Trit{UNKNOWN}},
+      		{name: "flaky", rule: "FLIP", runs: 5, wantOK: false, want: []Trit{TRUE, FALSE}},
+      		{name: "flaky once", rule: "FLIP", runs: 1, wantOK: true, want: []Trit{TRUE}},
+      		{name: "no runs", rule: "AND", runs: 0, wantOK: true},
+      		{name: "missing rule", rule: "missing", runs: 3, wantOK: false},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			calls := 0
+                    			e.AddRule("FLIP", TernaryRule{Name: "FLIP", Weight: 1, Evaluate: func(...Trit) Trit {
+                                                        				calls++
+                                                        				if calls%2 == 1 {
+                                                                        					return TRUE
+                                                                        				}
+                                                        				return FALSE
+                                                        			}})
+                    			ok, got := e.CheckDeterminism(tt.rule, tt.inputs, tt.runs)
+                    			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("CheckDeterminism(%q, %v, %d) = %v, %v, want %v, %v", tt.rule, tt.inputs, tt.runs, ok, got, tt.wantOK, tt.want)
+                              			}
+                    			if d := e.GetDecisions(DecisionFilter{}); len(d) != 0 {
+                              				t.Errorf("CheckDeterminism recorded %d decisions", len(d))
+                              			}
+                    		})
+      	}
+  }
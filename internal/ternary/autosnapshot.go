@@ -0,0 +1,71 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sync"
+  	"time"
+  )
+
+// EnableAutoSnapshot saves the engine's state every interval from a
+// background goroutine: the history to path with SaveHistory and the
+// scorecard to path+".scorecard" with SaveScorecard, both atomically. A
+// tick on which nothing changed since the last snapshot writes nothing.
+// Failed snapshots are logged and retried on the next tick.
+//
+// The returned stop function halts the goroutine after a final snapshot of
+// any pending changes, and returns once it has exited; calling it again
+// does nothing. EnableAutoSnapshot returns an error, and starts nothing, if
+// interval is not positive.
+func (e *Engine) EnableAutoSnapshot(path string, interval time.Duration) (stop func(), err error) {
+  	if interval <= 0 {
+      		return nil, fmt.Errorf("ternary: auto-snapshot interval %v must be positive", interval)
+      	}
+
+  	done := make(chan struct{})
+  	exited := make(chan struct{})
+  	go func() {
+      		defer close(exited)
+      		ticker := time.NewTicker(interval)
+      		defer ticker.Stop()
+
+      		var saved uint64 // version of the last snapshot; 0 is a new engine
+      		for {
+            			select {
+                    			case <-ticker.C:
+                    				saved = e.autoSnapshot(path, saved)
+                    			case <-done:
+                    				e.autoSnapshot(path, saved)
+                    				return
+                    			}
+            		}
+      	}()
+
+  	var once sync.Once
+  	return func() {
+      		once.Do(func() {
+                    			close(done)
+                    			<-exited
+                    		})
+      	}, nil
+  }
+
+// autoSnapshot saves the history and scorecard to path unless the engine
+// is still at version saved, and returns the version now on disk
+func (e *Engine) autoSnapshot(path string, saved uint64) uint64 {
+  	e.mu.RLock()
+  	version := e.version
+  	e.mu.RUnlock()
+  	if version == saved {
+      		return saved
+      	}
+
+  	if err := e.SaveHistory(path); err != nil {
+      		e.logger.Warn("ternary: auto-snapshot failed", "path", path, "err", err)
+      		return saved
+      	}
+  	if err := e.SaveScorecard(path + ".scorecard"); err != nil {
+      		e.logger.Warn("ternary: auto-snapshot failed", "path", path, "err", err)
+      		return saved
+      	}
+  	return version
+  }
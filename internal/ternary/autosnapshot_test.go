@@ -0,0 +1,121 @@
+package ternary
+
+import (
+  	"os"
+  	"path/filepath"
+  	"reflect"
+  	"testing"
+  	"time"
+  )
+
+// waitForFile polls until path exists or the deadline passes
+func waitForFile(t *testing.T, path string) {
+  	t.Helper()
+  	deadline := time.Now().Add(2 * time.Second)
+  	for {
+      		if _, err := os.Stat(path); err == nil {
+            			return
+            		}
+      		if time.Now().After(deadline) {
+            			t.Fatalf("no snapshot at %s", path)
+            		}
+      		time.Sleep(5 * time.Millisecond)
+      	}
+  }
+
+func TestAutoSnapshotAppearsAfterInterval(t *testing.T) {
+  	path := filepath.Join(t.TempDir(), "hist.json")
+  	e := NewEngine()
+  	stop, err := e.EnableAutoSnapshot(path, 10*time.Millisecond)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	defer stop()
+
+  	e.Evaluate("AND", TRUE)
+  	waitForFile(t, path)
+  	waitForFile(t, path+".scorecard")
+  }
+
+func TestAutoSnapshotSkipsUnchanged(t *testing.T) {
+  	path := filepath.Join(t.TempDir(), "hist.json")
+  	e := NewEngine()
+  	stop, err := e.EnableAutoSnapshot(path, 5*time.Millisecond)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	defer stop()
+
+  	time.Sleep(30 * time.Millisecond)
+  	if _, err := os.Stat(path); !os.IsNotExist(err) {
+      		t.Fatalf("snapshot of a new engine: %v", err)
+      	}
+
+  	e.Evaluate("AND", TRUE)
+  	waitForFile(t, path)
+  	// once saved, further ticks must leave the file alone
+  	os.Remove(path)
+  	time.Sleep(30 * time.Millisecond)
+  	if _, err := os.Stat(path); !os.IsNotExist(err) {
+      		t.Errorf("snapshot rewritten without a change: %v", err)
+      	}
+  }
+
+func TestAutoSnapshotStop(t *testing.T) {
+  	path := filepath.Join(t.TempDir(), "hist.json")
+  	e := NewEngine()
+  	// an interval that never ticks leaves the final snapshot to stop
+  	stop, err := e.EnableAutoSnapshot(path, time.Hour)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Evaluate("AND", TRUE)
+  	stop()
+  	if _, err := os.Stat(path); err != nil {
+      		t.Fatalf("stop did not flush pending changes: %v", err)
+      	}
+
+  	stop()
+  	os.Remove(path)
+  	e.Evaluate("OR", TRUE)
+  	time.Sleep(20 * time.Millisecond)
+  	if _, err := os.Stat(path); !os.IsNotExist(err) {
+      		t.Errorf("snapshot written after stop: %v", err)
+      	}
+  }
+
+func TestAutoSnapshotRoundTrip(t *testing.T) {
+  	path := filepath.Join(t.TempDir(), "hist.json")
+  	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+  	e := NewEngine(WithClock(func() time.Time { return at }))
+  	stop, err := e.EnableAutoSnapshot(path, time.Hour)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Feedback(e.Evaluate("AND", TRUE, UNKNOWN), FALSE)
+  	e.Feedback(e.Evaluate("OR", FALSE, TRUE), TRUE)
+  	stop()
+
+  	f := NewEngine()
+  	if err := f.LoadHistory(path); err != nil {
+      		t.Fatal(err)
+      	}
+  	if err := f.LoadScorecard(path + ".scorecard"); err != nil {
+      		t.Fatal(err)
+      	}
+  	if got, want := f.GetDecisions(DecisionFilter{}), e.GetDecisions(DecisionFilter{}); !reflect.DeepEqual(got, want) {
+      		t.Errorf("loaded decisions = %v, want %v", got, want)
+      	}
+  	if got, want := f.Scorecard(), e.Scorecard(); !reflect.DeepEqual(got, want) {
+      		t.Errorf("loaded scorecard = %v, want %v", got, want)
+      	}
+  }
+
+func TestAutoSnapshotInterval(t *testing.T) {
+  	e := NewEngine()
+  	for _, interval := range []time.Duration{0, -time.Second} {
+      		if stop, err := e.EnableAutoSnapshot(filepath.Join(t.TempDir(), "hist.json"), interval); err == nil || stop != nil {
+            			t.Errorf("EnableAutoSnapshot interval %v: want error", interval)
+            		}
+      	}
+  }
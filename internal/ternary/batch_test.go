@@ -0,0 +1,269 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"sync"
+  	"testing"
+  )
+
+func TestEvaluateBatch(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		rule      string
+      		inputSets [][]Trit
+      		want      []Trit
+      		reason    string
+      		decisions int
+      	}{
+      		{name: "in order", rule: "AND", inputSets: [][]Trit{{TRUE}, {FALSE}, {TRUE, UNKNOWN}}, want: []Trit{TRUE, FALSE, UNKNOWN}, reason: "evaluated", decisions: 3},
+      		{name: "empty", rule: "AND", inputSets: nil, want: []Trit{}, decisions: 0},
+      		{name: "unknown rule", rule: "nope", inputSets: [][]Trit{{TRUE}, {FALSE}, {}}, want: []Trit{UNKNOWN, UNKNOWN, UNKNOWN}, reason: "not found", decisions: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			rs := e.EvaluateBatch(tt.rule, tt.inputSets)
+                    			if len(rs) != len(tt.want) {
+                              				t.Fatalf("got %d results, want %d", len(rs), len(tt.want))
+                              			}
+                    			ids := map[string]bool{}
+                    			for i, r := range rs {
+                              				if r.Value != tt.want[i] || !strings.Contains(r.Reason, tt.reason) {
+                                          					t.Errorf("result %d = %v %q, want %v containing %q", i, r.Value, r.Reason, tt.want[i], tt.reason)
+                                          				}
+                              				if ids[r.ID] {
+                                          					t.Errorf("result %d reuses ID %q", i, r.ID)
+                                          				}
+                              				ids[r.ID] = true
+                              			}
+                    			st := e.Stats()
+                    			if st["total_evaluations"] != uint64(len(tt.inputSets)) || st["total_decisions"] != tt.decisions {
+                              				t.Errorf("stats = %v", st)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateBatchMatchesEvaluate(t *testing.T) {
+  	sets := [][]Trit{{TRUE}, {TRUE, FALSE}, {UNKNOWN}, {TRUE, 9, TRUE}, {}, {FALSE, TRUE}}
+  	tests := []struct {
+      		name string
+      		mode InvalidInputs
+      	}{
+      		{name: "reject", mode: RejectInvalid},
+      		{name: "coerce", mode: CoerceInvalid},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			batch := NewEngine(WithInvalidInputs(tt.mode), CaptureInputs())
+                    			loop := NewEngine(WithInvalidInputs(tt.mode), CaptureInputs())
+                    			for i, r := range batch.EvaluateBatch("OR", sets) {
+                              				want := loop.Evaluate("OR", sets[i]...)
+                              				if r.Value != want.Value || r.Reason != want.Reason || !reflect.DeepEqual(r.Inputs, want.Inputs) {
+                                          					t.Errorf("set %d: EvaluateBatch = %v %q %v, Evaluate = %v %q %v",
+                                                        						i, r.Value, r.Reason, r.Inputs, want.Value, want.Reason, want.Inputs)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateBatchCoerce(t *testing.T) {
+  	sets := [][]Trit{{TRUE, 5, TRUE}, {FALSE, -4}, {TRUE}, {}}
+  	tests := []struct {
+      		rule string
+      		want []Trit
+      	}{
+      		{"AND", []Trit{UNKNOWN, FALSE, TRUE, TRUE}},
+      		{"OR", []Trit{TRUE, UNKNOWN, TRUE, FALSE}},
+      		{"CONSENSUS", []Trit{TRUE, UNKNOWN, TRUE, UNKNOWN}},
+      		{"KEEP", []Trit{TRUE, FALSE, TRUE, UNKNOWN}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.rule, func(t *testing.T) {
+                    			e := NewEngine(WithInvalidInputs(CoerceInvalid), CaptureInputs())
+                    			e.AddRule("KEEP", TernaryRule{Name: "KEEP", Weight: 1, Evaluate: func(in ...Trit) Trit {
+                                                        				if len(in) == 0 {
+                                                                        					return UNKNOWN
+                                                                        				}
+                                                        				return in[0]
+                                                        			}})
+                    			rs := e.EvaluateBatch(tt.rule, sets)
+                    			for i, r := range rs {
+                              				if r.Value != tt.want[i] {
+                                          					t.Errorf("set %d: got %v, want %v", i, r.Value, tt.want[i])
+                                          				}
+                              			}
+                    			if !reflect.DeepEqual(rs[0].Inputs, []Trit{TRUE, UNKNOWN, TRUE}) || !reflect.DeepEqual(rs[1].Inputs, []Trit{FALSE, UNKNOWN}) {
+                              				t.Errorf("captured inputs %v %v", rs[0].Inputs, rs[1].Inputs)
+                              			}
+                    			if sets[0][1] != 5 || sets[1][1] != -4 {
+                              				t.Fatal("caller input sets changed")
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateBatchRetainingRule(t *testing.T) {
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid))
+  	var kept [][]Trit
+  	e.AddRule("RETAIN", TernaryRule{Name: "RETAIN", Weight: 1, Evaluate: func(in ...Trit) Trit {
+                    		kept = append(kept, in)
+                    		return TRUE
+                    	}})
+  	e.EvaluateBatch("RETAIN", [][]Trit{{TRUE, 9}, {FALSE, 9}, {UNKNOWN, 9}})
+  	e.EvaluateBatch("AND", [][]Trit{{FALSE, 9}, {FALSE, 9}})
+  	want := [][]Trit{{TRUE, UNKNOWN}, {FALSE, UNKNOWN}, {UNKNOWN, UNKNOWN}}
+  	if !reflect.DeepEqual(kept, want) {
+      		t.Fatalf("retained inputs = %v, want %v", kept, want)
+      	}
+  }
+
+func TestEvaluateBatchConcurrent(t *testing.T) {
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid), CaptureInputs())
+  	var wg sync.WaitGroup
+  	for g := 0; g < 8; g++ {
+      		wg.Add(1)
+      		go func(g int) {
+            			defer wg.Done()
+            			v := Trit(g%3 - 1)
+            			sets := [][]Trit{{v, v, 7}, {v, -7, v, v}, {v}}
+            			for k := 0; k < 50; k++ {
+                    				for _, rule := range []string{"AND", "OR", "CONSENSUS"} {
+                              					for i, r := range e.EvaluateBatch(rule, sets) {
+                                          						if len(r.Inputs) != len(sets[i]) || r.Inputs[0] != v {
+                                                        							t.Errorf("%s set %d: aliased inputs %v", rule, i, r.Inputs)
+                                                        						}
+                                          					}
+                              				}
+                    			}
+            		}(g)
+      	}
+  	wg.Wait()
+  	for _, d := range e.historyLocked() {
+      		for _, in := range d.Inputs {
+            			if !IsValid(in) {
+                    				t.Fatalf("recorded invalid input in %+v", d)
+                    			}
+            		}
+      	}
+  }
+
+func benchmarkInputSets(n int) [][]Trit {
+  	sets := make([][]Trit, n)
+  	for i := range sets {
+      		sets[i] = []Trit{TRUE, Trit(i%3 - 1), TRUE}
+      	}
+  	return sets
+  }
+
+func BenchmarkEvaluateLoop(b *testing.B) {
+  	e := NewEngine()
+  	sets := benchmarkInputSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		for _, inputs := range sets {
+            			e.Evaluate("AND", inputs...)
+            		}
+      	}
+  }
+
+// BenchmarkEvaluateLoopKept keeps the results, as EvaluateBatch returns
+// them; it is the allocation baseline for EvaluateBatch.
+func BenchmarkEvaluateLoopKept(b *testing.B) {
+  	e := NewEngine()
+  	sets := benchmarkInputSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		results := make([]TernaryResult, len(sets))
+      		for j, inputs := range sets {
+            			results[j] = e.Evaluate("AND", inputs...)
+            		}
+      	}
+  }
+
+func BenchmarkEvaluateBatch(b *testing.B) {
+  	e := NewEngine()
+  	sets := benchmarkInputSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		e.EvaluateBatch("AND", sets)
+      	}
+  }
+
+// The parallel variants show the lock contention EvaluateBatch avoids when
+// several goroutines score readings at once.
+
+func BenchmarkEvaluateLoopParallel(b *testing.B) {
+  	e := NewEngine()
+  	sets := benchmarkInputSets(100)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	b.RunParallel(func(pb *testing.PB) {
+            		for pb.Next() {
+                    			for _, inputs := range sets {
+                              				e.Evaluate("AND", inputs...)
+                              			}
+                    		}
+            	})
+  }
+
+func BenchmarkEvaluateBatchParallel(b *testing.B) {
+  	e := NewEngine()
+  	sets := benchmarkInputSets(100)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	b.RunParallel(func(pb *testing.PB) {
+            		for pb.Next() {
+                    			e.EvaluateBatch("AND", sets)
+                    		}
+            	})
+  }
+
+// coerceSets returns n input sets each holding one invalid trit
+func coerceSets(n int) [][]Trit {
+  	sets := make([][]Trit, n)
+  	for i := range sets {
+      		sets[i] = []Trit{TRUE, 5, Trit(i%3 - 1)}
+      	}
+  	return sets
+  }
+
+func BenchmarkEvaluateLoopCoerce(b *testing.B) {
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid))
+  	sets := coerceSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		for _, inputs := range sets {
+            			e.Evaluate("AND", inputs...)
+            		}
+      	}
+  }
+
+func BenchmarkEvaluateLoopCoerceKept(b *testing.B) {
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid))
+  	sets := coerceSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		results := make([]TernaryResult, len(sets))
+      		for j, inputs := range sets {
+            			results[j] = e.Evaluate("AND", inputs...)
+            		}
+      	}
+  }
+
+func BenchmarkEvaluateBatchCoerce(b *testing.B) {
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid))
+  	sets := coerceSets(1000)
+  	b.ReportAllocs()
+  	b.ResetTimer()
+  	for i := 0; i < b.N; i++ {
+      		e.EvaluateBatch("AND", sets)
+      	}
+  }
@@ -0,0 +1,30 @@
+package ternary
+
+import (
+  	"fmt"
+  	"math/rand"
+  )
+
+// Collapse resolves an UNKNOWN to TRUE with probability trueProbability
+// and to FALSE otherwise; TRUE and FALSE are returned unchanged. A nil rng
+// uses the math/rand default source, so pass a seeded one for reproducible
+// runs. Collapse returns an error if trueProbability is outside [0, 1].
+func Collapse(t Trit, trueProbability float64, rng *rand.Rand) (Trit, error) {
+  	if !(trueProbability >= 0 && trueProbability <= 1) {
+      		return t, fmt.Errorf("ternary: Collapse probability %v outside [0, 1]", trueProbability)
+      	}
+  	if t != UNKNOWN {
+      		return t, nil
+      	}
+
+  	var draw float64
+  	if rng != nil {
+      		draw = rng.Float64()
+      	} else {
+      		draw = rand.Float64()
+      	}
+  	if draw < trueProbability {
+      		return TRUE, nil
+      	}
+  	return FALSE, nil
+  }
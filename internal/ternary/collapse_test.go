@@ -0,0 +1,97 @@
+package ternary
+
+import (
+  	"math"
+  	"math/rand"
+  	"strings"
+  	"testing"
+  )
+
+func TestCollapse(t *testing.T) {
+  	tests := []struct {
+      		t       Trit
+      		p       float64
+      		want    Trit
+      		wantErr bool
+      	}{
+      		{t: TRUE, p: 0, want: TRUE},
+      		{t: FALSE, p: 1, want: FALSE},
+      		{t: UNKNOWN, p: 1, want: TRUE},
+      		{t: UNKNOWN, p: 0, want: FALSE},
+      		{t: UNKNOWN, p: -0.5, wantErr: true},
+      		{t: UNKNOWN, p: 1.5, wantErr: true},
+      		{t: UNKNOWN, p: math.NaN(), wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		got, err := Collapse(tt.t, tt.p, nil)
+      		if (err != nil) != tt.wantErr {
+            			t.Errorf("Collapse(%v, %v) error = %v, wantErr %v", tt.t, tt.p, err, tt.wantErr)
+            			continue
+            		}
+      		if err == nil && got != tt.want {
+            			t.Errorf("Collapse(%v, %v) = %v, want %v", tt.t, tt.p, got, tt.want)
+            		}
+      	}
+  }
+
+func TestCollapseReproducible(t *testing.T) {
+  	a, b := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+  	trues := 0
+  	for i := 0; i < 1000; i++ {
+      		x, _ := Collapse(UNKNOWN, 0.3, a)
+      		if y, _ := Collapse(UNKNOWN, 0.3, b); x != y {
+            			t.Fatalf("draw %d differs between equally seeded sources: %v, %v", i, x, y)
+            		}
+      		if x == TRUE {
+            			trues++
+            		}
+      	}
+  	if trues < 230 || trues > 370 {
+      		t.Errorf("%d of 1000 collapsed to TRUE, want about 300", trues)
+      	}
+  }
+
+func TestCollapseDistribution(t *testing.T) {
+  	const draws = 20000
+  	for _, p := range []float64{0.1, 0.25, 0.5, 0.9} {
+      		rng := rand.New(rand.NewSource(1))
+      		trues := 0
+      		for i := 0; i < draws; i++ {
+            			got, err := Collapse(UNKNOWN, p, rng)
+            			if err != nil {
+                    				t.Fatalf("Collapse(UNKNOWN, %v) error = %v", p, err)
+                    			}
+            			if got == TRUE {
+                    				trues++
+                    			} else if got != FALSE {
+                    				t.Fatalf("Collapse(UNKNOWN, %v) = %v, want TRUE or FALSE", p, got)
+                    			}
+            		}
+      		// five standard deviations of a binomial proportion
+      		tol := 5 * math.Sqrt(p*(1-p)/draws)
+      		if got := float64(trues) / draws; math.Abs(got-p) > tol {
+            			t.Errorf("p = %v: TRUE fraction %v, want within %v", p, got, tol)
+            		}
+      	}
+  }
+
+func TestCollapseDefiniteSkipsDraw(t *testing.T) {
+  	a, b := rand.New(rand.NewSource(3)), rand.New(rand.NewSource(3))
+  	for _, tr := range []Trit{TRUE, FALSE, TRUE} {
+      		if got, _ := Collapse(tr, 0.5, a); got != tr {
+            			t.Errorf("Collapse(%v, 0.5) = %v, want it unchanged", tr, got)
+            		}
+      	}
+  	if got, want := a.Int63(), b.Int63(); got != want {
+      		t.Errorf("definite inputs consumed draws: next value %d, want %d", got, want)
+      	}
+  }
+
+func TestCollapseErrorKeepsInput(t *testing.T) {
+  	for _, tr := range []Trit{FALSE, UNKNOWN, TRUE} {
+      		got, err := Collapse(tr, 2, nil)
+      		if err == nil || !strings.Contains(err.Error(), "outside [0, 1]") || got != tr {
+            			t.Errorf("Collapse(%v, 2) = %v, %v, want %v and a range error", tr, got, err, tr)
+            		}
+      	}
+  }
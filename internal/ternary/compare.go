@@ -0,0 +1,69 @@
+package ternary
+
+// CompareToBaseline evaluates ruleName and baselineRule over the same inputs
+// and reports whether their values diverged, e.g. to audit where EVOLVE's
+// action bias changes the outcome relative to CONSENSUS. Both decisions are
+// recorded.
+func (e *Engine) CompareToBaseline(ruleName, baselineRule string, inputs ...Trit) (result, baseline TernaryResult, diverged bool) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	result = e.evaluateLocked(ruleName, inputs)
+  	baseline = e.evaluateLocked(baselineRule, inputs)
+  	return result, baseline, result.Value != baseline.Value
+  }
+
+// AgreementMatrix returns, for each pair of rules evaluated on identical
+// inputs, the fraction of those input sets on which they produced the same
+// value. Decisions are matched through their captured inputs, so this
+// requires input capture (CaptureInputs or SetCaptureInputs) to have been
+// enabled while they were recorded; decisions without captured inputs are
+// ignored. When a rule was evaluated several times on the same inputs its
+// latest value is used. The matrix is symmetric and omits the diagonal.
+func (e *Engine) AgreementMatrix() map[string]map[string]float64 {
+  	e.mu.RLock()
+  	byInputs := make(map[string]map[string]Trit)
+  	for _, r := range e.historyLocked() {
+      		if r.Rule == "" || r.Inputs == nil {
+            			continue
+            		}
+      		key := formatInputs(r.Inputs)
+      		if byInputs[key] == nil {
+            			byInputs[key] = make(map[string]Trit)
+            		}
+      		byInputs[key][r.Rule] = r.Value
+      	}
+  	e.mu.RUnlock()
+
+  	type pair struct{ a, b string }
+  	total := make(map[pair]int)
+  	agree := make(map[pair]int)
+  	for _, values := range byInputs {
+      		for a, va := range values {
+            			for b, vb := range values {
+                    				if a >= b {
+                              					continue
+                              				}
+                    				p := pair{a, b}
+                    				total[p]++
+                    				if va == vb {
+                              					agree[p]++
+                              				}
+                    			}
+            		}
+      	}
+
+  	matrix := make(map[string]map[string]float64)
+  	set := func(a, b string, v float64) {
+      		if matrix[a] == nil {
+            			matrix[a] = make(map[string]float64)
+            		}
+      		matrix[a][b] = v
+      	}
+  	for p, n := range total {
+      		frac := float64(agree[p]) / float64(n)
+      		set(p.a, p.b, frac)
+      		set(p.b, p.a, frac)
+      	}
+  	return matrix
+  }
@@ -0,0 +1,106 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  )
+
+func TestCompareToBaseline(t *testing.T) {
+  	tests := []struct {
+      		name           string
+      		rule, baseline string
+      		inputs         []Trit
+      		want, wantBase Trit
+      		diverged       bool
+      	}{
+      		{name: "action bias", rule: "EVOLVE", baseline: "CONSENSUS", inputs: []Trit{UNKNOWN, UNKNOWN, FALSE}, want: TRUE, wantBase: UNKNOWN, diverged: true},
+      		{name: "agreement", rule: "EVOLVE", baseline: "CONSENSUS", inputs: []Trit{TRUE, TRUE, FALSE}, want: TRUE, wantBase: TRUE},
+      		{name: "missing baseline", rule: "AND", baseline: "nope", inputs: []Trit{TRUE}, want: TRUE, wantBase: UNKNOWN, diverged: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r, b, diverged := e.CompareToBaseline(tt.rule, tt.baseline, tt.inputs...)
+                    			if r.Value != tt.want || b.Value != tt.wantBase || diverged != tt.diverged {
+                              				t.Errorf("CompareToBaseline = %v, %v, %v, want %v, %v, %v", r.Value, b.Value, diverged, tt.want, tt.wantBase, tt.diverged)
+                              			}
+                    		})
+      	}
+  }
+
+func TestCompareToBaselineRecords(t *testing.T) {
+  	e := NewEngine()
+  	e.CompareToBaseline("EVOLVE", "CONSENSUS", UNKNOWN, UNKNOWN, FALSE)
+  	d := e.GetDecisions(DecisionFilter{})
+  	if len(d) != 2 || d[0].Rule != "EVOLVE" || d[1].Rule != "CONSENSUS" {
+      		t.Errorf("recorded %v, want EVOLVE then CONSENSUS", d)
+      	}
+  }
+
+func TestAgreementMatrix(t *testing.T) {
+  	// AND2 is AND under another name, so it always agrees with AND
+  	and2 := TernaryRule{Name: "AND2", Weight: 1, Evaluate: func(in ...Trit) Trit {
+            		result := TRUE
+            		for _, v := range in {
+                    			result = Min(result, v)
+                    		}
+            		return result
+            	}}
+  	inputSets := [][]Trit{{TRUE, FALSE}, {TRUE, TRUE}, {UNKNOWN, TRUE}}
+  	tests := []struct {
+      		name    string
+      		capture bool
+      		want    map[string]map[string]float64
+      	}{
+      		{
+            			name:    "captured",
+            			capture: true,
+            			want: map[string]map[string]float64{
+                    				"AND":  {"AND2": 1, "OR": 1.0 / 3},
+                    				"AND2": {"AND": 1, "OR": 1.0 / 3},
+                    				"OR":   {"AND": 1.0 / 3, "AND2": 1.0 / 3},
+                    			},
+            		},
+      		{name: "not captured", want: map[string]map[string]float64{}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.SetCaptureInputs(tt.capture)
+                    			e.AddRule("AND2", and2)
+                    			for _, in := range inputSets {
+                              				e.Evaluate("AND", in...)
+                              				e.Evaluate("AND2", in...)
+                              				e.Evaluate("OR", in...)
+                              			}
+                    			if got := e.AgreementMatrix(); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("AgreementMatrix = %v, want %v", got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestAgreementMatrixLatestValue(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	flip := FALSE
+  	e.AddRule("FLIP", TernaryRule{Weight: 1, NotThreadSafe: true, Evaluate: func(...Trit) Trit { return flip }})
+  	e.Evaluate("FLIP", TRUE)
+  	e.Evaluate("AND", TRUE)
+  	flip = TRUE
+  	e.Evaluate("FLIP", TRUE)
+  	if got := e.AgreementMatrix()["AND"]["FLIP"]; got != 1 {
+      		t.Errorf("agreement = %v, want 1 from FLIP's latest value", got)
+      	}
+  }
+
+func TestCaptureInputs(t *testing.T) {
+  	in := []Trit{TRUE, UNKNOWN}
+  	r := NewEngine(CaptureInputs()).Evaluate("AND", in...)
+  	in[0] = FALSE
+  	if !reflect.DeepEqual(r.Inputs, []Trit{TRUE, UNKNOWN}) {
+      		t.Errorf("captured %v, want a copy of the inputs", r.Inputs)
+      	}
+  	if r := NewEngine().Evaluate("AND", in...); r.Inputs != nil {
+      		t.Errorf("captured %v without CaptureInputs", r.Inputs)
+      	}
+  }
@@ -0,0 +1,76 @@
+package ternary
+
+import (
+  	"runtime"
+  	"sync"
+  )
+
+// EvalRequest is a single evaluation submitted to ConcurrentEvaluate
+type EvalRequest struct {
+  	Rule   string
+  	Inputs []Trit
+  }
+
+// ConcurrentEvaluate runs the rules of all requests in parallel and records
+// the decisions in request order, returning results in the same order. Rule
+// functions run under the engine's read lock, so they overlap with each
+// other but never with Evaluate.
+//
+// A rule marked NotThreadSafe is guarded by its own mutex, so its calls
+// within and across ConcurrentEvaluate calls run one at a time. Batches
+// dominated by such a rule gain nothing from parallelism; keep stateful
+// rules cheap or make them thread-safe.
+func (e *Engine) ConcurrentEvaluate(reqs []EvalRequest) []TernaryResult {
+  	results := make([]TernaryResult, len(reqs))
+  	values := make([]Trit, len(reqs))
+  	notes := make([]string, len(reqs))
+  	rules := make([]TernaryRule, len(reqs))
+  	inputs := make([][]Trit, len(reqs))
+  	found := make([]bool, len(reqs))
+  	ok := make([]bool, len(reqs))
+
+  	e.mu.RLock()
+  	for i, req := range reqs {
+      		rules[i], results[i], found[i] = e.ruleLocked(req.Rule)
+      	}
+
+  	jobs := make(chan int)
+  	var wg sync.WaitGroup
+  	workers := runtime.GOMAXPROCS(0)
+  	if workers > len(reqs) {
+      		workers = len(reqs)
+      	}
+  	for w := 0; w < workers; w++ {
+      		wg.Add(1)
+      		go func() {
+            			defer wg.Done()
+            			for i := range jobs {
+                    				inputs[i], values[i], notes[i], ok[i] = e.invokeLocked(reqs[i].Rule, rules[i], reqs[i].Inputs)
+                    			}
+            		}()
+      	}
+  	for i := range reqs {
+      		if found[i] {
+            			jobs <- i
+            		}
+      	}
+  	close(jobs)
+  	wg.Wait()
+  	e.mu.RUnlock()
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	for i, req := range reqs {
+      		e.evalCount++
+      		if !found[i] {
+            			continue
+            		}
+      		if !ok[i] {
+            			results[i] = e.failedResult(notes[i])
+            			continue
+            		}
+      		reason := ruleReason(req.Rule, inputs[i], notes[i])
+      		results[i] = e.recordLocked(e.resultLocked(req.Rule, rules[i].Weight, values[i], inputs[i], reason))
+      	}
+  	return results
+  }
@@ -0,0 +1,93 @@
+package ternary
+
+import (
+  	"runtime"
+  	"sync"
+  	"testing"
+  )
+
+// racyCounter returns a NotThreadSafe rule that counts its calls without
+// synchronization of its own, so -race flags any unserialized call
+func racyCounter() (TernaryRule, *int) {
+  	count := new(int)
+  	rule := TernaryRule{
+      		Name:          "COUNT",
+      		Weight:        1,
+      		NotThreadSafe: true,
+      		Evaluate: func(in ...Trit) Trit {
+            			c := *count
+            			runtime.Gosched()
+            			*count = c + 1
+            			return TRUE
+            		},
+      		Weighted: func(in []Trit, w []float64) Trit {
+            			*count++
+            			return TRUE
+            		},
+      	}
+  	return rule, count
+  }
+
+func TestConcurrentNotThreadSafe(t *testing.T) {
+  	e := NewEngine()
+  	rule, count := racyCounter()
+  	e.AddRule("COUNT", rule)
+
+  	var wg sync.WaitGroup
+  	for g := 0; g < 8; g++ {
+      		wg.Add(1)
+      		go func() {
+            			defer wg.Done()
+            			reqs := make([]EvalRequest, 100)
+            			for i := range reqs {
+                    				reqs[i] = EvalRequest{Rule: "COUNT"}
+                    			}
+            			reqs[5].Rule = "MISSING"
+            			rs := e.ConcurrentEvaluate(reqs)
+            			if rs[5].Value != UNKNOWN || rs[6].Value != TRUE {
+                    				t.Error(rs[5], rs[6])
+                    			}
+            		}()
+      	}
+  	wg.Wait()
+  	if *count != 8*99 {
+      		t.Fatalf("count = %d, want %d", *count, 8*99)
+      	}
+  }
+
+func TestNotThreadSafeReadOnlyPaths(t *testing.T) {
+  	e := NewEngine()
+  	rule, count := racyCounter()
+  	e.AddRule("COUNT", rule)
+
+  	paths := []struct {
+      		name  string
+      		run   func()
+      		calls int
+      	}{
+      		{"ConcurrentEvaluate", func() { e.ConcurrentEvaluate([]EvalRequest{{Rule: "COUNT"}, {Rule: "COUNT"}}) }, 2},
+      		{"AuditRules", func() { e.AuditRules(1) }, 2 * (1 + 3)},
+      		{"PreimageOf", func() { e.PreimageOf("COUNT", TRUE, 1) }, 3},
+      		{"CheckDeterminism", func() { e.CheckDeterminism("COUNT", []Trit{TRUE}, 4) }, 4},
+      		{"EvaluateWeighted", func() { e.EvaluateWeighted("COUNT", []Trit{TRUE}, []float64{1}) }, 1},
+      	}
+  	const rounds = 20
+  	var wg sync.WaitGroup
+  	want := 0
+  	for _, p := range paths {
+      		want += rounds * p.calls
+      		for g := 0; g < 2; g++ {
+            			wg.Add(1)
+            			go func(run func()) {
+                    				defer wg.Done()
+                    				for i := 0; i < rounds/2; i++ {
+                              					run()
+                              				}
+                    			}(p.run)
+            		}
+      	}
+  	wg.Wait()
+  	if *count != want {
+      		t.Fatalf("count = %d, want %d", *count, want)
+      	}
+  }
@@ -0,0 +1,40 @@
+package ternary
+
+import "fmt"
+
+// EvaluateIf evaluates thenRule over thenInputs only if condRule over
+// condInputs is TRUE, and records the outcome under thenRule. A FALSE or
+// UNKNOWN condition records UNKNOWN without running thenRule. The Reason
+// carries both the condition and the action outcome.
+func (e *Engine) EvaluateIf(condRule string, condInputs []Trit, thenRule string, thenInputs []Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	cond, failed, ok := e.ruleLocked(condRule)
+  	if !ok {
+      		return failed
+      	}
+  	then, failed, ok := e.ruleLocked(thenRule)
+  	if !ok {
+      		return failed
+      	}
+
+  	_, condValue, condNote, ok := e.invokeLocked(condRule, cond, condInputs)
+  	if !ok {
+      		return e.failedResult(condNote)
+      	}
+  	thenInputs, reason, ok := e.sanitizeLocked(thenRule, thenInputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	if condValue != TRUE {
+      		reason := withNote(fmt.Sprintf("Condition Rule[%s] = %s; Rule[%s] skipped", condRule, tritName(condValue), thenRule), condNote)
+      		return e.recordLocked(e.resultLocked(thenRule, then.Weight, UNKNOWN, thenInputs, reason))
+      	}
+
+  	value, note := e.ruleValueLocked(thenRule, then, thenInputs)
+  	reason = withNote(withNote(fmt.Sprintf("Condition Rule[%s] = TRUE; Rule[%s] = %s", condRule, thenRule, tritName(value)), condNote), note)
+  	return e.recordLocked(e.resultLocked(thenRule, then.Weight, value, thenInputs, reason))
+  }
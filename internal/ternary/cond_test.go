@@ -0,0 +1,43 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateIf(t *testing.T) {
+  	tests := []struct {
+      		name         string
+      		cond         []Trit
+      		then         string
+      		thenInputs   []Trit
+      		want         Trit
+      		wantReason   string
+      		wantRan      bool
+      		wantRecorded bool
+      	}{
+      		{name: "TRUE runs action", cond: []Trit{TRUE}, then: "NOT", thenInputs: []Trit{TRUE}, want: FALSE, wantReason: "Condition Rule[AND] = TRUE; Rule[NOT] = FALSE", wantRecorded: true},
+      		{name: "action runs", cond: []Trit{TRUE}, then: "COUNT", want: TRUE, wantReason: "Rule[COUNT] = TRUE", wantRan: true, wantRecorded: true},
+      		{name: "FALSE skips action", cond: []Trit{FALSE}, then: "COUNT", want: UNKNOWN, wantReason: "Condition Rule[AND] = FALSE; Rule[COUNT] skipped", wantRecorded: true},
+      		{name: "UNKNOWN skips action", cond: []Trit{UNKNOWN}, then: "COUNT", want: UNKNOWN, wantReason: "Condition Rule[AND] = UNKNOWN; Rule[COUNT] skipped", wantRecorded: true},
+      		{name: "missing action", cond: []Trit{TRUE}, then: "missing", want: UNKNOWN, wantReason: "Rule 'missing' not found"},
+      		{name: "bad action arity", cond: []Trit{TRUE}, then: "IMPLIES", thenInputs: []Trit{TRUE}, want: UNKNOWN, wantReason: "takes 2 inputs, got 1", wantRecorded: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			ran := false
+                    			e.AddRule("COUNT", TernaryRule{Name: "COUNT", Weight: 1, Evaluate: func(...Trit) Trit {
+                                                        				ran = true
+                                                        				return TRUE
+                                                        			}})
+                    			r := e.EvaluateIf("AND", tt.cond, tt.then, tt.thenInputs)
+                    			if r.Value != tt.want || !strings.Contains(r.Reason, tt.wantReason) || ran != tt.wantRan {
+                              				t.Errorf("EvaluateIf = %v %q (ran %v), want %v containing %q (ran %v)", r.Value, r.Reason, ran, tt.want, tt.wantReason, tt.wantRan)
+                              			}
+                    			if recorded := len(e.GetDecisions(DecisionFilter{RuleName: tt.then})) == 1; recorded != tt.wantRecorded {
+                              				t.Errorf("recorded under %s = %v, want %v", tt.then, recorded, tt.wantRecorded)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,52 @@
+package ternary
+
+import (
+  	"fmt"
+  	"math"
+  )
+
+// EvaluateConfidence is Evaluate with the confidence of an UNKNOWN outcome
+// derived from the inputs instead of the fixed 0.5. With t and f the
+// fractions of TRUE and FALSE inputs, an UNKNOWN outcome gets
+//
+//	confidence = min(t, f) * weight
+//
+// so an UNKNOWN from a genuine even split (t = f = 0.5) keeps the usual
+// confidence while one reached from mostly UNKNOWN inputs falls toward 0.
+// TRUE and FALSE outcomes are unchanged. The Reason shows the derivation.
+func (e *Engine) EvaluateConfidence(ruleName string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+
+  	inputs, value, note, ok := e.invokeLocked(ruleName, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+  	if value != UNKNOWN || len(inputs) == 0 || note != "" {
+      		return e.recordLocked(e.resultLocked(ruleName, rule.Weight, value, inputs, ruleReason(ruleName, inputs, note)))
+      	}
+
+  	trueCount, falseCount := 0, 0
+  	for _, inp := range inputs {
+      		switch inp {
+            		case TRUE:
+            			trueCount++
+            		case FALSE:
+            			falseCount++
+            		}
+      	}
+  	n := float64(len(inputs))
+  	t, f := float64(trueCount)/n, float64(falseCount)/n
+  	reason := fmt.Sprintf("Rule[%s] evaluated %d inputs; UNKNOWN confidence min(%.2f TRUE, %.2f FALSE) x weight %g",
+      		ruleName, len(inputs), t, f, rule.Weight)
+  	result := e.resultLocked(ruleName, rule.Weight, value, inputs, reason)
+  	e.setConfidenceLocked(&result, math.Min(t, f)*rule.Weight)
+  	return e.recordLocked(result)
+  }
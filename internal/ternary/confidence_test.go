@@ -0,0 +1,35 @@
+package ternary
+
+import (
+  	"math"
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateConfidence(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		rule       string
+      		inputs     []Trit
+      		want       Trit
+      		wantConf   float64
+      		wantReason string
+      	}{
+      		{name: "even split", rule: "CONSENSUS", inputs: []Trit{TRUE, TRUE, FALSE, FALSE}, want: UNKNOWN, wantConf: 0.75, wantReason: "min(0.50 TRUE, 0.50 FALSE) x weight 1.5"},
+      		{name: "fog", rule: "CONSENSUS", inputs: []Trit{UNKNOWN, UNKNOWN, UNKNOWN, TRUE}, want: UNKNOWN, wantConf: 0, wantReason: "min(0.25 TRUE, 0.00 FALSE)"},
+      		{name: "three-way split", rule: "CONSENSUS", inputs: []Trit{TRUE, FALSE, UNKNOWN}, want: UNKNOWN, wantConf: 0.5},
+      		{name: "partial", rule: "AND", inputs: []Trit{TRUE, UNKNOWN}, want: UNKNOWN, wantConf: 0},
+      		{name: "definite unchanged", rule: "AND", inputs: []Trit{TRUE, TRUE}, want: TRUE, wantConf: 1, wantReason: "Rule[AND] evaluated 2 inputs"},
+      		{name: "no inputs unchanged", rule: "CONSENSUS", want: UNKNOWN, wantConf: 0.75},
+      		{name: "missing rule", rule: "nope", want: UNKNOWN, wantConf: 0, wantReason: "Rule 'nope' not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := NewEngine().EvaluateConfidence(tt.rule, tt.inputs...)
+                    			if r.Value != tt.want || math.Abs(r.Confidence-tt.wantConf) > 1e-9 || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateConfidence(%s, %v) = %v conf %v (%q), want %v conf %v containing %q",
+                                          					tt.rule, tt.inputs, r.Value, r.Confidence, r.Reason, tt.want, tt.wantConf, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,49 @@
+package ternary
+
+import "context"
+
+// EvaluateContext is Evaluate for callers that may abandon the decision:
+// if ctx is done before the engine lock is acquired, nothing is evaluated
+// and ctx.Err() is returned
+func (e *Engine) EvaluateContext(ctx context.Context, ruleName string, inputs ...Trit) (TernaryResult, error) {
+  	if err := ctx.Err(); err != nil {
+      		return TernaryResult{}, err
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if err := ctx.Err(); err != nil {
+      		return TernaryResult{}, err
+      	}
+  	return e.evaluateLocked(ruleName, inputs), nil
+  }
+
+// EvaluateBatchContext is EvaluateBatch checking ctx before each input set.
+// Once ctx is done it stops and returns the results so far, which are
+// recorded, together with ctx.Err(); only evaluated sets count in Stats.
+func (e *Engine) EvaluateBatchContext(ctx context.Context, ruleName string, inputSets [][]Trit) ([]TernaryResult, error) {
+  	if err := ctx.Err(); err != nil {
+      		return nil, err
+      	}
+  	results := make([]TernaryResult, 0, len(inputSets))
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	rule, _, ok := e.ruleLocked(ruleName)
+  	for _, inputs := range inputSets {
+      		if err := ctx.Err(); err != nil {
+            			return results, err
+            		}
+      		e.evalCount++
+      		if !ok {
+            			_, failed, _ := e.ruleLocked(ruleName)
+            			results = append(results, failed)
+            			continue
+            		}
+      		result, _ := e.applyLocked(ruleName, rule, inputs)
+      		results = append(results, result)
+      	}
+  	return results, nil
+  }
@@ -0,0 +1,92 @@
+package ternary
+
+import (
+  	"context"
+  	"errors"
+  	"testing"
+  	"time"
+  )
+
+func TestEvaluateContext(t *testing.T) {
+  	canceled, cancel := context.WithCancel(context.Background())
+  	cancel()
+  	expired, cancel2 := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+  	defer cancel2()
+  	tests := []struct {
+      		name      string
+      		ctx       context.Context
+      		want      Trit
+      		wantErr   error
+      		wantEvals uint64
+      	}{
+      		{name: "live", ctx: context.Background(), want: TRUE, wantEvals: 1},
+      		{name: "canceled", ctx: canceled, wantErr: context.Canceled},
+      		{name: "deadline exceeded", ctx: expired, wantErr: context.DeadlineExceeded},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r, err := e.EvaluateContext(tt.ctx, "AND", TRUE)
+                    			if !errors.Is(err, tt.wantErr) || r.Value != tt.want {
+                              				t.Errorf("EvaluateContext = %v, %v, want %v, %v", r.Value, err, tt.want, tt.wantErr)
+                              			}
+                    			if got := e.Stats()["total_evaluations"]; got != tt.wantEvals {
+                              				t.Errorf("total_evaluations = %v, want %d", got, tt.wantEvals)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateBatchContext(t *testing.T) {
+  	sets := func(n, cancelAt int) [][]Trit {
+      		out := make([][]Trit, n)
+      		for i := range out {
+            			out[i] = []Trit{TRUE}
+            		}
+      		if cancelAt >= 0 {
+            			out[cancelAt] = []Trit{FALSE}
+            		}
+      		return out
+      	}
+  	tests := []struct {
+      		name        string
+      		rule        string
+      		sets        [][]Trit
+      		preCanceled bool
+      		wantCount   int
+      		wantNil     bool
+      		wantErr     error
+      		wantEvals   uint64
+      	}{
+      		{name: "complete", rule: "CANCEL_ON_FALSE", sets: sets(10, -1), wantCount: 10, wantEvals: 10},
+      		{name: "canceled midway", rule: "CANCEL_ON_FALSE", sets: sets(10, 2), wantCount: 3, wantErr: context.Canceled, wantEvals: 3},
+      		{name: "canceled at the last set", rule: "CANCEL_ON_FALSE", sets: sets(3, 2), wantCount: 3, wantEvals: 3},
+      		{name: "canceled before", rule: "AND", sets: sets(10, -1), preCanceled: true, wantNil: true, wantErr: context.Canceled},
+      		{name: "missing rule", rule: "nope", sets: sets(4, -1), wantCount: 4, wantEvals: 4},
+      		{name: "no sets", rule: "AND", wantCount: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			ctx, cancel := context.WithCancel(context.Background())
+                    			defer cancel()
+                    			if tt.preCanceled {
+                              				cancel()
+                              			}
+                    			e := NewEngine()
+                    			e.AddRule("CANCEL_ON_FALSE", TernaryRule{Name: "CANCEL_ON_FALSE", Weight: 1, Evaluate: func(in ...Trit) Trit {
+                                                        				if len(in) == 1 && in[0] == FALSE {
+                                                                        					cancel()
+                                                                        				}
+                                                        				return UNKNOWN
+                                                        			}})
+                    			rs, err := e.EvaluateBatchContext(ctx, tt.rule, tt.sets)
+                    			if !errors.Is(err, tt.wantErr) || len(rs) != tt.wantCount || (rs == nil) != tt.wantNil {
+                              				t.Errorf("EvaluateBatchContext = %d results (nil %v), %v, want %d (nil %v), %v",
+                                          					len(rs), rs == nil, err, tt.wantCount, tt.wantNil, tt.wantErr)
+                              			}
+                    			if got := e.Stats()["total_evaluations"]; got != tt.wantEvals {
+                              				t.Errorf("total_evaluations = %v, want %d", got, tt.wantEvals)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,113 @@
+package ternary
+
+import "fmt"
+
+// Node is a vertex of a ternary expression DAG. Unlike Expr, children are
+// pointers and may be shared between parents; a shared node is evaluated
+// once per EvaluateDAG call. A node with an empty Rule is the constant Value.
+type Node struct {
+  	Rule     string
+  	Children []*Node
+  	Value    Trit
+  }
+
+// EvaluateDAG evaluates the DAG rooted at root, memoizing each node's value
+// by pointer identity, and records the root decision. A cycle, a nil node or
+// a failing rule yields an unrecorded UNKNOWN result describing the problem.
+func (e *Engine) EvaluateDAG(root *Node) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	if root == nil {
+      		return e.failedResult("DAG root is nil")
+      	}
+  	if root.Rule == "" {
+      		value, reason, ok := e.constantLocked(root.Value)
+      		if !ok {
+            			return e.failedResult(reason)
+            		}
+      		reason = fmt.Sprintf("Constant %s", tritName(value))
+      		return e.recordLocked(e.resultLocked("", 1.0, value, nil, reason))
+      	}
+
+  	memo := make(map[*Node]Trit)
+  	onPath := map[*Node]bool{root: true}
+  	inputs, failed, ok := e.dagInputsLocked(root, memo, onPath)
+  	if !ok {
+      		return failed
+      	}
+  	rule, failed, ok := e.ruleLocked(root.Rule)
+  	if !ok {
+      		return failed
+      	}
+
+  	inputs, value, note, ok := e.invokeLocked(root.Rule, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+  	reason := withNote(fmt.Sprintf("DAG[%s] evaluated %d distinct nodes", root.Rule, len(memo)+1), note)
+  	return e.recordLocked(e.resultLocked(root.Rule, rule.Weight, value, inputs, reason))
+  }
+
+// dagInputsLocked evaluates the children of n. onPath holds the nodes on
+// the current path from the root, for cycle detection. The caller must
+// hold e.mu.
+func (e *Engine) dagInputsLocked(n *Node, memo map[*Node]Trit, onPath map[*Node]bool) ([]Trit, TernaryResult, bool) {
+  	inputs := make([]Trit, len(n.Children))
+  	for i, child := range n.Children {
+      		v, failed, ok := e.dagValueLocked(child, memo, onPath)
+      		if !ok {
+            			return nil, failed, false
+            		}
+      		inputs[i] = v
+      	}
+  	return inputs, TernaryResult{}, true
+  }
+
+// dagValueLocked computes the value of n, reusing memo. The caller must
+// hold e.mu.
+func (e *Engine) dagValueLocked(n *Node, memo map[*Node]Trit, onPath map[*Node]bool) (Trit, TernaryResult, bool) {
+  	if n == nil {
+      		return UNKNOWN, e.failedResult("DAG contains a nil node"), false
+      	}
+  	if n.Rule == "" {
+      		return n.Value, TernaryResult{}, true
+      	}
+  	if v, ok := memo[n]; ok {
+      		return v, TernaryResult{}, true
+      	}
+  	if onPath[n] {
+      		return UNKNOWN, e.failedResult(fmt.Sprintf("Cycle in DAG through Rule[%s]", n.Rule)), false
+      	}
+
+  	rule, failed, ok := e.ruleLocked(n.Rule)
+  	if !ok {
+      		return UNKNOWN, failed, false
+      	}
+  	onPath[n] = true
+  	inputs, failed, ok := e.dagInputsLocked(n, memo, onPath)
+  	delete(onPath, n)
+  	if !ok {
+      		return UNKNOWN, failed, false
+      	}
+
+  	_, v, note, ok := e.invokeLocked(n.Rule, rule, inputs)
+  	if !ok {
+      		return UNKNOWN, e.failedResult(note), false
+      	}
+  	memo[n] = v
+  	return v, TernaryResult{}, true
+  }
+
+// failedResult returns an unrecorded UNKNOWN result explaining a failure
+func (e *Engine) failedResult(reason string) TernaryResult {
+  	return TernaryResult{
+      		ID:        e.newID(),
+      		Value:     UNKNOWN,
+      		Reason:    reason,
+      		Timestamp: e.clock(),
+      		EngineID:  e.engineID,
+      	}
+  }
@@ -0,0 +1,67 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateDAG(t *testing.T) {
+  	tests := []struct {
+      		name         string
+      		root         func() *Node
+      		want         Trit
+      		wantCalls    int
+      		wantReason   string
+      		wantRecorded bool
+      	}{
+      		{
+            			name: "shared node evaluated once",
+            			root: func() *Node {
+                    				shared := &Node{Rule: "COUNT", Children: []*Node{{Value: TRUE}}}
+                    				return &Node{Rule: "OR", Children: []*Node{
+                                          					{Rule: "NOT", Children: []*Node{shared}},
+                                          					{Rule: "OR", Children: []*Node{shared}},
+                                          				}}
+                    			},
+            			want: TRUE, wantCalls: 1, wantReason: "DAG[OR] evaluated 4 distinct nodes", wantRecorded: true,
+            		},
+      		{
+            			name: "unshared nodes evaluated each time",
+            			root: func() *Node {
+                    				return &Node{Rule: "AND", Children: []*Node{{Rule: "COUNT", Children: []*Node{{Value: TRUE}}}, {Rule: "COUNT", Children: []*Node{{Value: UNKNOWN}}}}}
+                    			},
+            			want: UNKNOWN, wantCalls: 2, wantReason: "evaluated 3 distinct nodes", wantRecorded: true,
+            		},
+      		{name: "constant root", root: func() *Node { return &Node{Value: FALSE} }, want: FALSE, wantReason: "Constant FALSE", wantRecorded: true},
+      		{name: "nil root", root: func() *Node { return nil }, want: UNKNOWN, wantReason: "DAG root is nil"},
+      		{name: "nil child", root: func() *Node { return &Node{Rule: "AND", Children: []*Node{nil}} }, want: UNKNOWN, wantReason: "nil node"},
+      		{name: "missing rule", root: func() *Node { return &Node{Rule: "AND", Children: []*Node{{Rule: "nope"}}} }, want: UNKNOWN, wantReason: "Rule 'nope' not found"},
+      		{
+            			name: "cycle",
+            			root: func() *Node {
+                    				a := &Node{Rule: "NOT"}
+                    				a.Children = []*Node{{Rule: "NOT", Children: []*Node{a}}}
+                    				return &Node{Rule: "AND", Children: []*Node{a}}
+                    			},
+            			want: UNKNOWN, wantReason: "Cycle in DAG through Rule[NOT]",
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			calls := 0
+                    			e.AddRule("COUNT", TernaryRule{Name: "COUNT", Weight: 1, Evaluate: func(in ...Trit) Trit {
+                                                        				calls++
+                                                        				return in[0]
+                                                        			}})
+                    			r := e.EvaluateDAG(tt.root())
+                    			if r.Value != tt.want || calls != tt.wantCalls || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateDAG = %v after %d calls (%q), want %v after %d calls containing %q",
+                                          					r.Value, calls, r.Reason, tt.want, tt.wantCalls, tt.wantReason)
+                              			}
+                    			if recorded := len(e.GetDecisions(DecisionFilter{})) == 1; recorded != tt.wantRecorded {
+                              				t.Errorf("recorded = %v, want %v", recorded, tt.wantRecorded)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,39 @@
+package ternary
+
+import "fmt"
+
+// deprecation marks a rule superseded by another
+type deprecation struct {
+  	replacement string
+  	warned      bool // logged once already
+  }
+
+// DeprecateRule marks a registered rule as deprecated in favour of
+// replacement and reports whether the rule exists. The rule keeps
+// evaluating normally, but its recorded results mention the replacement in
+// their Reason and the first such evaluation logs a warning. Removing the
+// rule clears the mark.
+func (e *Engine) DeprecateRule(name, replacement string) bool {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if _, exists := e.rules[name]; !exists {
+      		return false
+      	}
+  	e.deprecated[name] = &deprecation{replacement: replacement}
+  	return true
+  }
+
+// deprecationLocked annotates result if its rule is deprecated, warning
+// on first use. The caller must hold e.mu for writing.
+func (e *Engine) deprecationLocked(result *TernaryResult) {
+  	d := e.deprecated[result.Rule]
+  	if d == nil {
+      		return
+      	}
+  	result.Reason += fmt.Sprintf(" (deprecated, use %s)", d.replacement)
+  	if !d.warned {
+      		d.warned = true
+      		e.logger.Warn("ternary: deprecated rule evaluated", "rule", result.Rule, "replacement", d.replacement)
+      	}
+  }
@@ -0,0 +1,85 @@
+package ternary
+
+import (
+  	"bytes"
+  	"log/slog"
+  	"strings"
+  	"testing"
+  )
+
+func TestDeprecateRule(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		setup      func(e *Engine) bool
+      		wantOK     bool
+      		evals      int
+      		wantReason string
+      		wantWarns  int
+      	}{
+      		{
+            			name:       "deprecated",
+            			setup:      func(e *Engine) bool { return e.DeprecateRule("SQL_AND", "AND") },
+            			wantOK:     true,
+            			evals:      1,
+            			wantReason: "Rule[SQL_AND] evaluated 2 inputs (deprecated, use AND)",
+            			wantWarns:  1,
+            		},
+      		{
+            			name:       "warns once",
+            			setup:      func(e *Engine) bool { return e.DeprecateRule("SQL_AND", "AND") },
+            			wantOK:     true,
+            			evals:      3,
+            			wantReason: "(deprecated, use AND)",
+            			wantWarns:  1,
+            		},
+      		{
+            			name:       "redeprecated",
+            			setup:      func(e *Engine) bool { e.DeprecateRule("SQL_AND", "AND"); return e.DeprecateRule("SQL_AND", "MIN") },
+            			wantOK:     true,
+            			evals:      1,
+            			wantReason: "(deprecated, use MIN)",
+            			wantWarns:  1,
+            		},
+      		{name: "missing rule", setup: func(e *Engine) bool { return e.DeprecateRule("nope", "AND") }, evals: 1},
+      		{
+            			name: "removal clears the mark",
+            			setup: func(e *Engine) bool {
+                    				e.DeprecateRule("SQL_AND", "AND")
+                    				rule := e.rules["SQL_AND"]
+                    				e.RemoveRule("SQL_AND")
+                    				e.AddRule("SQL_AND", rule)
+                    				return true
+                    			},
+            			wantOK: true,
+            			evals:  1,
+            		},
+      		{
+            			name: "removed rule",
+            			setup: func(e *Engine) bool {
+                    				e.RemoveRule("SQL_AND")
+                    				return e.DeprecateRule("SQL_AND", "AND")
+                    			},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			var log bytes.Buffer
+                    			e := NewEngine(WithLogger(slog.New(slog.NewTextHandler(&log, nil))))
+                    			if ok := tt.setup(e); ok != tt.wantOK {
+                              				t.Errorf("DeprecateRule = %v, want %v", ok, tt.wantOK)
+                              			}
+                    			for i := 0; i < tt.evals; i++ {
+                              				r := e.Evaluate("SQL_AND", TRUE, FALSE)
+                              				if r.Value != FALSE || strings.Contains(r.Reason, "deprecated") != (tt.wantReason != "") || !strings.Contains(r.Reason, tt.wantReason) {
+                                          					t.Errorf("SQL_AND = %v %q, want FALSE containing %q", r.Value, r.Reason, tt.wantReason)
+                                          				}
+                              			}
+                    			if warns := strings.Count(log.String(), "level=WARN"); warns != tt.wantWarns {
+                              				t.Errorf("logged %d warnings, want %d:\n%s", warns, tt.wantWarns, log.String())
+                              			}
+                    			if tt.wantWarns > 0 && !strings.Contains(log.String(), "rule=SQL_AND") {
+                              				t.Errorf("warning does not name the rule:\n%s", log.String())
+                              			}
+                    		})
+      	}
+  }
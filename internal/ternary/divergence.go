@@ -0,0 +1,52 @@
+package ternary
+
+import "fmt"
+
+// StreamDivergence returns the fraction of positions at which two
+// equally long decision streams have different Values, from 0 for
+// identical behaviour to 1 for disagreement everywhere. Empty streams give
+// 0. Streams of different lengths give -1; use StreamDivergenceE for an
+// error instead.
+func StreamDivergence(a, b []TernaryResult) float64 {
+  	d, err := StreamDivergenceE(a, b)
+  	if err != nil {
+      		return -1
+      	}
+  	return d
+  }
+
+// StreamDivergenceE is StreamDivergence returning an error for streams of
+// different lengths
+func StreamDivergenceE(a, b []TernaryResult) (float64, error) {
+  	return streamDivergence(a, b, func(x, y Trit) float64 { return 1 })
+  }
+
+// WeightedStreamDivergence is StreamDivergenceE counting a TRUE↔FALSE flip
+// double a flip to or from UNKNOWN, scaled so that fully opposite definite
+// streams still give 1
+func WeightedStreamDivergence(a, b []TernaryResult) (float64, error) {
+  	return streamDivergence(a, b, func(x, y Trit) float64 {
+            		if x == UNKNOWN || y == UNKNOWN {
+                    			return 0.5
+                    		}
+            		return 1
+            	})
+  }
+
+// streamDivergence averages cost over the positions where a and b differ
+func streamDivergence(a, b []TernaryResult, cost func(x, y Trit) float64) (float64, error) {
+  	if len(a) != len(b) {
+      		return 0, fmt.Errorf("ternary: streams of %d and %d decisions", len(a), len(b))
+      	}
+  	if len(a) == 0 {
+      		return 0, nil
+      	}
+
+  	var sum float64
+  	for i := range a {
+      		if x, y := a[i].Value, b[i].Value; x != y {
+            			sum += cost(x, y)
+            		}
+      	}
+  	return sum / float64(len(a)), nil
+  }
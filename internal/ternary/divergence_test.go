@@ -0,0 +1,46 @@
+package ternary
+
+import "testing"
+
+// stream returns results with the given values
+func stream(values ...Trit) []TernaryResult {
+  	out := make([]TernaryResult, len(values))
+  	for i, v := range values {
+      		out[i].Value = v
+      	}
+  	return out
+  }
+
+func TestStreamDivergence(t *testing.T) {
+  	tests := []struct {
+      		name         string
+      		a, b         []TernaryResult
+      		want         float64
+      		wantWeighted float64
+      		wantErr      bool
+      	}{
+      		{name: "empty", want: 0, wantWeighted: 0},
+      		{name: "identical", a: stream(TRUE, FALSE, UNKNOWN), b: stream(TRUE, FALSE, UNKNOWN), want: 0, wantWeighted: 0},
+      		{name: "opposite", a: stream(TRUE, FALSE), b: stream(FALSE, TRUE), want: 1, wantWeighted: 1},
+      		{name: "to UNKNOWN", a: stream(TRUE, FALSE), b: stream(UNKNOWN, UNKNOWN), want: 1, wantWeighted: 0.5},
+      		{name: "mixed", a: stream(TRUE, FALSE, UNKNOWN, TRUE), b: stream(FALSE, TRUE, TRUE, FALSE), want: 1, wantWeighted: 0.875},
+      		{name: "one in four", a: stream(TRUE, TRUE, TRUE, TRUE), b: stream(TRUE, TRUE, TRUE, FALSE), want: 0.25, wantWeighted: 0.25},
+      		{name: "length mismatch", a: stream(TRUE), b: stream(TRUE, TRUE), want: -1, wantErr: true},
+      		{name: "one empty", a: stream(TRUE), wantErr: true, want: -1},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := StreamDivergence(tt.a, tt.b); got != tt.want {
+                              				t.Errorf("StreamDivergence = %v, want %v", got, tt.want)
+                              			}
+                    			d, err := StreamDivergenceE(tt.a, tt.b)
+                    			if (err != nil) != tt.wantErr || (!tt.wantErr && d != tt.want) {
+                              				t.Errorf("StreamDivergenceE = %v, %v, want %v, error %v", d, err, tt.want, tt.wantErr)
+                              			}
+                    			w, err := WeightedStreamDivergence(tt.a, tt.b)
+                    			if (err != nil) != tt.wantErr || (!tt.wantErr && w != tt.wantWeighted) {
+                              				t.Errorf("WeightedStreamDivergence = %v, %v, want %v, error %v", w, err, tt.wantWeighted, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,23 @@
+package ternary
+
+// EdgeDetector reports transitions in a trit stream by evaluating the EDGE
+// rule over each value and the one before it. The value before the first
+// is UNKNOWN, so the first Feed returns UNKNOWN. An EdgeDetector is not
+// safe for concurrent use.
+type EdgeDetector struct {
+  	engine *Engine
+  	prev   Trit
+  }
+
+// NewEdgeDetector returns an EdgeDetector evaluating on e
+func (e *Engine) NewEdgeDetector() *EdgeDetector {
+  	return &EdgeDetector{engine: e, prev: UNKNOWN}
+  }
+
+// Feed evaluates EDGE over the previous value and v, records the decision
+// and makes v the previous value
+func (d *EdgeDetector) Feed(v Trit) TernaryResult {
+  	result := d.engine.Evaluate("EDGE", d.prev, v)
+  	d.prev = v
+  	return result
+  }
@@ -0,0 +1,54 @@
+package ternary
+
+import "testing"
+
+func TestEdgeRule(t *testing.T) {
+  	tests := []struct {
+      		prev, cur, want Trit
+      	}{
+      		{FALSE, FALSE, FALSE},
+      		{TRUE, TRUE, FALSE},
+      		{FALSE, TRUE, TRUE},
+      		{TRUE, FALSE, TRUE},
+      		{UNKNOWN, TRUE, UNKNOWN},
+      		{FALSE, UNKNOWN, UNKNOWN},
+      		{UNKNOWN, UNKNOWN, UNKNOWN},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("EDGE", tt.prev, tt.cur).Value; got != tt.want {
+            			t.Errorf("EDGE(%v, %v) = %v, want %v", tt.prev, tt.cur, got, tt.want)
+            		}
+      	}
+  }
+
+func TestEdgeDetector(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		stream []Trit
+      		want   []Trit
+      	}{
+      		{name: "first value", stream: []Trit{TRUE}, want: []Trit{UNKNOWN}},
+      		{name: "steady", stream: []Trit{FALSE, FALSE, FALSE}, want: []Trit{UNKNOWN, FALSE, FALSE}},
+      		{name: "toggling", stream: []Trit{TRUE, FALSE, TRUE}, want: []Trit{UNKNOWN, TRUE, TRUE}},
+      		{
+            			name:   "gap",
+            			stream: []Trit{TRUE, TRUE, FALSE, FALSE, UNKNOWN, TRUE, TRUE, FALSE},
+            			want:   []Trit{UNKNOWN, FALSE, TRUE, FALSE, UNKNOWN, UNKNOWN, FALSE, TRUE},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			d := e.NewEdgeDetector()
+                    			for i, v := range tt.stream {
+                              				if r := d.Feed(v); r.Value != tt.want[i] || r.Rule != "EDGE" {
+                                          					t.Errorf("Feed #%d (%v) = %v from %q, want %v", i, v, r.Value, r.Rule, tt.want[i])
+                                          				}
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{})); n != len(tt.stream) {
+                              				t.Errorf("recorded %d decisions, want %d", n, len(tt.stream))
+                              			}
+                    		})
+      	}
+  }
@@ -2,6 +2,8 @@ package ternary
 
 import (
   	"fmt"
+  	"log/slog"
+  	"math"
   	"sync"
   	"time"
 
@@ -55,21 +57,87 @@ func (t Trit) Confidence() float64 {
 
 // TernaryResult holds a decision result with metadata
 type TernaryResult struct {
-  	ID         string    `json:"id"`
-  	Value      Trit      `json:"value"`
-  	Confidence float64   `json:"confidence"`
-  	Reason     string    `json:"reason"`
-  	Timestamp  time.Time `json:"timestamp"`
-  	Depth      int       `json:"depth"` // recursive evaluation depth
+  	ID         string            `json:"id"`
+  	Rule       string            `json:"rule,omitempty"`
+  	Value      Trit              `json:"value"`
+  	Confidence float64           `json:"confidence"`
+  	Reason     string            `json:"reason"`
+  	Timestamp  time.Time         `json:"timestamp"`
+  	Depth      int               `json:"depth"` // recursive evaluation depth
+  	InputCount int               `json:"input_count"`
+  	Inputs     []Trit            `json:"inputs,omitempty"` // only with input capture
+  	Meta       map[string]string `json:"meta,omitempty"`
+  	EngineID   string            `json:"engine_id,omitempty"` // set by WithEngineID
+
+  	// Labels maps each input label to its value for EvaluateNamed
+  	Labels map[string]Trit `json:"labels,omitempty"`
+
+  	// RawConfidence is the confidence before clamping to the engine's
+  	// bounds, e.g. 2.0 for a TRUE from a rule of weight 2. It is only set
+  	// with WithRawConfidence or SetRawConfidence.
+  	RawConfidence float64 `json:"raw_confidence,omitempty"`
+  }
+
+// Score returns the result as a signed value in [-1, 1]: +Confidence for
+// TRUE, -Confidence for FALSE and 0 for UNKNOWN
+func (r TernaryResult) Score() float64 {
+  	switch r.Value {
+      	case TRUE:
+      		return r.Confidence
+      	case FALSE:
+      		return -r.Confidence
+      	default:
+      		return 0
+      	}
   }
 
 // Engine is the ternary logic evaluation engine
 type Engine struct {
-  	mu          sync.RWMutex
-  	decisions   []TernaryResult
-  	rules       map[string]TernaryRule
-  	evalCount   uint64
-  	truthTable  map[string]Trit
+  	mu         sync.RWMutex
+  	decisions  []TernaryResult
+  	head       int    // index of the oldest decision once the history is full
+  	lifetime   uint64 // decisions ever recorded
+  	rules      map[string]TernaryRule
+  	evalCount  uint64
+  	truthTable map[string]Trit
+  	tieBreak   TieBreak
+  	disabled   map[string]bool
+  	groups     map[string][]string
+  	ruleIndex  []string // sorted rule names, rebuilt lazily; nil when stale
+
+  	onlyOnChange bool
+  	lastValue    map[string]Trit // last recorded value per rule
+  	emptyResult  *Trit           // overrides rules on empty input when set
+  	once         map[string]TernaryResult
+  	crossings    map[string][]confidenceCross
+  	lastResult   map[string]TernaryResult // last result per watched rule
+  	capture      bool                     // copy inputs into results
+  	evolve       EvolveConfig
+  	wal          *walLog // write-ahead log of recorded decisions, if enabled
+  	walSync      bool    // fsync after every WAL record
+  	sampleEvery  uint64  // record one decision in sampleEvery; 0 or 1 records all
+  	sampleSeen   uint64  // decisions offered to the sampler since SetSampleRate
+  	confMin      float64 // result confidence floor
+  	confMax      float64 // result confidence ceiling
+  	typed        map[string]TypedRuleSpec
+  	maxTreeDepth int // EvaluateTree depth limit
+  	ruleStats    map[string]*ruleCounter
+  	scores       map[string]RuleScore // feedback per rule
+  	unknownBlock float64              // WEIGHTED_CONSENSUS abstention limit
+  	clock        func() time.Time     // timestamps results
+  	newID        func() string        // identifies results
+  	engineID     string               // copied into every result
+  	confHist     confidenceHistogram  // confidence of every decision
+  	deprecated   map[string]*deprecation
+  	logger       *slog.Logger
+  	version      uint64        // bumped by every change to history or scorecard
+  	invalidMode  InvalidInputs // policy for input trits that are not IsValid
+  	rawConf      bool          // set RawConfidence in results
+  	optErr       error         // first error from an Option
+
+  	// Metrics counters, which must never go down
+  	ruleTotals  map[string]uint64 // decisions per rule, not cleared by ResetStats
+  	evalDropped uint64            // evaluations Restore took off evalCount
   }
 
 // TernaryRule defines a named ternary evaluation rule
@@ -77,58 +145,151 @@ type TernaryRule struct {
   	Name     string
   	Evaluate func(inputs ...Trit) Trit
   	Weight   float64
+
+  	// Arity, when positive, is the exact number of inputs the rule takes.
+  	// Other input counts evaluate to UNKNOWN without calling Evaluate.
+  	Arity int
+
+  	// Weighted, if set, evaluates the rule over per-input weights for
+  	// EvaluateWeighted. Rules without it do not accept weights.
+  	Weighted func(inputs []Trit, weights []float64) Trit
+
+  	// Tags are free-form labels for grouping and configuring rules
+  	Tags []string
+
+  	// NotThreadSafe marks a rule whose Evaluate keeps mutable state. Rules
+  	// are assumed thread-safe by default; every call the engine makes to a
+  	// NotThreadSafe rule, including those of read-only paths such as
+  	// ConcurrentEvaluate, AuditRules and SynthesizeRule, is serialized with
+  	// the rule's own mutex.
+  	NotThreadSafe bool
+
+  	// Cacheable lets Evaluate answer from the truth table: when an entry
+  	// for TruthKey(name, inputs...) was set with Memoize, it is used
+  	// instead of calling Evaluate.
+  	Cacheable bool
+
+  	mu          *sync.Mutex            // set by AddRule for NotThreadSafe rules
+  	incremental func() func(Trit) Trit // running evaluator for streams and sequences
+  }
+
+// NewEngine creates a new ternary logic engine retaining the last
+// DefaultHistoryCapacity decisions. It panics if an option fails, such as
+// WithLogicSystem with an unknown system; use NewEngineWithCapacity to get
+// the error instead.
+func NewEngine(opts ...Option) *Engine {
+  	e := newEngine(DefaultHistoryCapacity, opts)
+  	if e.optErr != nil {
+      		panic(e.optErr)
+      	}
+  	return e
+  }
+
+// NewEngineWithCapacity creates a new ternary logic engine whose history
+// keeps the last n decisions, overwriting the oldest once full. It returns
+// an error if n is not positive or an option fails.
+func NewEngineWithCapacity(n int, opts ...Option) (*Engine, error) {
+  	if n < 1 {
+      		return nil, fmt.Errorf("ternary: history capacity %d must be positive", n)
+      	}
+  	e := newEngine(n, opts)
+  	if e.optErr != nil {
+      		return nil, e.optErr
+      	}
+  	return e, nil
   }
 
-// NewEngine creates a new ternary logic engine
-func NewEngine() *Engine {
+// newEngine creates an engine with history capacity n and applies opts,
+// leaving the first option error in e.optErr
+func newEngine(n int, opts []Option) *Engine {
   	e := &Engine{
-      		decisions:  make([]TernaryResult, 0, 1024),
-      		rules:      make(map[string]TernaryRule),
-      		truthTable: make(map[string]Trit),
+      		decisions:    make([]TernaryResult, 0, n),
+      		rules:        make(map[string]TernaryRule),
+      		truthTable:   make(map[string]Trit),
+      		disabled:     make(map[string]bool),
+      		groups:       make(map[string][]string),
+      		lastValue:    make(map[string]Trit),
+      		once:         make(map[string]TernaryResult),
+      		crossings:    make(map[string][]confidenceCross),
+      		lastResult:   make(map[string]TernaryResult),
+      		typed:        make(map[string]TypedRuleSpec),
+      		ruleStats:    make(map[string]*ruleCounter),
+      		ruleTotals:   make(map[string]uint64),
+      		scores:       make(map[string]RuleScore),
+      		deprecated:   make(map[string]*deprecation),
+      		logger:       slog.Default(),
+      		unknownBlock: DefaultUnknownBlock,
+      		clock:        time.Now,
+      		newID:        newUUID,
+      		evolve:       DefaultEvolveConfig(),
+      		confMin:      math.Inf(-1),
+      		confMax:      1.0,
+      		maxTreeDepth: DefaultMaxTreeDepth,
       	}
   	e.registerDefaultRules()
+  	for _, opt := range opts {
+      		opt(e)
+      	}
   	return e
   }
 
 // registerDefaultRules sets up the fundamental ternary operations
 func (e *Engine) registerDefaultRules() {
-  	// Ternary AND (Kleene strong)
-  	e.rules["AND"] = TernaryRule{
-      		Name: "AND",
+  	e.registerLogicRules(LogicDefault)
+
+  	// EDGE — change detection over (previous, current): TRUE on a change,
+  	// FALSE when steady, UNKNOWN if either side is UNKNOWN
+  	e.rules["EDGE"] = TernaryRule{
+      		Name: "EDGE",
       		Evaluate: func(inputs ...Trit) Trit {
-            			result := TRUE
-            			for _, inp := range inputs {
-                    				result = tritMin(result, inp)
+            			if len(inputs) != 2 || inputs[0] == UNKNOWN || inputs[1] == UNKNOWN {
+                    				return UNKNOWN
                     			}
-            			return result
+            			if inputs[0] != inputs[1] {
+                    				return TRUE
+                    			}
+            			return FALSE
             		},
       		Weight: 1.0,
+      		Arity:  2,
       	}
 
-  	// Ternary OR (Kleene strong)
-  	e.rules["OR"] = TernaryRule{
-      		Name: "OR",
+  	// XOR — Kleene exclusive or, NOT(EQ), folded pairwise from FALSE:
+  	// XOR(a, b, c) = XOR(XOR(a, b), c). Over definite inputs it is TRUE
+  	// when an odd number of them are TRUE (parity, not "exactly one");
+  	// any UNKNOWN input makes it UNKNOWN.
+  	e.rules["XOR"] = TernaryRule{
+      		Name: "XOR",
       		Evaluate: func(inputs ...Trit) Trit {
             			result := FALSE
             			for _, inp := range inputs {
-                    				result = tritMax(result, inp)
+                    				result = tritXor(result, inp)
                     			}
             			return result
             		},
-      		Weight: 1.0,
+      		Weight:      1.0,
+      		incremental: foldStream(FALSE, tritXor),
       	}
 
-  	// Ternary NOT (Lukasiewicz)
-  	e.rules["NOT"] = TernaryRule{
-      		Name: "NOT",
+  	// XNOR / EQ — Kleene equivalence folded pairwise from TRUE:
+  	// EQ(a, b, c) = EQ(EQ(a, b), c). Beyond two inputs this is parity too,
+  	// TRUE when an even number of inputs are FALSE, so EQ(FALSE, FALSE,
+  	// FALSE) is FALSE; use UNANIMOUS to test that all inputs agree.
+  	equivalence := TernaryRule{
+      		Name: "XNOR",
       		Evaluate: func(inputs ...Trit) Trit {
-            			if len(inputs) == 0 {
-                    				return UNKNOWN
+            			result := TRUE
+            			for _, inp := range inputs {
+                    				result = tritEq(result, inp)
                     			}
-            			return tritNeg(inputs[0])
+            			return result
             		},
-      		Weight: 1.0,
+      		Weight:      1.0,
+      		incremental: foldStream(TRUE, tritEq),
       	}
+  	e.rules["XNOR"] = equivalence
+  	equivalence.Name = "EQ"
+  	e.rules["EQ"] = equivalence
 
   	// CONSENSUS — requires majority agreement
   	e.rules["CONSENSUS"] = TernaryRule{
@@ -137,6 +298,11 @@ func (e *Engine) registerDefaultRules() {
             			if len(inputs) == 0 {
                     				return UNKNOWN
                     			}
+            			if len(inputs) == 3 {
+                    				if m, ok := median3(inputs[0], inputs[1], inputs[2]); ok {
+                              					return m
+                              				}
+                    			}
             			trueCount, falseCount, unknownCount := 0, 0, 0
             			for _, inp := range inputs {
                     				switch inp {
@@ -157,7 +323,66 @@ func (e *Engine) registerDefaultRules() {
                     			}
             			return UNKNOWN
             		},
-      		Weight: 1.5,
+      		Weighted: func(inputs []Trit, weights []float64) Trit {
+            			votes := make([]WeightedTrit, len(inputs))
+            			for i, inp := range inputs {
+                    				votes[i] = WeightedTrit{Value: inp, Weight: weights[i]}
+                    			}
+            			return weightedMajority(votes)
+            		},
+      		Weight:      1.5,
+      		incremental: consensusStream,
+      	}
+
+  	// UNANIMOUS — commits only when every input agrees on a definite value
+  	e.rules["UNANIMOUS"] = TernaryRule{
+      		Name: "UNANIMOUS",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			if len(inputs) == 0 {
+                    				return UNKNOWN
+                    			}
+            			first := inputs[0]
+            			for _, inp := range inputs[1:] {
+                    				if inp != first {
+                              					return UNKNOWN
+                              				}
+                    			}
+            			if first != TRUE && first != FALSE {
+                    				return UNKNOWN
+                    			}
+            			return first
+            		},
+      		Weight:      1.0,
+      		incremental: unanimousStream,
+      	}
+
+  	// PLURALITY — most frequent value, ties resolved by the engine's TieBreak
+  	e.rules["PLURALITY"] = TernaryRule{
+      		Name: "PLURALITY",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			return Plurality(e.tieBreak, inputs...)
+            		},
+      		Weight: 1.0,
+      	}
+
+  	// HAS_UNKNOWN — TRUE when any input is indeterminate (data incomplete)
+  	e.rules["HAS_UNKNOWN"] = TernaryRule{
+      		Name: "HAS_UNKNOWN",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			return hasUnknown(inputs)
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(FALSE, anyUnknown),
+      	}
+
+  	// ALL_KNOWN — complement of HAS_UNKNOWN
+  	e.rules["ALL_KNOWN"] = TernaryRule{
+      		Name: "ALL_KNOWN",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			return tritNeg(hasUnknown(inputs))
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(TRUE, allKnown),
       	}
 
   	// EVOLVE — biased toward action when uncertain
@@ -171,56 +396,340 @@ func (e *Engine) registerDefaultRules() {
                               				}
                     			}
             			// If more than 30% unknown, lean toward TRUE (action bias)
-            			if float64(unknowns)/float64(len(inputs)) > 0.3 {
+            			if float64(unknowns)/float64(len(inputs)) > e.evolve.Threshold {
                     				return TRUE
                     			}
-            			return e.rules["CONSENSUS"].Evaluate(inputs...)
+            			return e.fallbackLocked(e.evolve.FallbackChain, inputs)
             		},
       		Weight: 2.0,
       	}
+
+  	// EVOLVE_WEIGHTED — action bias damped by the confidence of known votes
+  	e.rules["EVOLVE_WEIGHTED"] = TernaryRule{
+      		Name: "EVOLVE_WEIGHTED",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			votes := make([]WeightedTrit, len(inputs))
+            			for i, inp := range inputs {
+                    				votes[i] = WeightedTrit{Value: inp, Weight: 1.0}
+                    			}
+            			return evolveWeighted(votes)
+            		},
+      		Weighted: func(inputs []Trit, weights []float64) Trit {
+            			votes := make([]WeightedTrit, len(inputs))
+            			for i, inp := range inputs {
+                    				votes[i] = WeightedTrit{Value: inp, Weight: weights[i]}
+                    			}
+            			return evolveWeighted(votes)
+            		},
+      		Weight: 2.0,
+      	}
+
+  	// WEIGHTED_CONSENSUS — majority by weight; see weightedConsensus
+  	e.rules["WEIGHTED_CONSENSUS"] = TernaryRule{
+      		Name: "WEIGHTED_CONSENSUS",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			weights := make([]float64, len(inputs))
+            			for i := range weights {
+                    				weights[i] = 1.0
+                    			}
+            			return weightedConsensus(e.unknownBlock, inputs, weights)
+            		},
+      		Weighted: func(inputs []Trit, weights []float64) Trit {
+            			return weightedConsensus(e.unknownBlock, inputs, weights)
+            		},
+      		Weight: 1.5,
+      	}
+
+  	// SQL_AND, SQL_OR, SQL_NOT — SQL three-valued logic with NULL as UNKNOWN.
+  	// The standard's truth tables are exactly Kleene strong logic, so these
+  	// agree with AND, OR and NOT on every input; they exist to name the
+  	// intent. The one divergence is outside the operators: a WHERE clause
+  	// keeps only TRUE rows, so callers filtering on a result must treat
+  	// UNKNOWN as not-true rather than as FALSE.
+  	e.rules["SQL_AND"] = TernaryRule{
+      		Name: "SQL_AND",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			// FALSE AND NULL = FALSE, TRUE AND NULL = NULL
+            			result := TRUE
+            			for _, inp := range inputs {
+                    				result = tritMin(result, inp)
+                    			}
+            			return result
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(TRUE, tritMin),
+      	}
+
+  	e.rules["SQL_OR"] = TernaryRule{
+      		Name: "SQL_OR",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			// TRUE OR NULL = TRUE, FALSE OR NULL = NULL
+            			result := FALSE
+            			for _, inp := range inputs {
+                    				result = tritMax(result, inp)
+                    			}
+            			return result
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(FALSE, tritMax),
+      	}
+
+  	e.rules["SQL_NOT"] = TernaryRule{
+      		Name: "SQL_NOT",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			// NOT NULL = NULL
+            			if len(inputs) == 0 {
+                    				return UNKNOWN
+                    			}
+            			return tritNeg(inputs[0])
+            		},
+      		Weight: 1.0,
+      	}
   }
 
 // Evaluate processes a decision through the ternary engine
 func (e *Engine) Evaluate(ruleName string, inputs ...Trit) TernaryResult {
   	e.mu.Lock()
   	defer e.mu.Unlock()
+  	return e.evaluateLocked(ruleName, inputs)
+  }
+
+// EvaluateWeight evaluates a rule using weight instead of the rule's
+// registered Weight. The override applies to this call only.
+func (e *Engine) EvaluateWeight(ruleName string, weight float64, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+  	rule.Weight = weight
+  	result, _ := e.applyLocked(ruleName, rule, inputs)
+  	return result
+  }
+
+// EvaluateMeta evaluates a rule and attaches a copy of meta, e.g. a request
+// ID or tenant, to the recorded result
+func (e *Engine) EvaluateMeta(meta map[string]string, ruleName string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
 
   	e.evalCount++
 
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		failed.Meta = copyMeta(meta)
+      		return failed
+      	}
+  	inputs, value, note, ok := e.invokeLocked(ruleName, rule, inputs)
+  	if !ok {
+      		failed = e.failedResult(note)
+      		failed.Meta = copyMeta(meta)
+      		return failed
+      	}
+  	result := e.resultLocked(ruleName, rule.Weight, value, inputs, ruleReason(ruleName, inputs, note))
+  	result.Meta = copyMeta(meta)
+  	return e.recordLocked(result)
+  }
+
+// copyMeta returns a copy of meta, or nil when it is empty
+func copyMeta(meta map[string]string) map[string]string {
+  	if len(meta) == 0 {
+      		return nil
+      	}
+  	out := make(map[string]string, len(meta))
+  	for k, v := range meta {
+      		out[k] = v
+      	}
+  	return out
+  }
+
+// copyLabels returns a copy of labels, or nil when it is empty
+func copyLabels(labels map[string]Trit) map[string]Trit {
+  	if len(labels) == 0 {
+      		return nil
+      	}
+  	out := make(map[string]Trit, len(labels))
+  	for k, v := range labels {
+      		out[k] = v
+      	}
+  	return out
+  }
+
+// EvaluateScore evaluates a rule and returns only the result's Score
+func (e *Engine) EvaluateScore(ruleName string, inputs ...Trit) float64 {
+  	return e.Evaluate(ruleName, inputs...).Score()
+  }
+
+// evaluateLocked looks up ruleName and evaluates it. The caller must hold e.mu.
+func (e *Engine) evaluateLocked(ruleName string, inputs []Trit) TernaryResult {
+  	result, _ := e.tryEvaluateLocked(ruleName, inputs)
+  	return result
+  }
+
+// tryEvaluateLocked is evaluateLocked also reporting whether the rule could
+// be run at all. The caller must hold e.mu.
+func (e *Engine) tryEvaluateLocked(ruleName string, inputs []Trit) (TernaryResult, bool) {
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed, false
+      	}
+  	return e.applyLocked(ruleName, rule, inputs)
+  }
+
+// ruleLocked returns the named rule if it is registered and enabled, and
+// otherwise the UNKNOWN result explaining why not. The caller must hold e.mu.
+func (e *Engine) ruleLocked(ruleName string) (TernaryRule, TernaryResult, bool) {
   	rule, exists := e.rules[ruleName]
   	if !exists {
-      		return TernaryResult{
-            			ID:         uuid.New().String(),
-            			Value:      UNKNOWN,
-            			Confidence: 0.0,
-            			Reason:     fmt.Sprintf("Rule '%s' not found", ruleName),
-            			Timestamp:  time.Now(),
-            		}
+      		return TernaryRule{}, e.ruleNotFound(ruleName), false
       	}
+  	if e.disabled[ruleName] {
+      		return TernaryRule{}, e.failedResult(fmt.Sprintf("Rule '%s' disabled", ruleName)), false
+      	}
+  	return rule, TernaryResult{}, true
+  }
 
-  	value := rule.Evaluate(inputs...)
-  	confidence := value.Confidence() * rule.Weight
-  	if confidence > 1.0 {
-      		confidence = 1.0
+// applyLocked runs rule over inputs and records the decision. It reports
+// false, recording nothing, if the inputs are rejected as invalid.
+// The caller must hold e.mu.
+func (e *Engine) applyLocked(ruleName string, rule TernaryRule, inputs []Trit) (TernaryResult, bool) {
+  	inputs, value, note, ok := e.invokeLocked(ruleName, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note), false
       	}
+  	return e.recordLocked(e.resultLocked(ruleName, rule.Weight, value, inputs, ruleReason(ruleName, inputs, note))), true
+  }
+
+// previewLocked evaluates ruleName like Evaluate but records nothing and
+// leaves the counters alone. The caller must hold e.mu.
+func (e *Engine) previewLocked(ruleName string, inputs []Trit) (TernaryResult, bool) {
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed, false
+      	}
+  	inputs, value, note, ok := e.invokeLocked(ruleName, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note), false
+      	}
+  	return e.resultLocked(ruleName, rule.Weight, value, inputs, ruleReason(ruleName, inputs, note)), true
+  }
+
+// ruleReason returns the Reason of an evaluation of ruleName over inputs
+// given the note from ruleValueLocked
+func ruleReason(ruleName string, inputs []Trit, note string) string {
+  	if note != "" {
+      		return note
+      	}
+  	return fmt.Sprintf("Rule[%s] evaluated %d inputs", ruleName, len(inputs))
+  }
+
+// withNote appends the note from ruleValueLocked, if any, to reason
+func withNote(reason, note string) string {
+  	if note == "" {
+      		return reason
+      	}
+  	return reason + "; " + note
+  }
 
+// computeLocked runs rule over inputs without recording anything, holding
+// the rule's mutex if it is NotThreadSafe. The caller must hold e.mu, for
+// reading at least.
+func (e *Engine) computeLocked(rule TernaryRule, inputs []Trit) Trit {
+  	if rule.Arity > 0 && len(inputs) != rule.Arity {
+      		return UNKNOWN
+      	}
+  	if len(inputs) == 0 && e.emptyResult != nil {
+      		return *e.emptyResult
+      	}
+  	if rule.mu != nil {
+      		rule.mu.Lock()
+      		defer rule.mu.Unlock()
+      	}
+  	return rule.Evaluate(inputs...)
+  }
+
+// weightedLocked runs the Weighted function of rule, holding the rule's
+// mutex if it is NotThreadSafe. The caller must hold e.mu.
+func (e *Engine) weightedLocked(rule TernaryRule, inputs []Trit, weights []float64) Trit {
+  	if rule.mu != nil {
+      		rule.mu.Lock()
+      		defer rule.mu.Unlock()
+      	}
+  	return rule.Weighted(inputs, weights)
+  }
+
+// resultLocked builds the result for value produced by a rule of the given
+// weight. The caller must hold e.mu.
+func (e *Engine) resultLocked(ruleName string, weight float64, value Trit, inputs []Trit, reason string) TernaryResult {
   	result := TernaryResult{
-      		ID:         uuid.New().String(),
+      		ID:         e.newID(),
+      		Rule:       ruleName,
       		Value:      value,
-      		Confidence: confidence,
-      		Reason:     fmt.Sprintf("Rule[%s] evaluated %d inputs", ruleName, len(inputs)),
-      		Timestamp:  time.Now(),
+      		Reason:     reason,
+      		Timestamp:  e.clock(),
+      		InputCount: len(inputs),
+      		EngineID:   e.engineID,
+      	}
+  	e.setConfidenceLocked(&result, value.Confidence()*weight)
+  	if e.capture {
+      		result.Inputs = append([]Trit(nil), inputs...)
       	}
+  	return result
+  }
+
+// recordLocked appends result to the decision history and returns it.
+// The caller must hold e.mu.
+func (e *Engine) recordLocked(result TernaryResult) TernaryResult {
+  	e.deprecationLocked(&result)
+  	e.countLocked(result)
+  	e.notifyCrossingsLocked(result)
 
-  	e.decisions = append(e.decisions, result)
+  	if e.onlyOnChange {
+      		if last, seen := e.lastValue[result.Rule]; seen && last == result.Value {
+            			return result
+            		}
+      	}
+  	e.lastValue[result.Rule] = result.Value
+  	if !e.sampleLocked() {
+      		return result
+      	}
+  	e.writeWALLocked(result)
+  	e.appendLocked(result)
   	return result
   }
 
+// newUUID returns a random UUID string, the default result ID
+func newUUID() string {
+  	return uuid.New().String()
+  }
+
+// ruleNotFound builds the result returned for an unregistered rule name
+func (e *Engine) ruleNotFound(ruleName string) TernaryResult {
+  	return TernaryResult{
+      		ID:         e.newID(),
+      		Value:      UNKNOWN,
+      		Confidence: 0.0,
+      		Reason:     fmt.Sprintf("Rule '%s' not found", ruleName),
+      		Timestamp:  e.clock(),
+      		EngineID:   e.engineID,
+      	}
+  }
+
 // AddRule registers a custom ternary rule
 func (e *Engine) AddRule(name string, rule TernaryRule) {
   	e.mu.Lock()
   	defer e.mu.Unlock()
+  	if rule.NotThreadSafe && rule.mu == nil {
+      		rule.mu = new(sync.Mutex)
+      	}
   	e.rules[name] = rule
+  	e.ruleIndex = nil
   }
 
 // Stats returns engine statistics
@@ -228,27 +737,61 @@ func (e *Engine) Stats() map[string]interface{} {
   	e.mu.RLock()
   	defer e.mu.RUnlock()
   	return map[string]interface{}{
-      		"total_evaluations": e.evalCount,
-      		"total_decisions":   len(e.decisions),
-      		"registered_rules":  len(e.rules),
+      		"total_evaluations":  e.evalCount,
+      		"total_decisions":    len(e.decisions),
+      		"lifetime_decisions": e.lifetime,
+      		"registered_rules":   len(e.rules),
       	}
   }
 
-// Helper functions for ternary arithmetic
-func tritMin(a, b Trit) Trit {
+// Min is Kleene conjunction: the lesser of a and b, FALSE < UNKNOWN < TRUE
+func Min(a, b Trit) Trit {
   	if a < b {
       		return a
       	}
   	return b
   }
 
-func tritMax(a, b Trit) Trit {
+// Max is Kleene disjunction: the greater of a and b
+func Max(a, b Trit) Trit {
   	if a > b {
       		return a
       	}
   	return b
   }
 
-func tritNeg(a Trit) Trit {
+// Neg is ternary negation: TRUE and FALSE swap, UNKNOWN stays UNKNOWN
+func Neg(a Trit) Trit {
   	return -a
   }
+
+// Helper functions for ternary arithmetic
+func tritMin(a, b Trit) Trit { return Min(a, b) }
+
+func tritMax(a, b Trit) Trit { return Max(a, b) }
+
+func tritNeg(a Trit) Trit { return Neg(a) }
+
+// tritEq is Kleene equivalence: UNKNOWN if either side is, else whether
+// a and b are equal
+func tritEq(a, b Trit) Trit {
+  	if a == UNKNOWN || b == UNKNOWN {
+      		return UNKNOWN
+      	}
+  	if a == b {
+      		return TRUE
+      	}
+  	return FALSE
+  }
+
+// tritXor is Kleene exclusive or, NOT(EQ)
+func tritXor(a, b Trit) Trit { return tritNeg(tritEq(a, b)) }
+
+func hasUnknown(inputs []Trit) Trit {
+  	for _, inp := range inputs {
+      		if inp == UNKNOWN {
+            			return TRUE
+            		}
+      	}
+  	return FALSE
+  }
@@ -0,0 +1,411 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"fmt"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestNewEngineWithCapacity(t *testing.T) {
+  	tests := []struct {
+      		name    string
+      		n       int
+      		opts    []Option
+      		wantErr bool
+      	}{
+      		{name: "capacity 1", n: 1},
+      		{name: "with logic system", n: 3, opts: []Option{WithLogicSystem(LogicKleene)}},
+      		{name: "zero capacity", n: 0, wantErr: true},
+      		{name: "negative capacity", n: -1, wantErr: true},
+      		{name: "unknown logic system", n: 3, opts: []Option{WithLogicSystem(LogicSystem(9))}, wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e, err := NewEngineWithCapacity(tt.n, tt.opts...)
+                    			if (err != nil) != tt.wantErr {
+                              				t.Fatalf("NewEngineWithCapacity error = %v, wantErr %v", err, tt.wantErr)
+                              			}
+                    			if (e == nil) != tt.wantErr {
+                              				t.Errorf("NewEngineWithCapacity engine = %v, wantErr %v", e, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestNewEngineOptionError(t *testing.T) {
+  	defer func() {
+      		err, _ := recover().(error)
+      		if err == nil || !strings.Contains(err.Error(), "unknown logic system 9") {
+            			t.Errorf("NewEngine panicked with %v, want the option error", err)
+            		}
+      	}()
+  	NewEngine(WithLogicSystem(LogicSystem(9)))
+  	t.Error("NewEngine with a failing option did not panic")
+  }
+
+func TestRingHistory(t *testing.T) {
+  	tests := []struct {
+      		name         string
+      		capacity     int
+      		evaluations  int
+      		wantRetained int
+      		wantFirst    int // index of the oldest retained evaluation
+      	}{
+      		{name: "below capacity", capacity: 3, evaluations: 2, wantRetained: 2, wantFirst: 0},
+      		{name: "exactly full", capacity: 3, evaluations: 3, wantRetained: 3, wantFirst: 0},
+      		{name: "one past full", capacity: 3, evaluations: 4, wantRetained: 3, wantFirst: 1},
+      		{name: "wrapped twice", capacity: 3, evaluations: 7, wantRetained: 3, wantFirst: 4},
+      		{name: "capacity 1", capacity: 1, evaluations: 5, wantRetained: 1, wantFirst: 4},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e, err := NewEngineWithCapacity(tt.capacity)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			for i := 0; i < tt.evaluations; i++ {
+                              				e.EvaluateMeta(map[string]string{"i": fmt.Sprint(i)}, "AND", TRUE)
+                              			}
+                    			st := e.Stats()
+                    			if st["total_decisions"] != tt.wantRetained || st["lifetime_decisions"] != uint64(tt.evaluations) {
+                              				t.Errorf("total_decisions = %v, lifetime_decisions = %v, want %d and %d",
+                                          					st["total_decisions"], st["lifetime_decisions"], tt.wantRetained, tt.evaluations)
+                              			}
+                    			got := e.GetDecisions(DecisionFilter{})
+                    			if len(got) != tt.wantRetained {
+                              				t.Fatalf("GetDecisions returned %d, want %d", len(got), tt.wantRetained)
+                              			}
+                    			for j, d := range got {
+                              				if want := fmt.Sprint(tt.wantFirst + j); d.Meta["i"] != want {
+                                          					t.Errorf("decision %d is evaluation %s, want %s", j, d.Meta["i"], want)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestDefaultHistoryCapacity(t *testing.T) {
+  	e := NewEngine()
+  	for i := 0; i < DefaultHistoryCapacity+10; i++ {
+      		e.Evaluate("OR", TRUE)
+      	}
+  	st := e.Stats()
+  	if st["total_decisions"] != DefaultHistoryCapacity || st["lifetime_decisions"] != uint64(DefaultHistoryCapacity+10) {
+      		t.Errorf("Stats = %v, want %d retained of %d", st, DefaultHistoryCapacity, DefaultHistoryCapacity+10)
+      	}
+  }
+
+func TestEvaluateWeight(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		rule     string
+      		weight   float64
+      		inputs   []Trit
+      		want     Trit
+      		wantConf float64
+      	}{
+      		{name: "lower weight", rule: "CONSENSUS", weight: 0.5, inputs: []Trit{TRUE, TRUE, FALSE}, want: TRUE, wantConf: 0.5},
+      		{name: "weight clamped", rule: "CONSENSUS", weight: 3, inputs: []Trit{TRUE, TRUE, FALSE}, want: TRUE, wantConf: 1},
+      		{name: "zero weight", rule: "AND", weight: 0, inputs: []Trit{TRUE}, want: TRUE, wantConf: 0},
+      		{name: "unknown value", rule: "AND", weight: 0.5, inputs: []Trit{UNKNOWN}, want: UNKNOWN, wantConf: 0.25},
+      		{name: "missing rule", rule: "nope", weight: 0.5, inputs: []Trit{TRUE}, want: UNKNOWN, wantConf: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r := e.EvaluateWeight(tt.rule, tt.weight, tt.inputs...)
+                    			if r.Value != tt.want || r.Confidence != tt.wantConf {
+                              				t.Errorf("EvaluateWeight = %v conf %v, want %v conf %v", r.Value, r.Confidence, tt.want, tt.wantConf)
+                              			}
+                    			// the override must not stick to the registered rule
+                    			if r := e.Evaluate(tt.rule, tt.inputs...); tt.rule != "nope" && r.Confidence != tt.want.Confidence() {
+                              				t.Errorf("Evaluate after override conf %v, want %v", r.Confidence, tt.want.Confidence())
+                              			}
+                    		})
+      	}
+  }
+
+func TestScore(t *testing.T) {
+  	tests := []struct {
+      		result TernaryResult
+      		want   float64
+      	}{
+      		{TernaryResult{Value: TRUE, Confidence: 0.7}, 0.7},
+      		{TernaryResult{Value: FALSE, Confidence: 0.7}, -0.7},
+      		{TernaryResult{Value: UNKNOWN, Confidence: 0.5}, 0},
+      		{TernaryResult{Value: TRUE}, 0},
+      		{TernaryResult{Value: 2, Confidence: 1}, 0},
+      	}
+  	for _, tt := range tests {
+      		if got := tt.result.Score(); got != tt.want {
+            			t.Errorf("Score(%v, %v) = %v, want %v", tt.result.Value, tt.result.Confidence, got, tt.want)
+            		}
+      	}
+  }
+
+func TestEvaluateScore(t *testing.T) {
+  	tests := []struct {
+      		rule   string
+      		inputs []Trit
+      		want   float64
+      	}{
+      		{"AND", []Trit{TRUE}, 1},
+      		{"AND", []Trit{UNKNOWN}, 0},
+      		{"AND", []Trit{FALSE}, 0}, // FALSE carries confidence 0
+      		{"nope", []Trit{TRUE}, 0},
+      	}
+  	for _, tt := range tests {
+      		if got := NewEngine().EvaluateScore(tt.rule, tt.inputs...); got != tt.want {
+            			t.Errorf("EvaluateScore(%s, %v) = %v, want %v", tt.rule, tt.inputs, got, tt.want)
+            		}
+      	}
+  }
+
+func TestInputCount(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		eval func(e *Engine) TernaryResult
+      		want int
+      	}{
+      		{name: "Evaluate", eval: func(e *Engine) TernaryResult { return e.Evaluate("OR", TRUE, FALSE, UNKNOWN) }, want: 3},
+      		{name: "no inputs", eval: func(e *Engine) TernaryResult { return e.Evaluate("OR") }, want: 0},
+      		{name: "EvaluateWeight", eval: func(e *Engine) TernaryResult { return e.EvaluateWeight("AND", 0.5, TRUE, TRUE) }, want: 2},
+      		{name: "EvaluateMeta", eval: func(e *Engine) TernaryResult { return e.EvaluateMeta(nil, "AND", TRUE) }, want: 1},
+      		{name: "missing rule", eval: func(e *Engine) TernaryResult { return e.Evaluate("nope", TRUE, TRUE) }, want: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if r := tt.eval(NewEngine()); r.InputCount != tt.want {
+                              				t.Errorf("InputCount = %d, want %d", r.InputCount, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestCompletenessRules(t *testing.T) {
+  	tests := []struct {
+      		inputs               []Trit
+      		hasUnknown, allKnown Trit
+      	}{
+      		{nil, FALSE, TRUE},
+      		{[]Trit{TRUE, FALSE}, FALSE, TRUE},
+      		{[]Trit{UNKNOWN}, TRUE, FALSE},
+      		{[]Trit{TRUE, UNKNOWN, FALSE}, TRUE, FALSE},
+      		{[]Trit{FALSE, FALSE, UNKNOWN}, TRUE, FALSE},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("HAS_UNKNOWN", tt.inputs...).Value; got != tt.hasUnknown {
+            			t.Errorf("HAS_UNKNOWN%v = %v, want %v", tt.inputs, got, tt.hasUnknown)
+            		}
+      		if got := e.Evaluate("ALL_KNOWN", tt.inputs...).Value; got != tt.allKnown {
+            			t.Errorf("ALL_KNOWN%v = %v, want %v", tt.inputs, got, tt.allKnown)
+            		}
+      	}
+  }
+
+func TestUnanimous(t *testing.T) {
+  	tests := []struct {
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{nil, UNKNOWN},
+      		{[]Trit{TRUE}, TRUE},
+      		{[]Trit{FALSE}, FALSE},
+      		{[]Trit{UNKNOWN}, UNKNOWN},
+      		{[]Trit{TRUE, TRUE, TRUE}, TRUE},
+      		{[]Trit{FALSE, FALSE}, FALSE},
+      		{[]Trit{TRUE, FALSE}, UNKNOWN},
+      		{[]Trit{TRUE, UNKNOWN}, UNKNOWN},
+      		{[]Trit{UNKNOWN, UNKNOWN}, UNKNOWN},
+      		{[]Trit{FALSE, FALSE, TRUE}, UNKNOWN},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("UNANIMOUS", tt.inputs...).Value; got != tt.want {
+            			t.Errorf("UNANIMOUS%v = %v, want %v", tt.inputs, got, tt.want)
+            		}
+      	}
+  }
+
+func TestSQLRules(t *testing.T) {
+  	const NULL = UNKNOWN
+  	tests := []struct {
+      		a, b    Trit
+      		and, or Trit
+      		notOfA  Trit
+      	}{
+      		{TRUE, TRUE, TRUE, TRUE, FALSE},
+      		{TRUE, FALSE, FALSE, TRUE, FALSE},
+      		{TRUE, NULL, NULL, TRUE, FALSE},
+      		{FALSE, FALSE, FALSE, FALSE, TRUE},
+      		{FALSE, NULL, FALSE, NULL, TRUE},
+      		{NULL, NULL, NULL, NULL, NULL},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		for _, in := range [][]Trit{{tt.a, tt.b}, {tt.b, tt.a}} {
+            			if got := e.Evaluate("SQL_AND", in...).Value; got != tt.and {
+                    				t.Errorf("SQL_AND%v = %v, want %v", in, got, tt.and)
+                    			}
+            			if got := e.Evaluate("SQL_OR", in...).Value; got != tt.or {
+                    				t.Errorf("SQL_OR%v = %v, want %v", in, got, tt.or)
+                    			}
+            		}
+      		if got := e.Evaluate("SQL_NOT", tt.a).Value; got != tt.notOfA {
+            			t.Errorf("SQL_NOT(%v) = %v, want %v", tt.a, got, tt.notOfA)
+            		}
+      	}
+
+  	// the SQL rules are Kleene logic under another name
+  	for _, pair := range [][2]string{{"SQL_AND", "AND"}, {"SQL_OR", "OR"}, {"SQL_NOT", "NOT"}} {
+      		forEachInput(2, func(in []Trit) bool {
+                    			if got, want := e.Evaluate(pair[0], in...).Value, e.Evaluate(pair[1], in...).Value; got != want {
+                              				t.Errorf("%s%v = %v, %s gives %v", pair[0], in, got, pair[1], want)
+                              			}
+                    			return true
+                    		})
+      	}
+  }
+
+func TestImplies(t *testing.T) {
+  	tests := []struct {
+      		a, b, want Trit
+      	}{
+      		{FALSE, FALSE, TRUE},
+      		{FALSE, UNKNOWN, TRUE},
+      		{FALSE, TRUE, TRUE},
+      		{UNKNOWN, FALSE, UNKNOWN},
+      		{UNKNOWN, UNKNOWN, TRUE},
+      		{UNKNOWN, TRUE, TRUE},
+      		{TRUE, FALSE, FALSE},
+      		{TRUE, UNKNOWN, UNKNOWN},
+      		{TRUE, TRUE, TRUE},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("IMPLIES", tt.a, tt.b).Value; got != tt.want {
+            			t.Errorf("IMPLIES(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+            		}
+      	}
+  }
+
+func TestImpliesArity(t *testing.T) {
+  	tests := [][]Trit{nil, {TRUE}, {TRUE, TRUE, TRUE}}
+  	e := NewEngine()
+  	for _, in := range tests {
+      		r := e.Evaluate("IMPLIES", in...)
+      		if want := fmt.Sprintf("takes 2 inputs, got %d", len(in)); r.Value != UNKNOWN || !strings.Contains(r.Reason, want) {
+            			t.Errorf("IMPLIES%v = %v %q, want UNKNOWN containing %q", in, r.Value, r.Reason, want)
+            		}
+      	}
+  }
+
+func TestXorEq(t *testing.T) {
+  	tests := []struct {
+      		inputs  []Trit
+      		xor, eq Trit
+      	}{
+      		{nil, FALSE, TRUE},
+      		{[]Trit{TRUE}, TRUE, TRUE},
+      		{[]Trit{FALSE}, FALSE, FALSE},
+      		{[]Trit{TRUE, FALSE}, TRUE, FALSE},
+      		{[]Trit{TRUE, TRUE}, FALSE, TRUE},
+      		{[]Trit{FALSE, FALSE}, FALSE, TRUE},
+      		{[]Trit{TRUE, UNKNOWN}, UNKNOWN, UNKNOWN},
+      		{[]Trit{UNKNOWN, UNKNOWN}, UNKNOWN, UNKNOWN},
+      		{[]Trit{TRUE, TRUE, TRUE}, TRUE, TRUE},
+      		{[]Trit{TRUE, TRUE, FALSE}, FALSE, FALSE},
+      		{[]Trit{TRUE, FALSE, FALSE}, TRUE, TRUE},
+      		{[]Trit{FALSE, FALSE, FALSE}, FALSE, FALSE},
+      		{[]Trit{TRUE, FALSE, UNKNOWN}, UNKNOWN, UNKNOWN},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("XOR", tt.inputs...).Value; got != tt.xor {
+            			t.Errorf("XOR%v = %v, want %v", tt.inputs, got, tt.xor)
+            		}
+      		for _, name := range []string{"EQ", "XNOR"} {
+            			if got := e.Evaluate(name, tt.inputs...).Value; got != tt.eq {
+                    				t.Errorf("%s%v = %v, want %v", name, tt.inputs, got, tt.eq)
+                    			}
+            		}
+      	}
+  }
+
+func TestExportedOperators(t *testing.T) {
+  	tests := []struct {
+      		a, b     Trit
+      		min, max Trit
+      	}{
+      		{FALSE, FALSE, FALSE, FALSE},
+      		{FALSE, UNKNOWN, FALSE, UNKNOWN},
+      		{FALSE, TRUE, FALSE, TRUE},
+      		{UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN},
+      		{UNKNOWN, TRUE, UNKNOWN, TRUE},
+      		{TRUE, TRUE, TRUE, TRUE},
+      	}
+  	for _, tt := range tests {
+      		for _, in := range [][2]Trit{{tt.a, tt.b}, {tt.b, tt.a}} {
+            			if got := Min(in[0], in[1]); got != tt.min {
+                    				t.Errorf("Min(%v, %v) = %v, want %v", in[0], in[1], got, tt.min)
+                    			}
+            			if got := Max(in[0], in[1]); got != tt.max {
+                    				t.Errorf("Max(%v, %v) = %v, want %v", in[0], in[1], got, tt.max)
+                    			}
+            		}
+      	}
+  	for in, want := range map[Trit]Trit{TRUE: FALSE, FALSE: TRUE, UNKNOWN: UNKNOWN} {
+      		if got := Neg(in); got != want {
+            			t.Errorf("Neg(%v) = %v, want %v", in, got, want)
+            		}
+      	}
+  }
+
+func TestEvaluateMeta(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		meta   map[string]string
+      		rule   string
+      		want   Trit
+      		wantMD map[string]string
+      	}{
+      		{name: "attached", meta: map[string]string{"req": "42", "tenant": "acme"}, rule: "AND", want: TRUE, wantMD: map[string]string{"req": "42", "tenant": "acme"}},
+      		{name: "empty meta", meta: map[string]string{}, rule: "AND", want: TRUE},
+      		{name: "nil meta", rule: "AND", want: TRUE},
+      		{name: "failed evaluation keeps meta", meta: map[string]string{"req": "7"}, rule: "nope", want: UNKNOWN, wantMD: map[string]string{"req": "7"}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			meta := make(map[string]string)
+                    			for k, v := range tt.meta {
+                              				meta[k] = v
+                              			}
+                    			r := NewEngine().EvaluateMeta(meta, tt.rule, TRUE)
+                    			meta["req"] = "changed"
+                    			if r.Value != tt.want || !reflect.DeepEqual(r.Meta, tt.wantMD) {
+                              				t.Errorf("EvaluateMeta = %v %v, want %v %v", r.Value, r.Meta, tt.want, tt.wantMD)
+                              			}
+                    		})
+      	}
+  }
+
+func TestMetaJSON(t *testing.T) {
+  	r := NewEngine().EvaluateMeta(map[string]string{"req": "42"}, "AND", TRUE)
+  	data, err := json.Marshal(r)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	var back TernaryResult
+  	if err := json.Unmarshal(data, &back); err != nil {
+      		t.Fatal(err)
+      	}
+  	if back.Meta["req"] != "42" {
+      		t.Errorf("round trip of %s lost meta", data)
+      	}
+  	if data, _ := json.Marshal(NewEngine().Evaluate("AND", TRUE)); strings.Contains(string(data), `"meta"`) {
+      		t.Errorf("result without meta encodes %s", data)
+      	}
+  }
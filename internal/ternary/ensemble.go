@@ -0,0 +1,53 @@
+package ternary
+
+import (
+  	"fmt"
+  	"time"
+
+  	"github.com/google/uuid"
+  )
+
+// Ensemble evaluates ruleName on every engine and combines the verdicts by
+// weighted CONSENSUS, engine i voting with weights[i]. A verdict wins with
+// more than half of the total weight. Each engine records its own decision;
+// the combined result is not recorded anywhere. Mismatched lengths or
+// negative weights yield UNKNOWN with the reason.
+func Ensemble(engines []*Engine, ruleName string, weights []float64, inputs ...Trit) TernaryResult {
+  	if len(engines) != len(weights) {
+      		return ensembleResult(UNKNOWN, fmt.Sprintf("Ensemble has %d engines but %d weights", len(engines), len(weights)))
+      	}
+  	for i, w := range weights {
+      		if w < 0 {
+            			return ensembleResult(UNKNOWN, fmt.Sprintf("Ensemble weight %d is negative", i))
+            		}
+      	}
+
+  	votes := make([]WeightedTrit, len(engines))
+  	for i, e := range engines {
+      		votes[i] = WeightedTrit{Value: e.Evaluate(ruleName, inputs...).Value, Weight: weights[i]}
+      	}
+  	value := weightedMajority(votes)
+  	return ensembleResult(value, fmt.Sprintf("Ensemble[%s] combined %d engines", ruleName, len(engines)))
+  }
+
+// ensembleResult builds a result that belongs to no single engine
+func ensembleResult(value Trit, reason string) TernaryResult {
+  	return TernaryResult{
+      		ID:         uuid.New().String(),
+      		Value:      value,
+      		Confidence: value.Confidence(),
+      		Reason:     reason,
+      		Timestamp:  time.Now(),
+      	}
+  }
+
+// WeightedEnsemble is Ensemble with each engine weighted by the scorecard
+// accuracy of ruleName on that engine, so engines that were right more
+// often count more. Engines without feedback weigh 0.5; see Feedback.
+func WeightedEnsemble(engines []*Engine, ruleName string, inputs ...Trit) TernaryResult {
+  	weights := make([]float64, len(engines))
+  	for i, e := range engines {
+      		weights[i] = e.accuracy(ruleName)
+      	}
+  	return Ensemble(engines, ruleName, weights, inputs...)
+  }
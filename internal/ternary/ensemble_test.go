@@ -0,0 +1,94 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+// ensembleEngines returns two stock engines and one whose AND always says FALSE
+func ensembleEngines() []*Engine {
+  	dissent := NewEngine()
+  	dissent.AddRule("AND", TernaryRule{Name: "AND", Weight: 1, Evaluate: func(...Trit) Trit { return FALSE }})
+  	return []*Engine{NewEngine(), NewEngine(), dissent}
+  }
+
+func TestEnsemble(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		weights    []float64
+      		want       Trit
+      		wantReason string
+      	}{
+      		{name: "majority", weights: []float64{1, 1, 1}, want: TRUE, wantReason: "Ensemble[AND] combined 3 engines"},
+      		{name: "heavy dissent", weights: []float64{1, 1, 5}, want: FALSE},
+      		{name: "exact half", weights: []float64{1, 0, 1}, want: UNKNOWN},
+      		{name: "zero weights", weights: []float64{0, 0, 0}, want: UNKNOWN},
+      		{name: "length mismatch", weights: []float64{1, 1}, want: UNKNOWN, wantReason: "3 engines but 2 weights"},
+      		{name: "negative weight", weights: []float64{1, -1, 1}, want: UNKNOWN, wantReason: "weight 1 is negative"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := Ensemble(ensembleEngines(), "AND", tt.weights, TRUE)
+                    			if r.Value != tt.want || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("Ensemble(%v) = %v %q, want %v containing %q", tt.weights, r.Value, r.Reason, tt.want, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEnsembleRecords(t *testing.T) {
+  	engines := ensembleEngines()
+  	r := Ensemble(engines, "AND", []float64{1, 1, 1}, TRUE)
+  	for i, e := range engines {
+      		d := e.GetDecisions(DecisionFilter{})
+      		if len(d) != 1 || d[0].ID == r.ID {
+            			t.Errorf("engine %d recorded %v", i, d)
+            		}
+      	}
+  }
+
+func TestWeightedEnsemble(t *testing.T) {
+  	// guesser returns an engine whose GUESS is always v, with right of
+  	// total feedback verdicts correct
+  	guesser := func(v Trit, right, total int) *Engine {
+      		e := NewEngine()
+      		e.AddRule("GUESS", TernaryRule{Name: "GUESS", Weight: 1, Evaluate: func(...Trit) Trit { return v }})
+      		for i := 0; i < total; i++ {
+            			actual := -v
+            			if i < right {
+                    				actual = v
+                    			}
+            			e.Feedback(e.Evaluate("GUESS"), actual)
+            		}
+      		return e
+      	}
+  	tests := []struct {
+      		name    string
+      		engines func() []*Engine
+      		want    Trit
+      	}{
+      		{
+            			name:    "accurate minority wins",
+            			engines: func() []*Engine { return []*Engine{guesser(TRUE, 20, 20), guesser(FALSE, 0, 20), guesser(FALSE, 0, 1)} },
+            			want:    TRUE,
+            		},
+      		{
+            			name:    "no feedback is plain majority",
+            			engines: func() []*Engine { return []*Engine{guesser(TRUE, 0, 0), guesser(FALSE, 0, 0), guesser(FALSE, 0, 0)} },
+            			want:    FALSE,
+            		},
+      		{
+            			name:    "even split",
+            			engines: func() []*Engine { return []*Engine{guesser(TRUE, 3, 4), guesser(FALSE, 3, 4)} },
+            			want:    UNKNOWN,
+            		},
+      		{name: "no engines", engines: func() []*Engine { return nil }, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if r := WeightedEnsemble(tt.engines(), "GUESS"); r.Value != tt.want {
+                              				t.Errorf("WeightedEnsemble = %v, want %v", r.Value, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,45 @@
+package ternary
+
+import "math"
+
+// TritEntropy returns the Shannon entropy, in bits, of the distribution of
+// TRUE, FALSE and UNKNOWN among trits: 0 when they are all the same and
+// log2(3) when the three values are equally frequent. Invalid trits are
+// ignored.
+func TritEntropy(trits ...Trit) float64 {
+  	var counts [3]int
+  	total := 0
+  	for _, t := range trits {
+      		switch t {
+            		case FALSE, UNKNOWN, TRUE:
+            			counts[t+1]++
+            			total++
+            		}
+      	}
+  	if total == 0 {
+      		return 0
+      	}
+
+  	h := 0.0
+  	for _, c := range counts {
+      		if c == 0 {
+            			continue
+            		}
+      		p := float64(c) / float64(total)
+      		h -= p * math.Log2(p)
+      	}
+  	return h
+  }
+
+// HistoryEntropy returns TritEntropy over the values of the retained decisions
+func (e *Engine) HistoryEntropy() float64 {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	history := e.historyLocked()
+  	values := make([]Trit, len(history))
+  	for i, r := range history {
+      		values[i] = r.Value
+      	}
+  	return TritEntropy(values...)
+  }
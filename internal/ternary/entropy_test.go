@@ -0,0 +1,41 @@
+package ternary
+
+import (
+  	"math"
+  	"testing"
+  )
+
+func TestTritEntropy(t *testing.T) {
+  	tests := []struct {
+      		name  string
+      		trits []Trit
+      		want  float64
+      	}{
+      		{name: "empty", want: 0},
+      		{name: "uniform", trits: []Trit{TRUE, TRUE}, want: 0},
+      		{name: "two values", trits: []Trit{TRUE, FALSE}, want: 1},
+      		{name: "three values", trits: []Trit{TRUE, FALSE, UNKNOWN}, want: math.Log2(3)},
+      		{name: "skewed", trits: []Trit{TRUE, TRUE, TRUE, FALSE}, want: 2 - 0.75*math.Log2(3)},
+      		{name: "invalid ignored", trits: []Trit{TRUE, FALSE, 2, -5}, want: 1},
+      		{name: "only invalid", trits: []Trit{2}, want: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := TritEntropy(tt.trits...); math.Abs(got-tt.want) > 1e-9 {
+                              				t.Errorf("TritEntropy(%v) = %v, want %v", tt.trits, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestHistoryEntropy(t *testing.T) {
+  	e := NewEngine()
+  	if h := e.HistoryEntropy(); h != 0 {
+      		t.Errorf("HistoryEntropy of empty history = %v, want 0", h)
+      	}
+  	e.Evaluate("AND", TRUE)
+  	e.Evaluate("AND", FALSE)
+  	if h := e.HistoryEntropy(); h != 1 {
+      		t.Errorf("HistoryEntropy = %v, want 1", h)
+      	}
+  }
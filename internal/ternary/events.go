@@ -0,0 +1,40 @@
+package ternary
+
+// confidenceCross is a callback registered with OnConfidenceCross
+type confidenceCross struct {
+  	threshold float64
+  	fn        func(prev, cur TernaryResult)
+  }
+
+// OnConfidenceCross registers fn to be called whenever a result of rule
+// crosses threshold relative to the rule's previous result: upward when the
+// previous confidence was below threshold and the new one is at or above
+// it, downward in the opposite case. The first result of a rule has nothing
+// to cross from. fn runs synchronously while the engine lock is held, so it
+// must not call back into the Engine.
+func (e *Engine) OnConfidenceCross(rule string, threshold float64, fn func(prev, cur TernaryResult)) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.crossings[rule] = append(e.crossings[rule], confidenceCross{threshold: threshold, fn: fn})
+  }
+
+// notifyCrossingsLocked fires the crossing callbacks for cur's rule and
+// remembers cur as that rule's previous result. The caller must hold e.mu.
+func (e *Engine) notifyCrossingsLocked(cur TernaryResult) {
+  	watchers := e.crossings[cur.Rule]
+  	if len(watchers) == 0 {
+      		return
+      	}
+  	prev, seen := e.lastResult[cur.Rule]
+  	e.lastResult[cur.Rule] = cur
+  	if !seen {
+      		return
+      	}
+  	for _, w := range watchers {
+      		wasAbove := prev.Confidence >= w.threshold
+      		isAbove := cur.Confidence >= w.threshold
+      		if wasAbove != isAbove {
+            			w.fn(prev, cur)
+            		}
+      	}
+  }
@@ -0,0 +1,44 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  )
+
+func TestOnConfidenceCross(t *testing.T) {
+  	// CONSENSUS of TRUE, TRUE has confidence 1, of TRUE, FALSE 0.5 and of
+  	// FALSE, FALSE 0
+  	high, mid, low := []Trit{TRUE, TRUE}, []Trit{TRUE, FALSE}, []Trit{FALSE, FALSE}
+  	tests := []struct {
+      		name      string
+      		threshold float64
+      		evals     [][]Trit
+      		want      []string
+      	}{
+      		{name: "first result crosses nothing", threshold: 0.8, evals: [][]Trit{high}},
+      		{name: "upward once", threshold: 0.8, evals: [][]Trit{mid, mid, high, high}, want: []string{"up"}},
+      		{name: "up and down", threshold: 0.8, evals: [][]Trit{mid, high, mid, high}, want: []string{"up", "down", "up"}},
+      		{name: "landing on threshold is above", threshold: 0.5, evals: [][]Trit{low, mid, mid}, want: []string{"up"}},
+      		{name: "leaving threshold downward", threshold: 0.5, evals: [][]Trit{mid, low}, want: []string{"down"}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			var got []string
+                    			e.OnConfidenceCross("CONSENSUS", tt.threshold, func(prev, cur TernaryResult) {
+                                          				if cur.Confidence >= tt.threshold {
+                                                        					got = append(got, "up")
+                                                        				} else {
+                                                        					got = append(got, "down")
+                                                        				}
+                                          			})
+                    			for _, in := range tt.evals {
+                              				e.Evaluate("CONSENSUS", in...)
+                              				e.Evaluate("AND", low...) // other rules do not interleave
+                              			}
+                    			if !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("crossings = %v, want %v", got, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,190 @@
+package ternary
+
+import (
+  	"errors"
+  	"fmt"
+  )
+
+const (
+  	// evolveBias is the UNKNOWN fraction above which EVOLVE leans TRUE
+  	evolveBias = 0.3
+  	// evolveDamping is how far fully confident known votes raise that fraction
+  	evolveDamping = 0.4
+  )
+
+// EvolveConfig tunes the EVOLVE rule
+type EvolveConfig struct {
+  	// Threshold is the UNKNOWN fraction above which EVOLVE returns TRUE
+  	Threshold float64
+  	// FallbackChain lists the rules tried in order below the threshold; the
+  	// first definite value wins, as in EvaluateWithFallback
+  	FallbackChain []string
+  }
+
+// DefaultEvolveConfig returns the shipped EVOLVE behavior: a 30% threshold
+// falling back to CONSENSUS
+func DefaultEvolveConfig() EvolveConfig {
+  	return EvolveConfig{
+      		Threshold:     evolveBias,
+      		FallbackChain: []string{"CONSENSUS"},
+      	}
+  }
+
+// SetEvolveConfig replaces the EVOLVE configuration. The fallback chain may
+// not contain EVOLVE itself.
+func (e *Engine) SetEvolveConfig(cfg EvolveConfig) error {
+  	if containsString(cfg.FallbackChain, "EVOLVE") {
+      		return errors.New("ternary: EVOLVE cannot fall back to itself")
+      	}
+  	cfg.FallbackChain = append([]string(nil), cfg.FallbackChain...)
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.evolve = cfg
+  	return nil
+  }
+
+// EvaluateWithFallback tries ruleNames in order and records the first
+// definite result. Unknown and disabled rules are skipped. If no rule is
+// definite, the UNKNOWN result of the last available rule is recorded.
+func (e *Engine) EvaluateWithFallback(ruleNames []string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	return e.firstDefiniteLocked(ruleNames, inputs)
+  }
+
+// firstDefiniteLocked implements EvaluateWithFallback over ruleNames in the
+// given order. The caller must hold e.mu.
+func (e *Engine) firstDefiniteLocked(ruleNames []string, inputs []Trit) TernaryResult {
+  	e.evalCount++
+
+  	lastName, tried := "", 0
+  	var (
+      		lastRule TernaryRule
+      		lastUsed []Trit
+      	)
+  	for _, name := range ruleNames {
+      		rule, _, ok := e.ruleLocked(name)
+      		if !ok {
+            			continue
+            		}
+      		tried++
+      		lastName, lastRule = name, rule
+      		used, value, note, ok := e.invokeLocked(name, rule, inputs)
+      		if !ok {
+            			return e.failedResult(note)
+            		}
+      		lastUsed = used
+      		if value != UNKNOWN {
+            			reason := withNote(fmt.Sprintf("Rule[%s] resolved %d inputs after %d fallbacks", name, len(used), tried-1), note)
+            			return e.recordLocked(e.resultLocked(name, rule.Weight, value, used, reason))
+            		}
+      	}
+
+  	if tried == 0 {
+      		return e.failedResult(fmt.Sprintf("None of rules %v available", ruleNames))
+      	}
+  	reason := fmt.Sprintf("No definite result from %d rules; Rule[%s] was last", tried, lastName)
+  	return e.recordLocked(e.resultLocked(lastName, lastRule.Weight, UNKNOWN, lastUsed, reason))
+  }
+
+// fallbackLocked returns the first definite value among ruleNames over
+// inputs, or UNKNOWN. The caller must hold e.mu.
+func (e *Engine) fallbackLocked(ruleNames []string, inputs []Trit) Trit {
+  	for _, name := range ruleNames {
+      		rule, _, ok := e.ruleLocked(name)
+      		if !ok {
+            			continue
+            		}
+      		if _, value, _, ok := e.invokeLocked(name, rule, inputs); ok && value != UNKNOWN {
+            			return value
+            		}
+      	}
+  	return UNKNOWN
+  }
+
+// evolveWeighted is EVOLVE with the action bias modulated by confidence.
+// With c the mean weight of the TRUE and FALSE votes (clamped to [0,1],
+// 0 when there are none), the bias fires when the UNKNOWN fraction exceeds
+//
+//	threshold = 0.3 + 0.4*c
+//
+// so uncertain known votes behave like plain EVOLVE (0.3) while confident
+// ones require up to 70% UNKNOWN before acting. Otherwise the votes are
+// decided by weighted majority. Abstentions are left out entirely.
+func evolveWeighted(votes []WeightedTrit) Trit {
+  	votes = castVotes(votes)
+  	if len(votes) == 0 {
+      		return UNKNOWN
+      	}
+
+  	unknowns, known := 0, 0
+  	confSum := 0.0
+  	for _, v := range votes {
+      		if v.Value == UNKNOWN {
+            			unknowns++
+            			continue
+            		}
+      		known++
+      		confSum += clamp01(v.Weight)
+      	}
+
+  	c := 0.0
+  	if known > 0 {
+      		c = confSum / float64(known)
+      	}
+  	threshold := evolveBias + evolveDamping*c
+  	if float64(unknowns)/float64(len(votes)) > threshold {
+      		return TRUE
+      	}
+  	return weightedMajority(votes)
+  }
+
+// EvaluateEvolveWeighted evaluates EVOLVE_WEIGHTED over votes whose weights
+// are the voters' confidences and records the decision. The rule is looked
+// up like any other: a replacement registered with AddRule is evaluated over
+// the vote values, and a Cacheable rule answers from the truth table, so the
+// result matches Evaluate("EVOLVE_WEIGHTED", ...) whenever weights play no
+// part.
+func (e *Engine) EvaluateEvolveWeighted(votes []WeightedTrit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked("EVOLVE_WEIGHTED")
+  	if !ok {
+      		return failed
+      	}
+
+  	votes = castVotes(votes)
+  	inputs := make([]Trit, len(votes))
+  	weights := make([]float64, len(votes))
+  	for i, v := range votes {
+      		inputs[i], weights[i] = v.Value, v.Weight
+      	}
+  	if rule.Weighted == nil || rule.Cacheable {
+      		result, _ := e.applyLocked("EVOLVE_WEIGHTED", rule, inputs)
+      		return result
+      	}
+
+  	inputs, reason, ok := e.sanitizeLocked("EVOLVE_WEIGHTED", inputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	value := e.weightedLocked(rule, inputs, weights)
+  	reason = fmt.Sprintf("Rule[EVOLVE_WEIGHTED] evaluated %d weighted inputs", len(votes))
+  	return e.recordLocked(e.resultLocked("EVOLVE_WEIGHTED", rule.Weight, value, inputs, reason))
+  }
+
+// clamp01 limits x to [0, 1]
+func clamp01(x float64) float64 {
+  	if x < 0 {
+      		return 0
+      	}
+  	if x > 1 {
+      		return 1
+      	}
+  	return x
+  }
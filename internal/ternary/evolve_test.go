@@ -0,0 +1,169 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateEvolveWeighted(t *testing.T) {
+  	u := func(w float64) WeightedTrit { return WeightedTrit{Value: UNKNOWN, Weight: w} }
+  	tests := []struct {
+      		name  string
+      		votes []WeightedTrit
+      		want  Trit
+      	}{
+      		{name: "no votes", want: UNKNOWN},
+      		{name: "all unknown", votes: []WeightedTrit{u(1), u(1)}, want: TRUE},
+      		{name: "confident dissent damps the bias", votes: []WeightedTrit{u(1), u(1), {Value: FALSE, Weight: 0.95}}, want: UNKNOWN},
+      		{name: "unsure dissent keeps the bias", votes: []WeightedTrit{u(1), u(1), {Value: FALSE, Weight: 0.05}}, want: TRUE},
+      		{name: "confidence clamped to 1", votes: []WeightedTrit{u(1), u(1), {Value: FALSE, Weight: 5}}, want: FALSE},
+      		{name: "weighted majority", votes: []WeightedTrit{{Value: TRUE, Weight: 1}, {Value: FALSE, Weight: 0.5}}, want: TRUE},
+      		{name: "abstentions left out", votes: []WeightedTrit{{Value: UNKNOWN, Weight: 1, Abstain: true}, {Value: UNKNOWN, Weight: 1, Abstain: true}, {Value: FALSE, Weight: 1}}, want: FALSE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := NewEngine().EvaluateEvolveWeighted(tt.votes)
+                    			if r.Value != tt.want || r.Rule != "EVOLVE_WEIGHTED" {
+                              				t.Errorf("EvaluateEvolveWeighted = %v from %q, want %v", r.Value, r.Rule, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateEvolveWeightedMatchesEvaluate(t *testing.T) {
+  	votes := []WeightedTrit{{Value: UNKNOWN, Weight: 1}, {Value: FALSE, Weight: 1}, {Value: FALSE, Weight: 1}}
+  	inputs := []Trit{UNKNOWN, FALSE, FALSE}
+  	tests := []struct {
+      		name  string
+      		setup func(e *Engine)
+      		want  Trit
+      	}{
+      		{name: "default", want: FALSE},
+      		{
+            			name: "overridden",
+            			setup: func(e *Engine) {
+                    				e.AddRule("EVOLVE_WEIGHTED", TernaryRule{Weight: 1, Evaluate: func(...Trit) Trit { return TRUE }})
+                    			},
+            			want: TRUE,
+            		},
+      		{
+            			name: "truth table",
+            			setup: func(e *Engine) {
+                    				rule := TernaryRule{Weight: 1, Cacheable: true, Evaluate: func(...Trit) Trit { return FALSE }}
+                    				e.AddRule("EVOLVE_WEIGHTED", rule)
+                    				e.Memoize(TruthKey("EVOLVE_WEIGHTED", inputs...), UNKNOWN)
+                    			},
+            			want: UNKNOWN,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if tt.setup != nil {
+                              				tt.setup(e)
+                              			}
+                    			weighted := e.EvaluateEvolveWeighted(votes)
+                    			plain := e.Evaluate("EVOLVE_WEIGHTED", inputs...)
+                    			if weighted.Value != tt.want || plain.Value != tt.want {
+                              				t.Errorf("EvaluateEvolveWeighted = %v, Evaluate = %v, want both %v", weighted.Value, plain.Value, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvolveUndamped(t *testing.T) {
+  	// plain EVOLVE ignores certainty: two thirds UNKNOWN is past its 30% bias
+  	if r := NewEngine().Evaluate("EVOLVE", UNKNOWN, UNKNOWN, FALSE); r.Value != TRUE {
+      		t.Errorf("EVOLVE(UNKNOWN, UNKNOWN, FALSE) = %v, want TRUE", r.Value)
+      	}
+  }
+
+func TestEvolveConfig(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		cfg    EvolveConfig
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{name: "default bias", cfg: DefaultEvolveConfig(), inputs: []Trit{UNKNOWN, TRUE, FALSE}, want: TRUE},
+      		{name: "default consensus", cfg: DefaultEvolveConfig(), inputs: []Trit{FALSE, FALSE, TRUE}, want: FALSE},
+      		{name: "raised threshold", cfg: EvolveConfig{Threshold: 0.5, FallbackChain: []string{"CONSENSUS"}}, inputs: []Trit{UNKNOWN, FALSE, FALSE}, want: FALSE},
+      		{name: "chain skips missing rules", cfg: EvolveConfig{Threshold: 0.3, FallbackChain: []string{"CONSENSUS", "MISSING", "ALWAYS_FALSE"}}, inputs: []Trit{TRUE, FALSE}, want: FALSE},
+      		{name: "first definite wins", cfg: EvolveConfig{Threshold: 0.3, FallbackChain: []string{"OR", "ALWAYS_FALSE"}}, inputs: []Trit{TRUE, FALSE}, want: TRUE},
+      		{name: "empty chain", cfg: EvolveConfig{Threshold: 0.3}, inputs: []Trit{TRUE, TRUE}, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("ALWAYS_FALSE", TernaryRule{Name: "ALWAYS_FALSE", Weight: 1, Evaluate: func(...Trit) Trit { return FALSE }})
+                    			if err := e.SetEvolveConfig(tt.cfg); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if got := e.Evaluate("EVOLVE", tt.inputs...).Value; got != tt.want {
+                              				t.Errorf("EVOLVE%v = %v, want %v", tt.inputs, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestSetEvolveConfigSelfReference(t *testing.T) {
+  	e := NewEngine()
+  	if err := e.SetEvolveConfig(EvolveConfig{FallbackChain: []string{"CONSENSUS", "EVOLVE"}}); err == nil {
+      		t.Error("SetEvolveConfig accepted EVOLVE in its own fallback chain")
+      	}
+  	if got := e.Evaluate("EVOLVE", FALSE, FALSE, TRUE).Value; got != FALSE {
+      		t.Errorf("rejected config was applied: EVOLVE = %v, want FALSE", got)
+      	}
+  }
+
+func TestEvaluateWithFallback(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		chain      []string
+      		want       Trit
+      		wantRule   string
+      		wantReason string
+      	}{
+      		{name: "first definite", chain: []string{"OR", "ALWAYS_FALSE"}, want: TRUE, wantRule: "OR", wantReason: "after 0 fallbacks"},
+      		{name: "falls back", chain: []string{"CONSENSUS", "ALWAYS_FALSE"}, want: FALSE, wantRule: "ALWAYS_FALSE", wantReason: "after 1 fallbacks"},
+      		{name: "missing rules skipped", chain: []string{"MISSING", "CONSENSUS", "ALWAYS_FALSE"}, want: FALSE, wantRule: "ALWAYS_FALSE", wantReason: "after 1 fallbacks"},
+      		{name: "none definite", chain: []string{"CONSENSUS", "UNANIMOUS"}, want: UNKNOWN, wantRule: "UNANIMOUS", wantReason: "No definite result from 2 rules"},
+      		{name: "none available", chain: []string{"MISSING"}, want: UNKNOWN, wantReason: "None of rules [MISSING] available"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("ALWAYS_FALSE", TernaryRule{Name: "ALWAYS_FALSE", Weight: 1, Evaluate: func(...Trit) Trit { return FALSE }})
+                    			r := e.EvaluateWithFallback(tt.chain, TRUE, FALSE)
+                    			if r.Value != tt.want || r.Rule != tt.wantRule || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateWithFallback(%v) = %v from %q (%q), want %v from %q containing %q",
+                                          					tt.chain, r.Value, r.Rule, r.Reason, tt.want, tt.wantRule, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateWithFallbackRecordsCoercedInputs(t *testing.T) {
+  	tests := []struct {
+      		name  string
+      		chain []string
+      		want  Trit
+      	}{
+      		{name: "definite", chain: []string{"OR"}, want: TRUE},
+      		{name: "none definite", chain: []string{"CONSENSUS", "UNANIMOUS"}, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(CaptureInputs(), WithInvalidInputs(CoerceInvalid))
+                    			r := e.EvaluateWithFallback(tt.chain, TRUE, Trit(9))
+                    			if r.Value != tt.want {
+                              				t.Errorf("EvaluateWithFallback(%v) = %v, want %v", tt.chain, r.Value, tt.want)
+                              			}
+                    			want := []Trit{TRUE, UNKNOWN}
+                    			if !reflect.DeepEqual(r.Inputs, want) {
+                              				t.Errorf("Inputs = %v, want the coerced %v", r.Inputs, want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,51 @@
+package ternary
+
+import (
+  	"encoding/csv"
+  	"encoding/json"
+  	"fmt"
+  	"io"
+  	"strconv"
+  	"time"
+  )
+
+// ExportDecisions writes the retained decisions, oldest first, to w as
+// "json" (an array of TernaryResult) or "csv" (a header row, then columns
+// id, value, confidence, reason, timestamp and depth, with values in the
+// string form of Trit and timestamps in RFC 3339). The history is snapshotted
+// under the read lock and written after releasing it, so slow writers do
+// not hold up evaluation. An unsupported format is an error before
+// anything is written.
+func (e *Engine) ExportDecisions(w io.Writer, format string) error {
+  	if format != "json" && format != "csv" {
+      		return fmt.Errorf("ternary: unsupported export format %q", format)
+      	}
+
+  	e.mu.RLock()
+  	decisions := append([]TernaryResult{}, e.historyLocked()...)
+  	e.mu.RUnlock()
+
+  	if format == "json" {
+      		return json.NewEncoder(w).Encode(decisions)
+      	}
+
+  	cw := csv.NewWriter(w)
+  	if err := cw.Write([]string{"id", "value", "confidence", "reason", "timestamp", "depth"}); err != nil {
+      		return err
+      	}
+  	for _, d := range decisions {
+      		record := []string{
+            			d.ID,
+            			d.Value.String(),
+            			strconv.FormatFloat(d.Confidence, 'g', -1, 64),
+            			d.Reason,
+            			d.Timestamp.Format(time.RFC3339),
+            			strconv.Itoa(d.Depth),
+            		}
+      		if err := cw.Write(record); err != nil {
+            			return err
+            		}
+      	}
+  	cw.Flush()
+  	return cw.Error()
+  }
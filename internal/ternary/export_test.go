@@ -0,0 +1,118 @@
+package ternary
+
+import (
+  	"bytes"
+  	"encoding/csv"
+  	"encoding/json"
+  	"errors"
+  	"fmt"
+  	"reflect"
+  	"strings"
+  	"testing"
+  	"time"
+  )
+
+// exportEngine returns an engine with two deterministic decisions
+func exportEngine() *Engine {
+  	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+  	n := 0
+  	e := NewEngine(WithClock(func() time.Time { return fixed }), WithIDGenerator(func() string {
+                    		n++
+                    		return fmt.Sprintf("id-%d", n)
+                    	}))
+  	e.Evaluate("AND", TRUE)
+  	e.Evaluate("OR", UNKNOWN)
+  	return e
+  }
+
+func TestExportDecisions(t *testing.T) {
+  	tests := []struct {
+      		format  string
+      		want    string
+      		wantErr string
+      	}{
+      		{
+            			format: "csv",
+            			want: "id,value,confidence,reason,timestamp,depth\n" +
+            				"id-1,█ TRUE,1,Rule[AND] evaluated 1 inputs,2024-01-02T03:04:05Z,0\n" +
+            				"id-2,▒ UNKNOWN,0.5,Rule[OR] evaluated 1 inputs,2024-01-02T03:04:05Z,0\n",
+            		},
+      		{format: "xml", wantErr: `unsupported export format "xml"`},
+      		{format: "CSV", wantErr: "unsupported export format"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.format, func(t *testing.T) {
+                    			var buf bytes.Buffer
+                    			err := exportEngine().ExportDecisions(&buf, tt.format)
+                    			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+                              				t.Fatalf("ExportDecisions error = %v, want %q", err, tt.wantErr)
+                              			}
+                    			if buf.String() != tt.want {
+                              				t.Errorf("ExportDecisions wrote\n%s\nwant\n%s", buf.String(), tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExportDecisionsCSVColumns(t *testing.T) {
+  	tests := []struct {
+      		name          string
+      		value         Trit
+      		at            time.Time
+      		wantValue     string
+      		wantTimestamp string
+      	}{
+      		{name: "true", value: TRUE, at: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), wantValue: TRUE.String(), wantTimestamp: "2024-01-02T03:04:05Z"},
+      		{name: "false", value: FALSE, at: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), wantValue: FALSE.String(), wantTimestamp: "2024-01-02T03:04:05Z"},
+      		{name: "fractional seconds dropped", value: TRUE, at: time.Date(2024, 1, 2, 3, 4, 5, 999_000_000, time.UTC), wantValue: TRUE.String(), wantTimestamp: "2024-01-02T03:04:05Z"},
+      		{name: "zone kept", value: TRUE, at: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*3600)), wantValue: TRUE.String(), wantTimestamp: "2024-01-02T03:04:05+02:00"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(WithClock(func() time.Time { return tt.at }))
+                    			e.Evaluate("OR", tt.value)
+                    			var buf bytes.Buffer
+                    			if err := e.ExportDecisions(&buf, "csv"); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			rows, err := csv.NewReader(&buf).ReadAll()
+                    			if err != nil || len(rows) != 2 {
+                              				t.Fatalf("CSV rows = %v, %v, want a header and one row", rows, err)
+                              			}
+                    			if got := rows[1][1]; got != tt.wantValue {
+                              				t.Errorf("value column = %q, want %q", got, tt.wantValue)
+                              			}
+                    			if got := rows[1][4]; got != tt.wantTimestamp {
+                              				t.Errorf("timestamp column = %q, want %q", got, tt.wantTimestamp)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExportDecisionsJSON(t *testing.T) {
+  	e := exportEngine()
+  	var buf bytes.Buffer
+  	if err := e.ExportDecisions(&buf, "json"); err != nil {
+      		t.Fatal(err)
+      	}
+  	var back []TernaryResult
+  	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+      		t.Fatal(err)
+      	}
+  	if want := e.GetDecisions(DecisionFilter{}); !reflect.DeepEqual(back, want) {
+      		t.Errorf("exported %+v, want %+v", back, want)
+      	}
+  }
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestExportDecisionsWriteError(t *testing.T) {
+  	for _, format := range []string{"csv", "json"} {
+      		if err := exportEngine().ExportDecisions(failingWriter{}, format); err == nil || !strings.Contains(err.Error(), "disk full") {
+            			t.Errorf("ExportDecisions(%s) error = %v, want disk full", format, err)
+            		}
+      	}
+  }
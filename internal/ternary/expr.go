@@ -0,0 +1,304 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"errors"
+  	"fmt"
+  	"strings"
+  )
+
+// Expr is a ternary expression tree. A node applies the registered rule
+// named Rule to the values of its Children; a leaf (empty Rule) is the
+// constant Value, or the input variable Var when Var is set.
+type Expr struct {
+  	Rule     string
+  	Children []Expr
+  	Value    Trit
+  	Var      string
+  }
+
+// Leaf returns a constant expression
+func Leaf(v Trit) Expr {
+  	return Expr{Value: v}
+  }
+
+// Variable returns a leaf standing for the input named name
+func Variable(name string) Expr {
+  	return Expr{Var: name}
+  }
+
+// Call returns an expression applying rule to args
+func Call(rule string, args ...Expr) Expr {
+  	return Expr{Rule: rule, Children: args}
+  }
+
+// IsLeaf reports whether x is a constant
+func (x Expr) IsLeaf() bool {
+  	return x.Rule == ""
+  }
+
+// IsVar reports whether x is an input variable
+func (x Expr) IsVar() bool {
+  	return x.Rule == "" && x.Var != ""
+  }
+
+// isConst reports whether x is a constant leaf
+func (x Expr) isConst() bool {
+  	return x.Rule == "" && x.Var == ""
+  }
+
+// String returns the expression in call form, e.g. "AND(TRUE, OR(FALSE, UNKNOWN))"
+func (x Expr) String() string {
+  	if x.IsVar() {
+      		return x.Var
+      	}
+  	if x.IsLeaf() {
+      		return tritName(x.Value)
+      	}
+  	args := make([]string, len(x.Children))
+  	for i, c := range x.Children {
+      		args[i] = c.String()
+      	}
+  	return x.Rule + "(" + strings.Join(args, ", ") + ")"
+  }
+
+// exprJSON is the wire form of Expr: {"leaf":"TRUE"} for constants,
+// {"var":"x"} for variables and {"rule":"AND","children":[...]} for nodes
+type exprJSON struct {
+  	Rule     string `json:"rule,omitempty"`
+  	Children []Expr `json:"children,omitempty"`
+  	Leaf     string `json:"leaf,omitempty"`
+  	Var      string `json:"var,omitempty"`
+  }
+
+// MarshalJSON implements json.Marshaler
+func (x Expr) MarshalJSON() ([]byte, error) {
+  	if x.IsVar() {
+      		return json.Marshal(exprJSON{Var: x.Var})
+      	}
+  	if x.IsLeaf() {
+      		return json.Marshal(exprJSON{Leaf: tritName(x.Value)})
+      	}
+  	return json.Marshal(exprJSON{Rule: x.Rule, Children: x.Children})
+  }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (x *Expr) UnmarshalJSON(data []byte) error {
+  	var raw exprJSON
+  	if err := json.Unmarshal(data, &raw); err != nil {
+      		return err
+      	}
+
+  	set := 0
+  	for _, s := range []string{raw.Rule, raw.Leaf, raw.Var} {
+      		if s != "" {
+            			set++
+            		}
+      	}
+  	switch {
+      	case set > 1:
+      		return errors.New("ternary: expression needs exactly one of rule, leaf and var")
+      	case raw.Var != "":
+      		if len(raw.Children) > 0 {
+            			return errors.New("ternary: variable expression has children")
+            		}
+      		*x = Variable(raw.Var)
+      	case raw.Rule != "":
+      		*x = Expr{Rule: raw.Rule, Children: raw.Children}
+      	case raw.Leaf != "":
+      		if len(raw.Children) > 0 {
+            			return errors.New("ternary: leaf expression has children")
+            		}
+      		v, ok := tritFromName(raw.Leaf)
+      		if !ok {
+            			return fmt.Errorf("ternary: invalid leaf %q", raw.Leaf)
+            		}
+      		*x = Leaf(v)
+      	default:
+      		return errors.New("ternary: expression needs a rule, a leaf or a var")
+      	}
+  	return nil
+  }
+
+// EvaluateExpr evaluates an expression tree bottom-up and records the root
+// decision. Depth is the height of the tree above its leaves: a rule applied
+// to constants only has Depth 0.
+func (e *Engine) EvaluateExpr(x Expr) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	return e.evaluateExprLocked(x)
+  }
+
+// evaluateExprLocked implements EvaluateExpr. The caller must hold e.mu.
+func (e *Engine) evaluateExprLocked(x Expr) TernaryResult {
+  	e.evalCount++
+
+  	if x.IsVar() {
+      		return e.failedResult(fmt.Sprintf("Expr variable %s is unbound", x.Var))
+      	}
+  	if x.IsLeaf() {
+      		value, reason, ok := e.constantLocked(x.Value)
+      		if !ok {
+            			return e.failedResult(reason)
+            		}
+      		reason = fmt.Sprintf("Constant %s", tritName(value))
+      		return e.recordLocked(e.resultLocked("", 1.0, value, nil, reason))
+      	}
+
+  	inputs, depth, failed, ok := e.exprInputsLocked(x)
+  	if !ok {
+      		return failed
+      	}
+  	rule, failed, ok := e.ruleLocked(x.Rule)
+  	if !ok {
+      		return failed
+      	}
+
+  	inputs, value, note, ok := e.invokeLocked(x.Rule, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+  	reason := withNote(fmt.Sprintf("Expr[%s] evaluated to depth %d", x, depth), note)
+  	result := e.resultLocked(x.Rule, rule.Weight, value, inputs, reason)
+  	result.Depth = depth
+  	return e.recordLocked(result)
+  }
+
+// exprInputsLocked evaluates the children of node x and returns their values
+// with the depth of x. On failure it returns the UNKNOWN result describing
+// the first problem. The caller must hold e.mu.
+func (e *Engine) exprInputsLocked(x Expr) ([]Trit, int, TernaryResult, bool) {
+  	inputs := make([]Trit, len(x.Children))
+  	depth := 0
+  	for i, child := range x.Children {
+      		if child.IsVar() {
+            			return nil, 0, e.failedResult(fmt.Sprintf("Expr variable %s is unbound", child.Var)), false
+            		}
+      		if child.IsLeaf() {
+            			inputs[i] = child.Value
+            			continue
+            		}
+      		v, d, failed, ok := e.exprValueLocked(child)
+      		if !ok {
+            			return nil, 0, failed, false
+            		}
+      		inputs[i] = v
+      		if d+1 > depth {
+            			depth = d + 1
+            		}
+      	}
+  	return inputs, depth, TernaryResult{}, true
+  }
+
+// exprValueLocked computes the value and depth of node x without recording.
+// The caller must hold e.mu.
+func (e *Engine) exprValueLocked(x Expr) (Trit, int, TernaryResult, bool) {
+  	rule, failed, ok := e.ruleLocked(x.Rule)
+  	if !ok {
+      		return UNKNOWN, 0, failed, false
+      	}
+  	inputs, depth, failed, ok := e.exprInputsLocked(x)
+  	if !ok {
+      		return UNKNOWN, 0, failed, false
+      	}
+  	_, value, note, ok := e.invokeLocked(x.Rule, rule, inputs)
+  	if !ok {
+      		return UNKNOWN, 0, e.failedResult(note), false
+      	}
+  	return value, depth, TernaryResult{}, true
+  }
+
+// Graphviz returns the expression tree as Graphviz DOT source. Rule nodes
+// are labeled with the rule name, constants with their CP437 trit glyph and
+// variables with their name, and edges point from each node to its children.
+func (x Expr) Graphviz() string {
+  	var b strings.Builder
+  	b.WriteString("digraph expr {\n")
+  	next := 0
+  	var walk func(x Expr) int
+  	walk = func(x Expr) int {
+      		id := next
+      		next++
+      		if x.IsVar() {
+            			fmt.Fprintf(&b, "  n%d [label=%q, shape=box];\n", id, x.Var)
+            			return id
+            		}
+      		if x.IsLeaf() {
+            			fmt.Fprintf(&b, "  n%d [label=%q, shape=box];\n", id, x.Value.String())
+            			return id
+            		}
+      		fmt.Fprintf(&b, "  n%d [label=%q];\n", id, x.Rule)
+      		for _, c := range x.Children {
+            			child := walk(c)
+            			fmt.Fprintf(&b, "  n%d -> n%d;\n", id, child)
+            		}
+      		return id
+      	}
+  	walk(x)
+  	b.WriteString("}\n")
+  	return b.String()
+  }
+
+// simplifyFolds are the rules Simplify can evaluate without an engine,
+// with their default (Kleene) semantics
+var simplifyFolds = map[string]func(inputs ...Trit) Trit{
+  	"AND": func(inputs ...Trit) Trit {
+      		result := TRUE
+      		for _, inp := range inputs {
+            			result = tritMin(result, inp)
+            		}
+      		return result
+      	},
+  	"OR": func(inputs ...Trit) Trit {
+      		result := FALSE
+      		for _, inp := range inputs {
+            			result = tritMax(result, inp)
+            		}
+      		return result
+      	},
+  	"NOT": func(inputs ...Trit) Trit {
+      		if len(inputs) == 0 {
+            			return UNKNOWN
+            		}
+      		return tritNeg(inputs[0])
+      	},
+  }
+
+// Simplify returns an equivalent, usually smaller, expression without
+// consulting an engine. AND, OR and NOT nodes over constants are folded to
+// leaves, AND with a FALSE leaf becomes FALSE and OR with a TRUE leaf becomes
+// TRUE. Other rules and variables are kept, with children simplified. It
+// assumes the default rule semantics, so trees meant for an engine with
+// redefined AND, OR or NOT should not be simplified.
+func (x Expr) Simplify() Expr {
+  	if x.IsLeaf() {
+      		return x
+      	}
+
+  	children := make([]Expr, len(x.Children))
+  	allConst := true
+  	for i, c := range x.Children {
+      		children[i] = c.Simplify()
+      		if !children[i].isConst() {
+            			allConst = false
+            		}
+      	}
+
+  	for _, c := range children {
+      		if !c.isConst() {
+            			continue
+            		}
+      		if (x.Rule == "AND" && c.Value == FALSE) || (x.Rule == "OR" && c.Value == TRUE) {
+            			return Leaf(c.Value)
+            		}
+      	}
+
+  	if fold, ok := simplifyFolds[x.Rule]; ok && allConst {
+      		inputs := make([]Trit, len(children))
+      		for i, c := range children {
+            			inputs[i] = c.Value
+            		}
+      		return Leaf(fold(inputs...))
+      	}
+  	return Expr{Rule: x.Rule, Children: children}
+  }
@@ -0,0 +1,178 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestExprJSON(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		expr Expr
+      		json string
+      	}{
+      		{name: "leaf", expr: Leaf(UNKNOWN), json: `{"leaf":"UNKNOWN"}`},
+      		{name: "no children", expr: Call("NOT"), json: `{"rule":"NOT"}`},
+      		{
+            			name: "nested",
+            			expr: Call("AND", Leaf(TRUE), Call("OR", Leaf(FALSE), Leaf(UNKNOWN))),
+            			json: `{"rule":"AND","children":[{"leaf":"TRUE"},{"rule":"OR","children":[{"leaf":"FALSE"},{"leaf":"UNKNOWN"}]}]}`,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			data, err := json.Marshal(tt.expr)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if string(data) != tt.json {
+                              				t.Errorf("Marshal = %s, want %s", data, tt.json)
+                              			}
+                    			var back Expr
+                    			if err := json.Unmarshal(data, &back); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if !reflect.DeepEqual(back, tt.expr) {
+                              				t.Errorf("round trip = %#v, want %#v", back, tt.expr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExprUnmarshalErrors(t *testing.T) {
+  	tests := []struct {
+      		name    string
+      		json    string
+      		wantErr string
+      	}{
+      		{name: "empty", json: `{}`, wantErr: "needs a rule, a leaf or a var"},
+      		{name: "rule and leaf", json: `{"rule":"AND","leaf":"TRUE"}`, wantErr: "exactly one of"},
+      		{name: "leaf with children", json: `{"leaf":"TRUE","children":[{"leaf":"TRUE"}]}`, wantErr: "leaf expression has children"},
+      		{name: "bad leaf", json: `{"leaf":"MAYBE"}`, wantErr: `invalid leaf "MAYBE"`},
+      		{name: "bad child", json: `{"rule":"AND","children":[{}]}`, wantErr: "needs a rule"},
+      		{name: "not an object", json: `[]`, wantErr: "cannot unmarshal"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			var x Expr
+                    			err := json.Unmarshal([]byte(tt.json), &x)
+                    			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                              				t.Errorf("Unmarshal(%s) error = %v, want %q", tt.json, err, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExprString(t *testing.T) {
+  	x := Call("AND", Leaf(TRUE), Call("OR", Leaf(FALSE), Leaf(UNKNOWN)), Call("NOT"))
+  	if got, want := x.String(), "AND(TRUE, OR(FALSE, UNKNOWN), NOT())"; got != want {
+      		t.Errorf("String = %q, want %q", got, want)
+      	}
+  }
+
+func TestEvaluateExpr(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		expr       Expr
+      		want       Trit
+      		wantDepth  int
+      		wantReason string
+      	}{
+      		{name: "leaf", expr: Leaf(TRUE), want: TRUE, wantReason: "Constant TRUE"},
+      		{name: "flat", expr: Call("AND", Leaf(TRUE), Leaf(UNKNOWN)), want: UNKNOWN, wantReason: "evaluated to depth 0"},
+      		{name: "nested", expr: Call("AND", Leaf(TRUE), Call("OR", Leaf(FALSE), Leaf(UNKNOWN))), want: UNKNOWN, wantDepth: 1},
+      		{name: "deep", expr: Call("NOT", Call("NOT", Call("AND", Leaf(FALSE)))), want: FALSE, wantDepth: 2},
+      		{name: "missing rule", expr: Call("AND", Call("nope", Leaf(TRUE))), want: UNKNOWN, wantReason: "not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := NewEngine().EvaluateExpr(tt.expr)
+                    			if r.Value != tt.want || r.Depth != tt.wantDepth || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateExpr = %v depth %d %q, want %v depth %d containing %q",
+                                          					r.Value, r.Depth, r.Reason, tt.want, tt.wantDepth, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExprGraphviz(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		expr Expr
+      		want string
+      	}{
+      		{
+            			name: "leaf",
+            			expr: Leaf(TRUE),
+            			want: "digraph expr {\n" +
+            				"  n0 [label=\"█ TRUE\", shape=box];\n" +
+            				"}\n",
+            		},
+      		{
+            			name: "nested",
+            			expr: Call("AND", Leaf(TRUE), Call("OR", Leaf(FALSE), Leaf(UNKNOWN))),
+            			want: "digraph expr {\n" +
+            				"  n0 [label=\"AND\"];\n" +
+            				"  n1 [label=\"█ TRUE\", shape=box];\n" +
+            				"  n0 -> n1;\n" +
+            				"  n2 [label=\"OR\"];\n" +
+            				"  n3 [label=\"░ FALSE\", shape=box];\n" +
+            				"  n2 -> n3;\n" +
+            				"  n4 [label=\"▒ UNKNOWN\", shape=box];\n" +
+            				"  n2 -> n4;\n" +
+            				"  n0 -> n2;\n" +
+            				"}\n",
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := tt.expr.Graphviz(); got != tt.want {
+                              				t.Errorf("Graphviz =\n%s\nwant\n%s", got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExprSimplify(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		expr Expr
+      		want Expr
+      	}{
+      		{name: "leaf", expr: Leaf(UNKNOWN), want: Leaf(UNKNOWN)},
+      		{name: "variable", expr: Variable("x"), want: Variable("x")},
+      		{name: "constant AND", expr: Call("AND", Leaf(TRUE), Leaf(UNKNOWN)), want: Leaf(UNKNOWN)},
+      		{name: "constant OR", expr: Call("OR", Leaf(FALSE), Leaf(FALSE)), want: Leaf(FALSE)},
+      		{name: "constant NOT", expr: Call("NOT", Leaf(TRUE)), want: Leaf(FALSE)},
+      		{name: "AND with FALSE", expr: Call("AND", Variable("x"), Leaf(FALSE)), want: Leaf(FALSE)},
+      		{name: "OR with TRUE", expr: Call("OR", Call("CUSTOM", Leaf(TRUE)), Leaf(TRUE)), want: Leaf(TRUE)},
+      		{name: "AND short-circuits other rules", expr: Call("AND", Leaf(FALSE), Call("CUSTOM", Leaf(TRUE))), want: Leaf(FALSE)},
+      		{name: "other rules kept", expr: Call("CUSTOM", Call("OR", Leaf(FALSE), Call("NOT", Leaf(TRUE)))), want: Call("CUSTOM", Leaf(FALSE))},
+      		{name: "variables kept", expr: Call("AND", Variable("x"), Call("NOT", Leaf(FALSE))), want: Call("AND", Variable("x"), Leaf(TRUE))},
+      		{name: "AND with TRUE not dropped", expr: Call("AND", Variable("x"), Leaf(TRUE)), want: Call("AND", Variable("x"), Leaf(TRUE))},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := tt.expr.Simplify(); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("%v.Simplify() = %v, want %v", tt.expr, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestExprSimplifyEquivalent(t *testing.T) {
+  	exprs := []Expr{
+      		Call("AND", Leaf(TRUE), Call("OR", Leaf(FALSE), Leaf(UNKNOWN)), Call("NOT", Leaf(FALSE))),
+      		Call("OR", Call("AND", Leaf(UNKNOWN), Leaf(FALSE)), Call("NOT", Leaf(UNKNOWN))),
+      		Call("NOT", Call("OR", Leaf(TRUE), Call("CONSENSUS", Leaf(TRUE), Leaf(FALSE)))),
+      	}
+  	e := NewEngine()
+  	for _, x := range exprs {
+      		want := e.EvaluateExpr(x).Value
+      		if got := e.EvaluateExpr(x.Simplify()).Value; got != want {
+            			t.Errorf("EvaluateExpr(%v.Simplify()) = %v, want %v", x, got, want)
+            		}
+      	}
+  }
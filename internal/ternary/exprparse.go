@@ -0,0 +1,186 @@
+package ternary
+
+import (
+  	"fmt"
+  	"strings"
+  	"unicode"
+  	"unicode/utf8"
+  )
+
+// ParseExpr parses an expression in the call form produced by
+// Expr.String, e.g. "AND(OR(a, b), NOT(c))". A name followed by
+// parentheses applies the rule of that name; a bare TRUE, FALSE or UNKNOWN
+// (in any case) is a constant and any other bare name is a variable. Names
+// may contain Unicode letters and digits and the characters _ - . / :.
+func ParseExpr(s string) (Expr, error) {
+  	p := exprParser{src: s}
+  	x, err := p.expr()
+  	if err != nil {
+      		return Expr{}, err
+      	}
+  	p.skipSpace()
+  	if p.pos < len(p.src) {
+      		return Expr{}, p.errorf("unexpected %q", p.src[p.pos:])
+      	}
+  	return x, nil
+  }
+
+// exprParser is a recursive-descent parser over src
+type exprParser struct {
+  	src string
+  	pos int
+  }
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+  	return fmt.Errorf("ternary: expression %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+  }
+
+func (p *exprParser) skipSpace() {
+  	for p.pos < len(p.src) {
+      		r, size := p.rune()
+      		if !unicode.IsSpace(r) {
+            			return
+            		}
+      		p.pos += size
+      	}
+  }
+
+// rune decodes the rune at pos and its width in bytes; invalid UTF-8
+// decodes as utf8.RuneError of width 1
+func (p *exprParser) rune() (rune, int) {
+  	return utf8.DecodeRuneInString(p.src[p.pos:])
+  }
+
+// peek returns the next non-space byte, or 0 at the end
+func (p *exprParser) peek() byte {
+  	p.skipSpace()
+  	if p.pos == len(p.src) {
+      		return 0
+      	}
+  	return p.src[p.pos]
+  }
+
+// name reads an identifier
+func (p *exprParser) name() (string, error) {
+  	p.skipSpace()
+  	start := p.pos
+  	for p.pos < len(p.src) {
+      		r, size := p.rune()
+      		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-./:", r) {
+            			break
+            		}
+      		p.pos += size
+      	}
+  	if p.pos == start {
+      		if p.pos == len(p.src) {
+            			return "", p.errorf("unexpected end")
+            		}
+      		r, _ := p.rune()
+      		return "", p.errorf("expected a name, got %q", r)
+      	}
+  	return p.src[start:p.pos], nil
+  }
+
+// expr parses name | name "(" [expr {"," expr}] ")"
+func (p *exprParser) expr() (Expr, error) {
+  	name, err := p.name()
+  	if err != nil {
+      		return Expr{}, err
+      	}
+  	if p.peek() != '(' {
+      		if v, ok := tritFromName(name); ok {
+            			return Leaf(v), nil
+            		}
+      		return Variable(name), nil
+      	}
+  	p.pos++
+
+  	x := Call(name)
+  	if p.peek() == ')' {
+      		p.pos++
+      		return x, nil
+      	}
+  	for {
+      		arg, err := p.expr()
+      		if err != nil {
+            			return Expr{}, err
+            		}
+      		x.Children = append(x.Children, arg)
+      		switch p.peek() {
+            		case ',':
+            			p.pos++
+            		case ')':
+            			p.pos++
+            			return x, nil
+            		case 0:
+            			return Expr{}, p.errorf("unclosed call to %s", name)
+            		default:
+            			r, _ := p.rune()
+            			return Expr{}, p.errorf("expected ',' or ')', got %q", r)
+            		}
+      	}
+  }
+
+// Bind returns x with every variable replaced by its value in vars. A
+// variable missing from vars is an error.
+func (x Expr) Bind(vars map[string]Trit) (Expr, error) {
+  	if x.IsVar() {
+      		v, ok := vars[x.Var]
+      		if !ok {
+            			return Expr{}, fmt.Errorf("ternary: expression variable %s is unbound", x.Var)
+            		}
+      		return Leaf(v), nil
+      	}
+  	if x.IsLeaf() {
+      		return x, nil
+      	}
+  	bound := Expr{Rule: x.Rule, Children: make([]Expr, len(x.Children))}
+  	for i, c := range x.Children {
+      		b, err := c.Bind(vars)
+      		if err != nil {
+            			return Expr{}, err
+            		}
+      		bound.Children[i] = b
+      	}
+  	return bound, nil
+  }
+
+// EvalExpr parses expr with ParseExpr, binds its variables from vars and
+// evaluates it like EvaluateExpr, recording the root decision. Syntax
+// errors, unbound variables and calls to unregistered rules are errors,
+// reported before anything is evaluated; a disabled rule still yields an
+// UNKNOWN result.
+func (e *Engine) EvalExpr(expr string, vars map[string]Trit) (TernaryResult, error) {
+  	x, err := ParseExpr(expr)
+  	if err != nil {
+      		return TernaryResult{}, err
+      	}
+  	if x, err = x.Bind(vars); err != nil {
+      		return TernaryResult{}, err
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if name, ok := e.unknownRuleLocked(x); ok {
+      		return TernaryResult{}, fmt.Errorf("ternary: expression calls unknown rule %s", name)
+      	}
+  	return e.evaluateExprLocked(x), nil
+  }
+
+// unknownRuleLocked returns the first rule x calls that is not registered.
+// The caller must hold e.mu.
+func (e *Engine) unknownRuleLocked(x Expr) (string, bool) {
+  	if x.IsLeaf() {
+      		return "", false
+      	}
+  	if _, exists := e.rules[x.Rule]; !exists {
+      		return x.Rule, true
+      	}
+  	for _, c := range x.Children {
+      		if name, ok := e.unknownRuleLocked(c); ok {
+            			return name, true
+            		}
+      	}
+  	return "", false
+  }
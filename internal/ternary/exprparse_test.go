@@ -0,0 +1,103 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestParseExpr(t *testing.T) {
+  	tests := []struct {
+      		in      string
+      		want    Expr
+      		wantErr string
+      	}{
+      		{in: "a", want: Variable("a")},
+      		{in: "true", want: Leaf(TRUE)},
+      		{in: " Unknown ", want: Leaf(UNKNOWN)},
+      		{in: "NOT()", want: Call("NOT")},
+      		{in: "AND(OR(a, b), NOT(c))", want: Call("AND", Call("OR", Variable("a"), Variable("b")), Call("NOT", Variable("c")))},
+      		{in: " OR ( unknown , NOT(AND(x,TRUE)) )", want: Call("OR", Leaf(UNKNOWN), Call("NOT", Call("AND", Variable("x"), Leaf(TRUE))))},
+      		{in: "AND(sec/a, v-1.x:y_z)", want: Call("AND", Variable("sec/a"), Variable("v-1.x:y_z"))},
+      		{in: "AND(température, 温度2)", want: Call("AND", Variable("température"), Variable("温度2"))},
+      		{in: "\u00a0NOT(ünknown)\u3000", want: Call("NOT", Variable("ünknown"))},
+      		{in: "", wantErr: "offset 0: unexpected end"},
+      		{in: "AND(a", wantErr: "offset 5: unclosed call to AND"},
+      		{in: "AND(a,)", wantErr: `offset 6: expected a name, got ')'`},
+      		{in: "AND(a;b)", wantErr: `offset 5: expected ',' or ')', got ';'`},
+      		{in: "AND(a) b", wantErr: `offset 7: unexpected "b"`},
+      		{in: "(a)", wantErr: `expected a name, got '('`},
+      		{in: "AND(a, →b)", wantErr: `offset 7: expected a name, got '→'`},
+      		{in: "AND(a→b)", wantErr: `offset 5: expected ',' or ')', got '→'`},
+      		{in: "AND(\xff)", wantErr: "offset 4: expected a name, got '\ufffd'"},
+      	}
+  	for _, tt := range tests {
+      		got, err := ParseExpr(tt.in)
+      		if tt.wantErr != "" {
+            			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                    				t.Errorf("ParseExpr(%q) = %v, %v, want error %q", tt.in, got, err, tt.wantErr)
+                    			}
+            			continue
+            		}
+      		if err != nil || !reflect.DeepEqual(got, tt.want) {
+            			t.Errorf("ParseExpr(%q) = %#v, %v, want %#v", tt.in, got, err, tt.want)
+            		}
+      	}
+  }
+
+func TestParseExprRoundTrip(t *testing.T) {
+  	exprs := []Expr{
+      		Call("AND", Variable("sec/a"), Leaf(UNKNOWN), Call("OR")),
+      		Call("NOT", Call("NOT", Leaf(FALSE))),
+      		Variable("x"),
+      	}
+  	for _, x := range exprs {
+      		if y, err := ParseExpr(x.String()); err != nil || !reflect.DeepEqual(x, y) {
+            			t.Errorf("ParseExpr(%q) = %v, %v, want %v", x.String(), y, err, x)
+            		}
+      	}
+  }
+
+func TestEvalExpr(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		expr      string
+      		vars      map[string]Trit
+      		want      Trit
+      		wantDepth int
+      		wantErr   string
+      	}{
+      		{name: "flat", expr: "AND(a, b)", vars: map[string]Trit{"a": TRUE, "b": UNKNOWN}, want: UNKNOWN},
+      		{name: "nested", expr: "AND(OR(a, b), NOT(c))", vars: map[string]Trit{"a": FALSE, "b": TRUE, "c": FALSE}, want: TRUE, wantDepth: 1},
+      		{name: "constants", expr: " OR ( unknown , NOT(AND(x,TRUE)) )", vars: map[string]Trit{"x": TRUE}, want: UNKNOWN, wantDepth: 2},
+      		{name: "unused variables", expr: "NOT(a)", vars: map[string]Trit{"a": TRUE, "z": FALSE}, want: FALSE},
+      		{name: "unbound", expr: "AND(a, b)", vars: map[string]Trit{"a": TRUE}, wantErr: "variable b is unbound"},
+      		{name: "unknown rule", expr: "AND(FOO(a))", vars: map[string]Trit{"a": TRUE}, wantErr: "calls unknown rule FOO"},
+      		{name: "syntax", expr: "AND(a", vars: map[string]Trit{"a": TRUE}, wantErr: "unclosed call"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r, err := e.EvalExpr(tt.expr, tt.vars)
+                    			recorded := len(e.GetDecisions(DecisionFilter{}))
+                    			if tt.wantErr != "" {
+                              				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || recorded != 0 {
+                                          					t.Errorf("EvalExpr(%q) = %v, %v with %d recorded, want error %q", tt.expr, r.Value, err, recorded, tt.wantErr)
+                                          				}
+                              				return
+                              			}
+                    			if err != nil || r.Value != tt.want || r.Depth != tt.wantDepth || recorded != 1 {
+                              				t.Errorf("EvalExpr(%q) = %v depth %d, %v with %d recorded, want %v depth %d", tt.expr, r.Value, r.Depth, err, recorded, tt.want, tt.wantDepth)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvalExprDisabledRule(t *testing.T) {
+  	e := NewEngine()
+  	e.DisableRule("OR")
+  	r, err := e.EvalExpr("AND(OR(a), TRUE)", map[string]Trit{"a": TRUE})
+  	if err != nil || r.Value != UNKNOWN || !strings.Contains(r.Reason, "disabled") {
+      		t.Errorf("EvalExpr = %v %q, %v, want UNKNOWN naming the disabled rule", r.Value, r.Reason, err)
+      	}
+  }
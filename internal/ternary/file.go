@@ -0,0 +1,33 @@
+package ternary
+
+import (
+  	"fmt"
+  	"os"
+  	"path/filepath"
+  )
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, so readers see either the old or the new contents in full
+func writeFileAtomic(path string, data []byte) error {
+  	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+  	if err != nil {
+      		return fmt.Errorf("ternary: write %s: %w", path, err)
+      	}
+  	defer os.Remove(tmp.Name())
+
+  	if _, err := tmp.Write(data); err != nil {
+      		tmp.Close()
+      		return fmt.Errorf("ternary: write %s: %w", path, err)
+      	}
+  	if err := tmp.Sync(); err != nil {
+      		tmp.Close()
+      		return fmt.Errorf("ternary: write %s: %w", path, err)
+      	}
+  	if err := tmp.Close(); err != nil {
+      		return fmt.Errorf("ternary: write %s: %w", path, err)
+      	}
+  	if err := os.Rename(tmp.Name(), path); err != nil {
+      		return fmt.Errorf("ternary: write %s: %w", path, err)
+      	}
+  	return nil
+  }
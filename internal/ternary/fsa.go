@@ -0,0 +1,88 @@
+package ternary
+
+// fsaKey identifies a transition out of a state on an input trit
+type fsaKey struct {
+  	from  string
+  	input Trit
+  }
+
+// TritFSA is a finite-state acceptor over trit sequences. Transitions are
+// keyed by input trit. An UNKNOWN input with no explicit UNKNOWN transition
+// is treated as superposition: the automaton follows both its TRUE and FALSE
+// transitions and tracks every state it could be in.
+type TritFSA struct {
+  	start       string
+  	accept      map[string]bool
+  	transitions map[fsaKey]string
+  }
+
+// NewTritFSA creates an automaton starting in start and accepting in any of
+// the accepting states
+func NewTritFSA(start string, accepting ...string) *TritFSA {
+  	f := &TritFSA{
+      		start:       start,
+      		accept:      make(map[string]bool, len(accepting)),
+      		transitions: make(map[fsaKey]string),
+      	}
+  	for _, s := range accepting {
+      		f.accept[s] = true
+      	}
+  	return f
+  }
+
+// AddTransition moves from state from to state to on input
+func (f *TritFSA) AddTransition(from string, input Trit, to string) {
+  	f.transitions[fsaKey{from: from, input: input}] = to
+  }
+
+// Accepts runs inputs through the automaton. It returns TRUE if every state
+// the automaton may end in is accepting, FALSE if none is (including when
+// no transition applies), and UNKNOWN when UNKNOWN inputs left it in a mix
+// of accepting and rejecting states.
+func (f *TritFSA) Accepts(inputs []Trit) Trit {
+  	current := map[string]bool{f.start: true}
+  	for _, in := range inputs {
+      		next := make(map[string]bool)
+      		for state := range current {
+            			for _, to := range f.step(state, in) {
+                    				next[to] = true
+                    			}
+            		}
+      		if len(next) == 0 {
+            			return FALSE
+            		}
+      		current = next
+      	}
+
+  	accepting := 0
+  	for state := range current {
+      		if f.accept[state] {
+            			accepting++
+            		}
+      	}
+  	switch accepting {
+      	case len(current):
+      		return TRUE
+      	case 0:
+      		return FALSE
+      	default:
+      		return UNKNOWN
+      	}
+  }
+
+// step returns the states reachable from state on one input
+func (f *TritFSA) step(state string, in Trit) []string {
+  	if to, ok := f.transitions[fsaKey{from: state, input: in}]; ok {
+      		return []string{to}
+      	}
+  	if in != UNKNOWN {
+      		return nil
+      	}
+  	var targets []string
+  	for _, branch := range []Trit{TRUE, FALSE} {
+      		if to, ok := f.transitions[fsaKey{from: state, input: branch}]; ok {
+            			targets = append(targets, to)
+            		}
+      	}
+  	return targets
+  }
@@ -0,0 +1,64 @@
+package ternary
+
+import "testing"
+
+func TestTritFSA(t *testing.T) {
+  	// "ends in TRUE": q1 is reached by the last TRUE
+  	lastTrue := func() *TritFSA {
+      		f := NewTritFSA("q0", "q1")
+      		f.AddTransition("q0", TRUE, "q1")
+      		f.AddTransition("q0", FALSE, "q0")
+      		f.AddTransition("q1", TRUE, "q1")
+      		f.AddTransition("q1", FALSE, "q0")
+      		return f
+      	}
+  	tests := []struct {
+      		name   string
+      		fsa    func() *TritFSA
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{name: "empty input in rejecting start", fsa: lastTrue, want: FALSE},
+      		{name: "accepted", fsa: lastTrue, inputs: []Trit{FALSE, TRUE}, want: TRUE},
+      		{name: "rejected", fsa: lastTrue, inputs: []Trit{TRUE, FALSE}, want: FALSE},
+      		{name: "superposition undecided", fsa: lastTrue, inputs: []Trit{TRUE, UNKNOWN}, want: UNKNOWN},
+      		{name: "superposition resolved", fsa: lastTrue, inputs: []Trit{UNKNOWN, TRUE}, want: TRUE},
+      		{
+            			name: "explicit UNKNOWN transition",
+            			fsa: func() *TritFSA {
+                    				f := lastTrue()
+                    				f.AddTransition("q0", UNKNOWN, "q0")
+                    				return f
+                    			},
+            			inputs: []Trit{UNKNOWN},
+            			want:   FALSE,
+            		},
+      		{
+            			name: "no transition",
+            			fsa: func() *TritFSA {
+                    				f := NewTritFSA("s", "s")
+                    				f.AddTransition("s", TRUE, "s")
+                    				return f
+                    			},
+            			inputs: []Trit{TRUE, FALSE},
+            			want:   FALSE,
+            		},
+      		{
+            			name: "superposition with one branch",
+            			fsa: func() *TritFSA {
+                    				f := NewTritFSA("s", "s")
+                    				f.AddTransition("s", TRUE, "s")
+                    				return f
+                    			},
+            			inputs: []Trit{UNKNOWN},
+            			want:   TRUE,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := tt.fsa().Accepts(tt.inputs); got != tt.want {
+                              				t.Errorf("Accepts(%v) = %v, want %v", tt.inputs, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
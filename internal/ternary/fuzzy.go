@@ -0,0 +1,54 @@
+package ternary
+
+import (
+  	"fmt"
+  	"math"
+  )
+
+// Fuzzy is a truth degree in [0, 1] under Łukasiewicz fuzzy logic, for
+// pipelines that work on continuous confidences and discretize at the end
+// with ToTrit. Operands are expected to lie in [0, 1].
+type Fuzzy float64
+
+// And is the Łukasiewicz t-norm max(0, a+b-1)
+func (a Fuzzy) And(b Fuzzy) Fuzzy {
+  	return Fuzzy(math.Max(0, float64(a+b-1)))
+  }
+
+// Or is the Łukasiewicz t-conorm min(1, a+b)
+func (a Fuzzy) Or(b Fuzzy) Fuzzy {
+  	return Fuzzy(math.Min(1, float64(a+b)))
+  }
+
+// Not is 1-a
+func (a Fuzzy) Not() Fuzzy {
+  	return 1 - a
+  }
+
+// Implies is the Łukasiewicz implication min(1, 1-a+b)
+func (a Fuzzy) Implies(b Fuzzy) Fuzzy {
+  	return Fuzzy(math.Min(1, float64(1-a+b)))
+  }
+
+// ToTrit discretizes a: FALSE at or below lowThresh, TRUE at or above
+// highThresh, UNKNOWN in between. ToTrit returns an error unless
+// 0 <= lowThresh <= highThresh <= 1.
+func (a Fuzzy) ToTrit(lowThresh, highThresh float64) (Trit, error) {
+  	if !(lowThresh >= 0 && lowThresh <= highThresh && highThresh <= 1) {
+      		return UNKNOWN, fmt.Errorf("ternary: Fuzzy thresholds %v, %v not ordered within [0, 1]", lowThresh, highThresh)
+      	}
+
+  	switch v := float64(a); {
+      	case v <= lowThresh:
+      		return FALSE, nil
+      	case v >= highThresh:
+      		return TRUE, nil
+      	default:
+      		return UNKNOWN, nil
+      	}
+  }
+
+// FuzzyOf lifts a trit into fuzzy logic: FALSE is 0, UNKNOWN 0.5, TRUE 1
+func FuzzyOf(t Trit) Fuzzy {
+  	return Fuzzy(float64(t+1) / 2)
+  }
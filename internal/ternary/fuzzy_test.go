@@ -0,0 +1,144 @@
+package ternary
+
+import (
+  	"math"
+  	"strings"
+  	"testing"
+  )
+
+// nearFuzzy reports whether a and b agree up to rounding
+func nearFuzzy(a, b Fuzzy) bool {
+  	return math.Abs(float64(a-b)) < 1e-12
+  }
+
+func TestFuzzyOperators(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		got  Fuzzy
+      		want Fuzzy
+      	}{
+      		{name: "And overlap", got: Fuzzy(0.7).And(0.6), want: 0.3},
+      		{name: "And floors at 0", got: Fuzzy(0.3).And(0.4), want: 0},
+      		{name: "Or sums", got: Fuzzy(0.2).Or(0.3), want: 0.5},
+      		{name: "Or caps at 1", got: Fuzzy(0.7).Or(0.6), want: 1},
+      		{name: "Not", got: Fuzzy(0.25).Not(), want: 0.75},
+      		{name: "Implies drop", got: Fuzzy(0.9).Implies(0.4), want: 0.5},
+      		{name: "Implies caps at 1", got: Fuzzy(0.4).Implies(0.9), want: 1},
+      	}
+  	for _, tt := range tests {
+      		if !nearFuzzy(tt.got, tt.want) {
+            			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+            		}
+      	}
+  }
+
+func TestFuzzyIdentities(t *testing.T) {
+  	vals := []Fuzzy{0, 0.1, 0.25, 0.5, 0.75, 0.9, 1}
+  	laws := []struct {
+      		name  string
+      		holds func(a, b Fuzzy) bool
+      	}{
+      		{"double negation", func(a, _ Fuzzy) bool { return nearFuzzy(a.Not().Not(), a) }},
+      		{"And identity", func(a, _ Fuzzy) bool { return nearFuzzy(a.And(1), a) }},
+      		{"Or identity", func(a, _ Fuzzy) bool { return nearFuzzy(a.Or(0), a) }},
+      		{"excluded middle", func(a, _ Fuzzy) bool { return nearFuzzy(a.Or(a.Not()), 1) }},
+      		{"non-contradiction", func(a, _ Fuzzy) bool { return nearFuzzy(a.And(a.Not()), 0) }},
+      		{"self implication", func(a, _ Fuzzy) bool { return nearFuzzy(a.Implies(a), 1) }},
+      		{"De Morgan", func(a, b Fuzzy) bool { return nearFuzzy(a.And(b).Not(), a.Not().Or(b.Not())) }},
+      		{"material implication", func(a, b Fuzzy) bool { return nearFuzzy(a.Implies(b), a.Not().Or(b)) }},
+      		{"residuation", func(a, b Fuzzy) bool { return nearFuzzy(a.And(a.Implies(b)), min(a, b)) }},
+      		{"And commutes", func(a, b Fuzzy) bool { return nearFuzzy(a.And(b), b.And(a)) }},
+      		{"Or commutes", func(a, b Fuzzy) bool { return nearFuzzy(a.Or(b), b.Or(a)) }},
+      	}
+  	for _, law := range laws {
+      		for _, a := range vals {
+            			for _, b := range vals {
+                    				if !law.holds(a, b) {
+                              					t.Errorf("%s fails for %v, %v", law.name, a, b)
+                              				}
+                    			}
+            		}
+      	}
+  }
+
+func TestFuzzyMatchesLukasiewiczRules(t *testing.T) {
+  	e := NewEngine(WithLogicSystem(LogicLukasiewicz))
+  	trits := []Trit{FALSE, UNKNOWN, TRUE}
+  	for _, a := range trits {
+      		for _, b := range trits {
+            			fa, fb := FuzzyOf(a), FuzzyOf(b)
+            			if got, want := fa.And(fb), FuzzyOf(e.Evaluate("AND", a, b).Value); got != want {
+                    				t.Errorf("FuzzyOf(%v).And(%v) = %v, Lukasiewicz AND gives %v", a, b, got, want)
+                    			}
+            			if got, want := fa.Or(fb), FuzzyOf(e.Evaluate("OR", a, b).Value); got != want {
+                    				t.Errorf("FuzzyOf(%v).Or(%v) = %v, Lukasiewicz OR gives %v", a, b, got, want)
+                    			}
+            			if got, want := fa.Implies(fb), FuzzyOf(e.Evaluate("IMPLIES", a, b).Value); got != want {
+                    				t.Errorf("FuzzyOf(%v).Implies(%v) = %v, Lukasiewicz IMPLIES gives %v", a, b, got, want)
+                    			}
+            		}
+      		if got, want := FuzzyOf(a).Not(), FuzzyOf(e.Evaluate("NOT", a).Value); got != want {
+            			t.Errorf("FuzzyOf(%v).Not() = %v, NOT gives %v", a, got, want)
+            		}
+      	}
+  }
+
+func TestFuzzyToTrit(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		a         Fuzzy
+      		low, high float64
+      		want      Trit
+      	}{
+      		{name: "at low", a: 0.3, low: 0.3, high: 0.7, want: FALSE},
+      		{name: "just above low", a: Fuzzy(math.Nextafter(0.3, 1)), low: 0.3, high: 0.7, want: UNKNOWN},
+      		{name: "middle", a: 0.5, low: 0.3, high: 0.7, want: UNKNOWN},
+      		{name: "just below high", a: Fuzzy(math.Nextafter(0.7, 0)), low: 0.3, high: 0.7, want: UNKNOWN},
+      		{name: "at high", a: 0.7, low: 0.3, high: 0.7, want: TRUE},
+      		{name: "zero", a: 0, low: 0, high: 1, want: FALSE},
+      		{name: "one", a: 1, low: 0, high: 1, want: TRUE},
+      		{name: "equal thresholds below", a: 0.4, low: 0.5, high: 0.5, want: FALSE},
+      		{name: "equal thresholds at", a: 0.5, low: 0.5, high: 0.5, want: FALSE},
+      		{name: "equal thresholds above", a: 0.6, low: 0.5, high: 0.5, want: TRUE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := tt.a.ToTrit(tt.low, tt.high)
+                    			if err != nil || got != tt.want {
+                              				t.Errorf("Fuzzy(%v).ToTrit(%v, %v) = %v, %v, want %v", tt.a, tt.low, tt.high, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestFuzzyToTritErrors(t *testing.T) {
+  	tests := []struct {
+      		low, high float64
+      	}{
+      		{low: 0.8, high: 0.2},
+      		{low: -0.1, high: 0.2},
+      		{low: 0.1, high: 1.1},
+      		{low: 0.1, high: math.NaN()},
+      	}
+  	for _, tt := range tests {
+      		got, err := Fuzzy(0.5).ToTrit(tt.low, tt.high)
+      		if err == nil || !strings.Contains(err.Error(), "not ordered within [0, 1]") || got != UNKNOWN {
+            			t.Errorf("ToTrit(%v, %v) = %v, %v, want UNKNOWN and a threshold error", tt.low, tt.high, got, err)
+            		}
+      	}
+  }
+
+func TestFuzzyOf(t *testing.T) {
+  	tests := []struct {
+      		t    Trit
+      		want Fuzzy
+      	}{{FALSE, 0}, {UNKNOWN, 0.5}, {TRUE, 1}}
+  	for _, tt := range tests {
+      		if got := FuzzyOf(tt.t); got != tt.want {
+            			t.Errorf("FuzzyOf(%v) = %v, want %v", tt.t, got, tt.want)
+            		}
+      		if got, _ := FuzzyOf(tt.t).ToTrit(0.25, 0.75); got != tt.t {
+            			t.Errorf("FuzzyOf(%v).ToTrit(0.25, 0.75) = %v, want the trit back", tt.t, got)
+            		}
+      	}
+  }
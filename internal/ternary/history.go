@@ -0,0 +1,157 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"fmt"
+  	"os"
+  	"time"
+  )
+
+// DefaultHistoryCapacity is the number of decisions NewEngine retains
+const DefaultHistoryCapacity = 1024
+
+// The history is a ring buffer: e.decisions grows up to its capacity, after
+// which each new decision overwrites the oldest one at e.head.
+
+// appendLocked adds result to the history, overwriting the oldest decision
+// when it is full. The caller must hold e.mu.
+func (e *Engine) appendLocked(result TernaryResult) {
+  	e.lifetime++
+  	e.version++
+  	if len(e.decisions) < cap(e.decisions) {
+      		e.decisions = append(e.decisions, result)
+      		return
+      	}
+  	e.decisions[e.head] = result
+  	e.head = (e.head + 1) % len(e.decisions)
+  }
+
+// resetHistoryLocked replaces the history with results, keeping the newest
+// that fit. The caller must hold e.mu.
+func (e *Engine) resetHistoryLocked(results []TernaryResult) {
+  	if n := cap(e.decisions); len(results) > n {
+      		results = results[len(results)-n:]
+      	}
+  	clear(e.decisions)
+  	e.decisions = append(e.decisions[:0], results...)
+  	e.head = 0
+  	e.lifetime = uint64(len(results))
+  	e.version++
+  }
+
+// historyLocked returns the retained decisions, oldest first. The slice may
+// alias engine state: callers must not modify it or keep it past the lock.
+// The caller must hold e.mu.
+func (e *Engine) historyLocked() []TernaryResult {
+  	if e.head == 0 {
+      		return e.decisions
+      	}
+  	ordered := make([]TernaryResult, 0, len(e.decisions))
+  	ordered = append(ordered, e.decisions[e.head:]...)
+  	return append(ordered, e.decisions[:e.head]...)
+  }
+
+// ExportColumns returns the retained decisions as parallel column slices,
+// oldest first, for feeding columnar builders such as Apache Arrow. Index i
+// of every slice describes the same decision.
+func (e *Engine) ExportColumns() (ids []string, values []Trit, confidences []float64, timestamps []time.Time, reasons []string) {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	history := e.historyLocked()
+  	n := len(history)
+  	ids = make([]string, n)
+  	values = make([]Trit, n)
+  	confidences = make([]float64, n)
+  	timestamps = make([]time.Time, n)
+  	reasons = make([]string, n)
+  	for i, d := range history {
+      		ids[i] = d.ID
+      		values[i] = d.Value
+      		confidences[i] = d.Confidence
+      		timestamps[i] = d.Timestamp
+      		reasons[i] = d.Reason
+      	}
+  	return ids, values, confidences, timestamps, reasons
+  }
+
+// DecisionFilter selects decisions in GetDecisions. Zero-valued fields do
+// not filter, so the zero DecisionFilter matches every decision.
+type DecisionFilter struct {
+  	Value         *Trit     // only decisions with this value
+  	MinConfidence float64   // only confidence >= MinConfidence, if nonzero
+  	MaxConfidence float64   // only confidence <= MaxConfidence, if nonzero
+  	Since         time.Time // only decisions at or after Since, if set
+  	RuleName      string    // only decisions of this rule, if set
+  }
+
+// matches reports whether d passes the filter
+func (f DecisionFilter) matches(d TernaryResult) bool {
+  	switch {
+      	case f.Value != nil && d.Value != *f.Value:
+      		return false
+      	case f.MinConfidence != 0 && d.Confidence < f.MinConfidence:
+      		return false
+      	case f.MaxConfidence != 0 && d.Confidence > f.MaxConfidence:
+      		return false
+      	case !f.Since.IsZero() && d.Timestamp.Before(f.Since):
+      		return false
+      	case f.RuleName != "" && d.Rule != f.RuleName:
+      		return false
+      	}
+  	return true
+  }
+
+// GetDecisions returns copies of the retained decisions matching filter,
+// oldest first
+func (e *Engine) GetDecisions(filter DecisionFilter) []TernaryResult {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	var out []TernaryResult
+  	for _, d := range e.historyLocked() {
+      		if !filter.matches(d) {
+            			continue
+            		}
+      		d.Inputs = append([]Trit(nil), d.Inputs...)
+      		d.Meta = copyMeta(d.Meta)
+      		d.Labels = copyLabels(d.Labels)
+      		out = append(out, d)
+      	}
+  	return out
+  }
+
+// SaveHistory writes the retained decisions, oldest first, to path as a
+// JSON array, replacing the file atomically
+func (e *Engine) SaveHistory(path string) error {
+  	e.mu.RLock()
+  	data, err := json.Marshal(e.historyLocked())
+  	e.mu.RUnlock()
+  	if err != nil {
+      		return fmt.Errorf("ternary: encode history: %w", err)
+      	}
+  	return writeFileAtomic(path, data)
+  }
+
+// LoadHistory replaces the decision history with the one saved at path by
+// SaveHistory, keeping the newest decisions that fit
+func (e *Engine) LoadHistory(path string) error {
+  	data, err := os.ReadFile(path)
+  	if err != nil {
+      		return fmt.Errorf("ternary: read history: %w", err)
+      	}
+  	var results []TernaryResult
+  	if err := json.Unmarshal(data, &results); err != nil {
+      		return fmt.Errorf("ternary: decode history %s: %w", path, err)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.resetHistoryLocked(results)
+  	clear(e.lastValue)
+  	for _, r := range results {
+      		e.lastValue[r.Rule] = r.Value
+      	}
+  	return nil
+  }
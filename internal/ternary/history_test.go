@@ -0,0 +1,101 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  	"time"
+  )
+
+func TestExportColumns(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		capacity int
+      		evals    int
+      		want     int
+      	}{
+      		{name: "empty", capacity: 4, evals: 0, want: 0},
+      		{name: "partial", capacity: 4, evals: 3, want: 3},
+      		{name: "full", capacity: 4, evals: 4, want: 4},
+      		{name: "wrapped", capacity: 4, evals: 7, want: 4},
+      	}
+  	rules := []string{"AND", "OR", "NOT"}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e, err := NewEngineWithCapacity(tt.capacity)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			for i := 0; i < tt.evals; i++ {
+                              				e.Evaluate(rules[i%len(rules)], Trit(i%3-1))
+                              			}
+                    			ids, values, confs, times, reasons := e.ExportColumns()
+                    			if len(ids) != tt.want || len(values) != tt.want || len(confs) != tt.want || len(times) != tt.want || len(reasons) != tt.want {
+                              				t.Fatalf("column lengths %d %d %d %d %d, want %d", len(ids), len(values), len(confs), len(times), len(reasons), tt.want)
+                              			}
+                    			for i, d := range e.historyLocked() {
+                              				if ids[i] != d.ID || values[i] != d.Value || confs[i] != d.Confidence || !times[i].Equal(d.Timestamp) || reasons[i] != d.Reason {
+                                          					t.Errorf("row %d = %s %v %v %v %q, want %+v", i, ids[i], values[i], confs[i], times[i], reasons[i], d)
+                                          				}
+                              			}
+                    			for i := 1; i < len(times); i++ {
+                              				if times[i].Before(times[i-1]) {
+                                          					t.Errorf("row %d at %v is older than row %d at %v", i, times[i], i-1, times[i-1])
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestGetDecisions(t *testing.T) {
+  	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  	newEngine := func() *Engine {
+      		now := t0
+      		e := NewEngine(CaptureInputs(), WithClock(func() time.Time {
+                              			defer func() { now = now.Add(time.Minute) }()
+                              			return now
+                              		}))
+      		e.Evaluate("AND", TRUE)                     // TRUE, 1
+      		e.EvaluateWeight("CONSENSUS", 0.5, UNKNOWN) // UNKNOWN, 0.25
+      		e.Evaluate("OR", UNKNOWN)                   // UNKNOWN, 0.5
+      		e.Evaluate("NOT", TRUE)                     // FALSE, 0
+      		return e
+      	}
+  	unknown := UNKNOWN
+  	tests := []struct {
+      		name   string
+      		filter DecisionFilter
+      		want   []string
+      	}{
+      		{name: "all", want: []string{"AND", "CONSENSUS", "OR", "NOT"}},
+      		{name: "value", filter: DecisionFilter{Value: &unknown}, want: []string{"CONSENSUS", "OR"}},
+      		{name: "min confidence", filter: DecisionFilter{MinConfidence: 0.4}, want: []string{"AND", "OR"}},
+      		{name: "max confidence", filter: DecisionFilter{MaxConfidence: 0.3}, want: []string{"CONSENSUS", "NOT"}},
+      		{name: "since", filter: DecisionFilter{Since: t0.Add(2 * time.Minute)}, want: []string{"OR", "NOT"}},
+      		{name: "rule", filter: DecisionFilter{RuleName: "OR"}, want: []string{"OR"}},
+      		{name: "combined", filter: DecisionFilter{Value: &unknown, MaxConfidence: 0.3, Since: t0}, want: []string{"CONSENSUS"}},
+      		{name: "none", filter: DecisionFilter{RuleName: "XOR"}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			var got []string
+                    			for _, d := range newEngine().GetDecisions(tt.filter) {
+                              				got = append(got, d.Rule)
+                              			}
+                    			if !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("GetDecisions(%+v) rules = %v, want %v", tt.filter, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestGetDecisionsCopies(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	e.EvaluateMeta(map[string]string{"k": "v"}, "AND", UNKNOWN)
+  	got := e.GetDecisions(DecisionFilter{})
+  	got[0].Inputs[0] = TRUE
+  	got[0].Meta["k"] = "changed"
+  	d := e.historyLocked()[0]
+  	if d.Inputs[0] != UNKNOWN || d.Meta["k"] != "v" {
+      		t.Errorf("GetDecisions aliased the history: %+v", d)
+      	}
+  }
@@ -0,0 +1,80 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"errors"
+  	"fmt"
+  	"net/http"
+  )
+
+// maxEvaluateBody is the largest POST /evaluate body HTTPHandler accepts
+const maxEvaluateBody = 64 * 1024
+
+// evaluateRequest is the body accepted by POST /evaluate
+type evaluateRequest struct {
+  	Rule   string   `json:"rule"`
+  	Inputs []string `json:"inputs"`
+  }
+
+// HTTPHandler exposes the engine over HTTP:
+//
+//	GET  /stats     Stats as JSON
+//	GET  /rules     RulesJSON
+//	POST /evaluate  {"rule":"AND","inputs":["TRUE","UNKNOWN"]} -> TernaryResult
+//
+// An /evaluate body over 64 KiB is rejected with 413.
+func (e *Engine) HTTPHandler() http.Handler {
+  	mux := http.NewServeMux()
+
+  	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+            		writeJSON(w, e.Stats())
+            	})
+
+  	mux.HandleFunc("GET /rules", func(w http.ResponseWriter, r *http.Request) {
+            		data, err := e.RulesJSON()
+            		if err != nil {
+                    			http.Error(w, err.Error(), http.StatusInternalServerError)
+                    			return
+                    		}
+            		w.Header().Set("Content-Type", "application/json")
+            		w.Write(data)
+            	})
+
+  	mux.HandleFunc("POST /evaluate", func(w http.ResponseWriter, r *http.Request) {
+            		var req evaluateRequest
+            		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEvaluateBody)).Decode(&req)
+            		var tooLarge *http.MaxBytesError
+            		if errors.As(err, &tooLarge) {
+                    			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+                    			return
+                    		}
+            		if err != nil {
+                    			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+                    			return
+                    		}
+            		if req.Rule == "" {
+                    			http.Error(w, "missing rule", http.StatusBadRequest)
+                    			return
+                    		}
+            		inputs := make([]Trit, len(req.Inputs))
+            		for i, s := range req.Inputs {
+                    			t, ok := tritFromName(s)
+                    			if !ok {
+                              				http.Error(w, fmt.Sprintf("invalid input %d: %q", i, s), http.StatusBadRequest)
+                              				return
+                              			}
+                    			inputs[i] = t
+                    		}
+            		writeJSON(w, e.Evaluate(req.Rule, inputs...))
+            	})
+
+  	return mux
+  }
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+  	w.Header().Set("Content-Type", "application/json")
+  	if err := json.NewEncoder(w).Encode(v); err != nil {
+      		http.Error(w, err.Error(), http.StatusInternalServerError)
+      	}
+  }
@@ -0,0 +1,63 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"net/http"
+  	"net/http/httptest"
+  	"strings"
+  	"testing"
+  )
+
+func TestHTTPHandler(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		method     string
+      		path       string
+      		body       string
+      		wantStatus int
+      		wantBody   string
+      	}{
+      		{name: "stats", method: "GET", path: "/stats", wantStatus: http.StatusOK, wantBody: `"total_evaluations"`},
+      		{name: "rules", method: "GET", path: "/rules", wantStatus: http.StatusOK, wantBody: "CONSENSUS"},
+      		{name: "evaluate", method: "POST", path: "/evaluate", body: `{"rule":"AND","inputs":["TRUE","FALSE"]}`, wantStatus: http.StatusOK, wantBody: `"value":"FALSE"`},
+      		{name: "bad body", method: "POST", path: "/evaluate", body: `{`, wantStatus: http.StatusBadRequest, wantBody: "invalid request body"},
+      		{name: "missing rule", method: "POST", path: "/evaluate", body: `{"inputs":["TRUE"]}`, wantStatus: http.StatusBadRequest, wantBody: "missing rule"},
+      		{name: "bad input", method: "POST", path: "/evaluate", body: `{"rule":"AND","inputs":["TRUE","MAYBE"]}`, wantStatus: http.StatusBadRequest, wantBody: `invalid input 1: "MAYBE"`},
+      		{
+            			name:       "body too large",
+            			method:     "POST",
+            			path:       "/evaluate",
+            			body:       `{"rule":"AND","inputs":["TRUE"` + strings.Repeat(`,"TRUE"`, maxEvaluateBody/7) + `]}`,
+            			wantStatus: http.StatusRequestEntityTooLarge,
+            			wantBody:   "request body too large",
+            		},
+      		{name: "wrong method", method: "GET", path: "/evaluate", wantStatus: http.StatusMethodNotAllowed},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			h := NewEngine().HTTPHandler()
+                    			rec := httptest.NewRecorder()
+                    			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+                    			if rec.Code != tt.wantStatus || !strings.Contains(rec.Body.String(), tt.wantBody) {
+                              				t.Errorf("%s %s = %d %q, want %d containing %q", tt.method, tt.path, rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+                              			}
+                    		})
+      	}
+  }
+
+func TestHTTPEvaluateRecords(t *testing.T) {
+  	e := NewEngine()
+  	rec := httptest.NewRecorder()
+  	e.HTTPHandler().ServeHTTP(rec, httptest.NewRequest("POST", "/evaluate", strings.NewReader(`{"rule":"OR","inputs":["UNKNOWN","TRUE"]}`)))
+
+  	var r TernaryResult
+  	if err := json.Unmarshal(rec.Body.Bytes(), &r); err != nil {
+      		t.Fatal(err)
+      	}
+  	if r.Value != TRUE || r.Rule != "OR" {
+      		t.Errorf("result = %v from %q, want TRUE from OR", r.Value, r.Rule)
+      	}
+  	if got := e.GetDecisions(DecisionFilter{}); len(got) != 1 || got[0].ID != r.ID {
+      		t.Errorf("history = %v, want the returned decision", got)
+      	}
+  }
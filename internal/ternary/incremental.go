@@ -0,0 +1,75 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sync"
+  	"time"
+
+  	"github.com/google/uuid"
+  )
+
+// IncrementalConsensus keeps CONSENSUS over a set of agents' votes up to
+// date as individual votes change. Each update adjusts running counts, so
+// both SetVote and Current are O(1) regardless of the number of agents.
+type IncrementalConsensus struct {
+  	mu     sync.Mutex
+  	votes  map[string]Trit
+  	counts map[Trit]int
+  }
+
+// NewIncrementalConsensus returns an IncrementalConsensus with no votes
+func NewIncrementalConsensus() *IncrementalConsensus {
+  	return &IncrementalConsensus{
+      		votes:  make(map[string]Trit),
+      		counts: make(map[Trit]int),
+      	}
+  }
+
+// SetVote sets or replaces the vote of agentID
+func (c *IncrementalConsensus) SetVote(agentID string, vote Trit) {
+  	c.mu.Lock()
+  	defer c.mu.Unlock()
+
+  	if old, ok := c.votes[agentID]; ok {
+      		c.counts[old]--
+      	}
+  	c.votes[agentID] = vote
+  	c.counts[vote]++
+  }
+
+// RemoveVote forgets the vote of agentID, if any
+func (c *IncrementalConsensus) RemoveVote(agentID string) {
+  	c.mu.Lock()
+  	defer c.mu.Unlock()
+
+  	if old, ok := c.votes[agentID]; ok {
+      		c.counts[old]--
+      		delete(c.votes, agentID)
+      	}
+  }
+
+// Current returns the consensus of the current votes: TRUE or FALSE with
+// more than half of all votes, otherwise UNKNOWN, exactly as CONSENSUS
+func (c *IncrementalConsensus) Current() TernaryResult {
+  	c.mu.Lock()
+  	defer c.mu.Unlock()
+
+  	total := len(c.votes)
+  	value := UNKNOWN
+  	switch {
+      	case total == 0:
+      	case c.counts[TRUE] > total/2:
+      		value = TRUE
+      	case c.counts[FALSE] > total/2:
+      		value = FALSE
+      	}
+  	return TernaryResult{
+      		ID:         uuid.New().String(),
+      		Value:      value,
+      		Confidence: value.Confidence(),
+      		Reason: fmt.Sprintf("Incremental consensus of %d votes (%d true, %d false)",
+            			total, c.counts[TRUE], c.counts[FALSE]),
+      		Timestamp:  time.Now(),
+      		InputCount: total,
+      	}
+  }
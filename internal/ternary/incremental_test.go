@@ -0,0 +1,52 @@
+package ternary
+
+import "testing"
+
+func TestIncrementalConsensus(t *testing.T) {
+  	type op struct {
+      		agent  string
+      		vote   Trit
+      		remove bool
+      	}
+  	tests := []struct {
+      		name      string
+      		ops       []op
+      		want      Trit
+      		wantCount int
+      	}{
+      		{name: "no votes", want: UNKNOWN},
+      		{name: "majority", ops: []op{{"a", TRUE, false}, {"b", TRUE, false}, {"c", FALSE, false}}, want: TRUE, wantCount: 3},
+      		{name: "changed vote", ops: []op{{"a", TRUE, false}, {"b", TRUE, false}, {"c", FALSE, false}, {"b", FALSE, false}}, want: FALSE, wantCount: 3},
+      		{name: "repeated vote", ops: []op{{"a", TRUE, false}, {"a", TRUE, false}, {"b", FALSE, false}}, want: UNKNOWN, wantCount: 2},
+      		{name: "removed vote", ops: []op{{"a", TRUE, false}, {"b", FALSE, false}, {"c", FALSE, false}, {"c", 0, true}}, want: UNKNOWN, wantCount: 2},
+      		{name: "remove unknown agent", ops: []op{{"a", TRUE, false}, {"z", 0, true}}, want: TRUE, wantCount: 1},
+      		{name: "UNKNOWN votes count", ops: []op{{"a", TRUE, false}, {"b", UNKNOWN, false}}, want: UNKNOWN, wantCount: 2},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			c := NewIncrementalConsensus()
+                    			votes := make(map[string]Trit)
+                    			for _, o := range tt.ops {
+                              				if o.remove {
+                                          					c.RemoveVote(o.agent)
+                                          					delete(votes, o.agent)
+                                          				} else {
+                                          					c.SetVote(o.agent, o.vote)
+                                          					votes[o.agent] = o.vote
+                                          				}
+                              			}
+                    			r := c.Current()
+                    			if r.Value != tt.want || r.InputCount != tt.wantCount {
+                              				t.Errorf("Current = %v over %d votes, want %v over %d", r.Value, r.InputCount, tt.want, tt.wantCount)
+                              			}
+
+                    			inputs := make([]Trit, 0, len(votes))
+                    			for _, v := range votes {
+                              				inputs = append(inputs, v)
+                              			}
+                    			if want := NewEngine().Evaluate("CONSENSUS", inputs...).Value; r.Value != want {
+                              				t.Errorf("Current = %v, CONSENSUS%v = %v", r.Value, inputs, want)
+                              			}
+                    		})
+      	}
+  }
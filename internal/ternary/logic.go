@@ -0,0 +1,140 @@
+package ternary
+
+import "fmt"
+
+// LogicSystem selects the truth tables of the AND, OR, NOT and IMPLIES
+// rules. NOT is negation in every system; the systems differ as follows:
+//
+//	            AND                OR                 IMPLIES
+//	Default     min (Kleene)       max (Kleene)       min(TRUE, 1-a+b)
+//	Kleene      min                max                max(NOT a, b)
+//	Lukasiewicz max(FALSE, a+b-1)  min(TRUE, a+b+1)   min(TRUE, 1-a+b)
+//
+// So Kleene departs from the default only in IMPLIES, where UNKNOWN →
+// UNKNOWN is UNKNOWN rather than TRUE, and Lukasiewicz only in AND and OR,
+// which become the strong connectives: UNKNOWN AND UNKNOWN is FALSE and
+// UNKNOWN OR UNKNOWN is TRUE. Min and Max stay available as functions.
+type LogicSystem int
+
+const (
+  	// LogicDefault is the mix the engine has always shipped with
+  	LogicDefault LogicSystem = iota
+  	// LogicKleene is Kleene's strong three-valued logic K3
+  	LogicKleene
+  	// LogicLukasiewicz is Lukasiewicz's three-valued logic Ł3 with strong
+  	// conjunction and disjunction
+  	LogicLukasiewicz
+  )
+
+func (ls LogicSystem) String() string {
+  	switch ls {
+      	case LogicDefault:
+      		return "default"
+      	case LogicKleene:
+      		return "kleene"
+      	case LogicLukasiewicz:
+      		return "lukasiewicz"
+      	default:
+      		return fmt.Sprintf("LogicSystem(%d)", int(ls))
+      	}
+  }
+
+// WithLogicSystem registers AND, OR, NOT and IMPLIES with the truth tables
+// of ls. An unknown system makes NewEngine panic and NewEngineWithCapacity
+// return an error.
+func WithLogicSystem(ls LogicSystem) Option {
+  	return func(e *Engine) {
+      		if err := e.SetLogicSystem(ls); err != nil && e.optErr == nil {
+            			e.optErr = err
+            		}
+      	}
+  }
+
+// SetLogicSystem re-registers AND, OR, NOT and IMPLIES with the truth
+// tables of ls, replacing any rules added under those names
+func (e *Engine) SetLogicSystem(ls LogicSystem) error {
+  	if ls < LogicDefault || ls > LogicLukasiewicz {
+      		return fmt.Errorf("ternary: unknown logic system %d", int(ls))
+      	}
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.registerLogicRules(ls)
+  	e.ruleIndex = nil
+  	return nil
+  }
+
+// registerLogicRules sets up AND, OR, NOT and IMPLIES for ls.
+// The caller must hold e.mu.
+func (e *Engine) registerLogicRules(ls LogicSystem) {
+  	and, or, implies := tritMin, tritMax, lukasiewiczImplies
+  	switch ls {
+      	case LogicKleene:
+      		implies = kleeneImplies
+      	case LogicLukasiewicz:
+      		and, or = lukasiewiczAnd, lukasiewiczOr
+      	}
+
+  	// Ternary AND, folded from TRUE
+  	e.rules["AND"] = TernaryRule{
+      		Name: "AND",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			result := TRUE
+            			for _, inp := range inputs {
+                    				result = and(result, inp)
+                    			}
+            			return result
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(TRUE, and),
+      	}
+
+  	// Ternary OR, folded from FALSE
+  	e.rules["OR"] = TernaryRule{
+      		Name: "OR",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			result := FALSE
+            			for _, inp := range inputs {
+                    				result = or(result, inp)
+                    			}
+            			return result
+            		},
+      		Weight:      1.0,
+      		incremental: foldStream(FALSE, or),
+      	}
+
+  	// Ternary NOT, the same in every system
+  	e.rules["NOT"] = TernaryRule{
+      		Name: "NOT",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			if len(inputs) == 0 {
+                    				return UNKNOWN
+                    			}
+            			return tritNeg(inputs[0])
+            		},
+      		Weight: 1.0,
+      	}
+
+  	e.rules["IMPLIES"] = TernaryRule{
+      		Name: "IMPLIES",
+      		Evaluate: func(inputs ...Trit) Trit {
+            			if len(inputs) != 2 {
+                    				return UNKNOWN
+                    			}
+            			return implies(inputs[0], inputs[1])
+            		},
+      		Weight: 1.0,
+      		Arity:  2,
+      	}
+  }
+
+// lukasiewiczImplies is a → b = min(TRUE, 1 - a + b)
+func lukasiewiczImplies(a, b Trit) Trit { return tritMin(TRUE, 1-a+b) }
+
+// kleeneImplies is a → b = max(NOT a, b)
+func kleeneImplies(a, b Trit) Trit { return tritMax(tritNeg(a), b) }
+
+// lukasiewiczAnd is strong conjunction max(FALSE, a + b - 1)
+func lukasiewiczAnd(a, b Trit) Trit { return tritMax(FALSE, a+b-1) }
+
+// lukasiewiczOr is strong disjunction min(TRUE, a + b + 1)
+func lukasiewiczOr(a, b Trit) Trit { return tritMin(TRUE, a+b+1) }
@@ -0,0 +1,97 @@
+package ternary
+
+import "testing"
+
+// logicOrder lists the trits in the row and column order of the tables
+// below
+var logicOrder = [3]Trit{FALSE, UNKNOWN, TRUE}
+
+func TestLogicSystems(t *testing.T) {
+  	const F, U, T = FALSE, UNKNOWN, TRUE
+  	tests := []struct {
+      		system LogicSystem
+      		rule   string
+      		table  [3][3]Trit // [a][b] in FALSE, UNKNOWN, TRUE order
+      	}{
+      		{LogicDefault, "AND", [3][3]Trit{{F, F, F}, {F, U, U}, {F, U, T}}},
+      		{LogicDefault, "OR", [3][3]Trit{{F, U, T}, {U, U, T}, {T, T, T}}},
+      		{LogicDefault, "IMPLIES", [3][3]Trit{{T, T, T}, {U, T, T}, {F, U, T}}},
+      		{LogicKleene, "AND", [3][3]Trit{{F, F, F}, {F, U, U}, {F, U, T}}},
+      		{LogicKleene, "OR", [3][3]Trit{{F, U, T}, {U, U, T}, {T, T, T}}},
+      		{LogicKleene, "IMPLIES", [3][3]Trit{{T, T, T}, {U, U, T}, {F, U, T}}},
+      		{LogicLukasiewicz, "AND", [3][3]Trit{{F, F, F}, {F, F, U}, {F, U, T}}},
+      		{LogicLukasiewicz, "OR", [3][3]Trit{{F, U, T}, {U, T, T}, {T, T, T}}},
+      		{LogicLukasiewicz, "IMPLIES", [3][3]Trit{{T, T, T}, {U, T, T}, {F, U, T}}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.system.String()+"/"+tt.rule, func(t *testing.T) {
+                    			e := NewEngine(WithLogicSystem(tt.system))
+                    			for i, a := range logicOrder {
+                              				for j, b := range logicOrder {
+                                          					if got := e.Evaluate(tt.rule, a, b).Value; got != tt.table[i][j] {
+                                                        						t.Errorf("%s(%v, %v) = %v, want %v", tt.rule, a, b, got, tt.table[i][j])
+                                                        					}
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestLogicSystemNot(t *testing.T) {
+  	for _, ls := range []LogicSystem{LogicDefault, LogicKleene, LogicLukasiewicz} {
+      		e := NewEngine(WithLogicSystem(ls))
+      		for i, a := range logicOrder {
+            			if got, want := e.Evaluate("NOT", a).Value, logicOrder[2-i]; got != want {
+                    				t.Errorf("%v NOT(%v) = %v, want %v", ls, a, got, want)
+                    			}
+            		}
+      	}
+  }
+
+func TestSetLogicSystem(t *testing.T) {
+  	tests := []struct {
+      		system  LogicSystem
+      		want    Trit // IMPLIES(UNKNOWN, UNKNOWN) afterwards
+      		wantErr bool
+      	}{
+      		{system: LogicKleene, want: UNKNOWN},
+      		{system: LogicLukasiewicz, want: TRUE},
+      		{system: LogicDefault, want: TRUE},
+      		{system: LogicSystem(9), want: TRUE, wantErr: true},
+      		{system: LogicSystem(-1), want: TRUE, wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		e := NewEngine()
+      		if err := e.SetLogicSystem(tt.system); (err != nil) != tt.wantErr {
+            			t.Errorf("SetLogicSystem(%v) error = %v, want error %v", tt.system, err, tt.wantErr)
+            		}
+      		if got := e.Evaluate("IMPLIES", UNKNOWN, UNKNOWN).Value; got != tt.want {
+            			t.Errorf("after SetLogicSystem(%v): IMPLIES(UNKNOWN, UNKNOWN) = %v, want %v", tt.system, got, tt.want)
+            		}
+      	}
+  }
+
+func TestSetLogicSystemReplacesRules(t *testing.T) {
+  	e := NewEngine()
+  	e.AddRule("AND", TernaryRule{Name: "AND", Weight: 1, Evaluate: func(...Trit) Trit { return FALSE }})
+  	if err := e.SetLogicSystem(LogicKleene); err != nil {
+      		t.Fatal(err)
+      	}
+  	if got := e.Evaluate("AND", TRUE, TRUE).Value; got != TRUE {
+      		t.Errorf("custom AND survived SetLogicSystem: AND(TRUE, TRUE) = %v", got)
+      	}
+  }
+
+func TestLogicSystemString(t *testing.T) {
+  	tests := map[LogicSystem]string{
+      		LogicDefault:     "default",
+      		LogicKleene:      "kleene",
+      		LogicLukasiewicz: "lukasiewicz",
+      		LogicSystem(7):   "LogicSystem(7)",
+      	}
+  	for ls, want := range tests {
+      		if got := ls.String(); got != want {
+            			t.Errorf("LogicSystem(%d).String() = %q, want %q", int(ls), got, want)
+            		}
+      	}
+  }
@@ -0,0 +1,66 @@
+package ternary
+
+// confidenceBounds are the upper bounds of the confidence histogram
+var confidenceBounds = [...]float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0}
+
+// ConfidenceBuckets returns the upper bounds of the confidence histogram
+// reported by Metrics
+func ConfidenceBuckets() []float64 {
+  	return append([]float64(nil), confidenceBounds[:]...)
+  }
+
+// confidenceHistogram counts decisions per confidence bucket
+type confidenceHistogram struct {
+  	counts [len(confidenceBounds) + 1]uint64 // per bound, then overflow
+  	sum    float64
+  	count  uint64
+  }
+
+// observe adds one confidence to the histogram
+func (h *confidenceHistogram) observe(c float64) {
+  	i := 0
+  	for i < len(confidenceBounds) && c > confidenceBounds[i] {
+      		i++
+      	}
+  	h.counts[i]++
+  	h.sum += c
+  	h.count++
+  }
+
+// EngineMetrics is a point-in-time view of the engine's counters, shaped
+// for export to a monitoring system
+type EngineMetrics struct {
+  	Evaluations     uint64
+  	RuleInvocations map[string]uint64
+  	// ConfidenceCounts[i] is the number of decisions with confidence at
+  	// most ConfidenceBuckets()[i], cumulative as in a Prometheus histogram
+  	ConfidenceCounts []uint64
+  	ConfidenceSum    float64
+  	ConfidenceCount  uint64
+  }
+
+// Metrics returns the engine's counters. Every counter covers the whole
+// life of the engine and never decreases, so it can be exported as a
+// Prometheus counter: unlike RuleStats, rule invocations survive
+// ResetStats, and evaluations survive a Restore to an earlier count.
+func (e *Engine) Metrics() EngineMetrics {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	m := EngineMetrics{
+      		Evaluations:      e.evalCount + e.evalDropped,
+      		RuleInvocations:  make(map[string]uint64, len(e.ruleTotals)),
+      		ConfidenceCounts: make([]uint64, len(confidenceBounds)),
+      		ConfidenceSum:    e.confHist.sum,
+      		ConfidenceCount:  e.confHist.count,
+      	}
+  	for name, n := range e.ruleTotals {
+      		m.RuleInvocations[name] = n
+      	}
+  	var cum uint64
+  	for i := range m.ConfidenceCounts {
+      		cum += e.confHist.counts[i]
+      		m.ConfidenceCounts[i] = cum
+      	}
+  	return m
+  }
@@ -0,0 +1,55 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sort"
+  	"strings"
+  )
+
+// EvaluateNamed evaluates a rule over labelled inputs, e.g. one trit per
+// agent. The rule sees the values in label order. The result's Reason
+// lists every label=value pair sorted by label, and its Labels map each
+// label to its value, so a dissenting input can be picked out.
+func (e *Engine) EvaluateNamed(ruleName string, inputs map[string]Trit) TernaryResult {
+  	labels := make([]string, 0, len(inputs))
+  	for label := range inputs {
+      		labels = append(labels, label)
+      	}
+  	sort.Strings(labels)
+
+  	values := make([]Trit, len(labels))
+  	pairs := make([]string, len(labels))
+  	named := make(map[string]Trit, len(labels))
+  	for i, label := range labels {
+      		values[i] = inputs[label]
+      		pairs[i] = label + "=" + tritName(values[i])
+      		named[label] = values[i]
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+  	values, value, note, ok := e.invokeLocked(ruleName, rule, values)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+  	reason := fmt.Sprintf("%s: %s", ruleReason(ruleName, values, note), strings.Join(pairs, " "))
+  	result := e.resultLocked(ruleName, rule.Weight, value, values, reason)
+  	result.Labels = named
+  	return e.recordLocked(result)
+  }
+
+// median3 returns the middle of three trits, which for valid trits is
+// their CONSENSUS. It reports false if any input is not a valid trit.
+func median3(a, b, c Trit) (Trit, bool) {
+  	if !IsValid(a) || !IsValid(b) || !IsValid(c) {
+      		return UNKNOWN, false
+      	}
+  	return tritMax(tritMin(a, b), tritMin(tritMax(a, b), c)), true
+  }
@@ -0,0 +1,103 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateNamed(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		rule       string
+      		inputs     map[string]Trit
+      		want       Trit
+      		wantSuffix string
+      	}{
+      		{
+            			name:       "consensus",
+            			rule:       "CONSENSUS",
+            			inputs:     map[string]Trit{"agent-7": FALSE, "agent-1": TRUE, "agent-3": TRUE},
+            			want:       TRUE,
+            			wantSuffix: ": agent-1=TRUE agent-3=TRUE agent-7=FALSE",
+            		},
+      		{
+            			name:       "label order",
+            			rule:       "IMPLIES",
+            			inputs:     map[string]Trit{"b": FALSE, "a": TRUE},
+            			want:       FALSE,
+            			wantSuffix: ": a=TRUE b=FALSE",
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r := e.EvaluateNamed(tt.rule, tt.inputs)
+                    			if r.Value != tt.want || !strings.HasSuffix(r.Reason, tt.wantSuffix) {
+                              				t.Errorf("EvaluateNamed = %v %q, want %v with suffix %q", r.Value, r.Reason, tt.want, tt.wantSuffix)
+                              			}
+                    			if !reflect.DeepEqual(r.Labels, tt.inputs) {
+                              				t.Errorf("Labels = %v, want %v", r.Labels, tt.inputs)
+                              			}
+                    			if r.Meta != nil {
+                              				t.Errorf("Meta = %v, want nil", r.Meta)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateNamedUnknownRule(t *testing.T) {
+  	e := NewEngine()
+  	if r := e.EvaluateNamed("nope", nil); r.Value != UNKNOWN || r.Labels != nil {
+      		t.Errorf("EvaluateNamed(nope) = %v labels %v, want UNKNOWN without labels", r.Value, r.Labels)
+      	}
+  }
+
+func TestEvaluateNamedLabelsCopied(t *testing.T) {
+  	e := NewEngine()
+  	e.EvaluateNamed("CONSENSUS", map[string]Trit{"a": TRUE, "b": TRUE, "c": FALSE})
+  	d := e.GetDecisions(DecisionFilter{})
+  	d[0].Labels["a"] = FALSE
+  	if got := e.GetDecisions(DecisionFilter{})[0].Labels["a"]; got != TRUE {
+      		t.Errorf("history label changed through GetDecisions copy: %v", got)
+      	}
+
+  	data, err := json.Marshal(d[0])
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	if !strings.Contains(string(data), `"labels":{"a":"FALSE","b":"TRUE","c":"FALSE"}`) {
+      		t.Errorf("JSON = %s, want labels by name", data)
+      	}
+  }
+
+func TestConsensusTruthTable(t *testing.T) {
+  	all := []Trit{FALSE, UNKNOWN, TRUE}
+  	e := NewEngine()
+  	for _, a := range all {
+      		for _, b := range all {
+            			for _, c := range all {
+                    				in := []Trit{a, b, c}
+                    				trues, falses := 0, 0
+                    				for _, x := range in {
+                              					switch x {
+                                          					case TRUE:
+                                          						trues++
+                                          					case FALSE:
+                                          						falses++
+                                          					}
+                              				}
+                    				want := UNKNOWN
+                    				if trues > 1 {
+                              					want = TRUE
+                              				} else if falses > 1 {
+                              					want = FALSE
+                              				}
+                    				if got := e.Evaluate("CONSENSUS", in...).Value; got != want {
+                              					t.Errorf("CONSENSUS%v = %v, want %v", in, got, want)
+                              				}
+                    			}
+            		}
+      	}
+  }
@@ -0,0 +1,114 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sort"
+  	"strings"
+  )
+
+// gate drives wire output with rule applied to the input wires
+type gate struct {
+  	output string
+  	rule   string
+  	inputs []string
+  }
+
+// Netlist is a small combinational logic network: named wires driven either
+// by primary inputs or by gates that apply an engine rule to other wires
+type Netlist struct {
+  	engine *Engine
+  	gates  []gate
+  }
+
+// NewNetlist creates an empty network whose gates evaluate rules of e
+func NewNetlist(e *Engine) *Netlist {
+  	return &Netlist{engine: e}
+  }
+
+// AddGate adds a gate driving output with rule over the given input wires
+func (n *Netlist) AddGate(output string, rule string, inputs ...string) {
+  	n.gates = append(n.gates, gate{
+            		output: output,
+            		rule:   rule,
+            		inputs: append([]string(nil), inputs...),
+            	})
+  }
+
+// Evaluate propagates primaryInputs through the gates in topological order
+// and returns the value of every wire. Gates are evaluated without being
+// recorded in the engine's history. It fails on wires with several drivers,
+// wires nobody drives, unknown or disabled rules, and combinational cycles.
+func (n *Netlist) Evaluate(primaryInputs map[string]Trit) (map[string]Trit, error) {
+  	driver := make(map[string]int, len(n.gates))
+  	for i, g := range n.gates {
+      		if _, ok := primaryInputs[g.output]; ok {
+            			return nil, fmt.Errorf("ternary: wire %q is both a primary input and a gate output", g.output)
+            		}
+      		if _, ok := driver[g.output]; ok {
+            			return nil, fmt.Errorf("ternary: wire %q has several drivers", g.output)
+            		}
+      		driver[g.output] = i
+      	}
+
+  	// Kahn's algorithm over gate dependencies
+  	pending := make([]int, len(n.gates))
+  	dependents := make(map[int][]int)
+  	for i, g := range n.gates {
+      		for _, in := range g.inputs {
+            			if d, ok := driver[in]; ok {
+                    				pending[i]++
+                    				dependents[d] = append(dependents[d], i)
+                    			} else if _, ok := primaryInputs[in]; !ok {
+                    				return nil, fmt.Errorf("ternary: wire %q is not driven", in)
+                    			}
+            		}
+      	}
+  	var ready, order []int
+  	for i, p := range pending {
+      		if p == 0 {
+            			ready = append(ready, i)
+            		}
+      	}
+  	for len(ready) > 0 {
+      		i := ready[0]
+      		ready = ready[1:]
+      		order = append(order, i)
+      		for _, d := range dependents[i] {
+            			if pending[d]--; pending[d] == 0 {
+                    				ready = append(ready, d)
+                    			}
+            		}
+      	}
+  	if len(order) < len(n.gates) {
+      		var cyclic []string
+      		for i, p := range pending {
+            			if p > 0 {
+                    				cyclic = append(cyclic, n.gates[i].output)
+                    			}
+            		}
+      		sort.Strings(cyclic)
+      		return nil, fmt.Errorf("ternary: cycle through wires %s", strings.Join(cyclic, ", "))
+      	}
+
+  	wires := make(map[string]Trit, len(primaryInputs)+len(n.gates))
+  	for w, v := range primaryInputs {
+      		wires[w] = v
+      	}
+
+  	e := n.engine
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+  	for _, i := range order {
+      		g := n.gates[i]
+      		args := make([]Trit, len(g.inputs))
+      		for j, in := range g.inputs {
+            			args[j] = wires[in]
+            		}
+      		result, ok := e.previewLocked(g.rule, args)
+      		if !ok {
+            			return nil, fmt.Errorf("ternary: gate %q: %s", g.output, result.Reason)
+            		}
+      		wires[g.output] = result.Value
+      	}
+  	return wires, nil
+  }
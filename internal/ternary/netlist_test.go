@@ -0,0 +1,77 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestNetlistHalfAdder(t *testing.T) {
+  	n := NewNetlist(NewEngine())
+  	// declared out of order: carry_n depends on carry
+  	n.AddGate("carry_n", "NOT", "carry")
+  	n.AddGate("sum", "XOR", "a", "b")
+  	n.AddGate("carry", "AND", "a", "b")
+
+  	tests := []struct {
+      		a, b                Trit
+      		sum, carry, carry_n Trit
+      	}{
+      		{FALSE, FALSE, FALSE, FALSE, TRUE},
+      		{FALSE, TRUE, TRUE, FALSE, TRUE},
+      		{TRUE, FALSE, TRUE, FALSE, TRUE},
+      		{TRUE, TRUE, FALSE, TRUE, FALSE},
+      		{UNKNOWN, TRUE, UNKNOWN, UNKNOWN, UNKNOWN},
+      		{UNKNOWN, FALSE, UNKNOWN, FALSE, TRUE},
+      	}
+  	for _, tt := range tests {
+      		w, err := n.Evaluate(map[string]Trit{"a": tt.a, "b": tt.b})
+      		if err != nil {
+            			t.Fatal(err)
+            		}
+      		if w["sum"] != tt.sum || w["carry"] != tt.carry || w["carry_n"] != tt.carry_n || w["a"] != tt.a {
+            			t.Errorf("a=%v b=%v: wires %v, want sum %v carry %v carry_n %v", tt.a, tt.b, w, tt.sum, tt.carry, tt.carry_n)
+            		}
+      	}
+  }
+
+func TestNetlistErrors(t *testing.T) {
+  	type g struct {
+      		out, rule string
+      		in        []string
+      	}
+  	tests := []struct {
+      		name    string
+      		gates   []g
+      		wantErr string
+      	}{
+      		{name: "cycle", gates: []g{{"x", "AND", []string{"y"}}, {"y", "AND", []string{"x"}}}, wantErr: "cycle through wires x, y"},
+      		{name: "several drivers", gates: []g{{"x", "AND", []string{"a"}}, {"x", "OR", []string{"a"}}}, wantErr: `wire "x" has several drivers`},
+      		{name: "driven primary input", gates: []g{{"a", "AND", []string{"a"}}}, wantErr: `wire "a" is both a primary input and a gate output`},
+      		{name: "undriven wire", gates: []g{{"x", "AND", []string{"z"}}}, wantErr: `wire "z" is not driven`},
+      		{name: "unknown rule", gates: []g{{"x", "nope", []string{"a"}}}, wantErr: `gate "x": Rule 'nope' not found`},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			n := NewNetlist(NewEngine())
+                    			for _, gate := range tt.gates {
+                              				n.AddGate(gate.out, gate.rule, gate.in...)
+                              			}
+                    			w, err := n.Evaluate(map[string]Trit{"a": TRUE})
+                    			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || w != nil {
+                              				t.Errorf("Evaluate = %v, %v, want error %q", w, err, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestNetlistNotRecorded(t *testing.T) {
+  	e := NewEngine()
+  	n := NewNetlist(e)
+  	n.AddGate("x", "AND", "a")
+  	if _, err := n.Evaluate(map[string]Trit{"a": TRUE}); err != nil {
+      		t.Fatal(err)
+      	}
+  	if d := e.GetDecisions(DecisionFilter{}); len(d) != 0 {
+      		t.Errorf("netlist recorded %v", d)
+      	}
+  }
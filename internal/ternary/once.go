@@ -0,0 +1,34 @@
+package ternary
+
+// EvaluateOnce evaluates a rule the first time key is seen and returns the
+// cached result on later calls with the same key, whatever their inputs.
+// Failed evaluations (unknown or disabled rule) are not cached.
+func (e *Engine) EvaluateOnce(key, ruleName string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if cached, ok := e.once[key]; ok {
+      		return cached
+      	}
+  	result, ok := e.tryEvaluateLocked(ruleName, inputs)
+  	if ok {
+      		e.once[key] = result
+      	}
+  	return result
+  }
+
+// EvaluateOrUpdate re-evaluates a rule for key, replaces the result cached by
+// EvaluateOnce, and reports whether the value changed from the cached one.
+// The first evaluation of a key never counts as a change.
+func (e *Engine) EvaluateOrUpdate(key, ruleName string, inputs ...Trit) (TernaryResult, bool) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	result, ok := e.tryEvaluateLocked(ruleName, inputs)
+  	if !ok {
+      		return result, false
+      	}
+  	prev, seen := e.once[key]
+  	e.once[key] = result
+  	return result, seen && prev.Value != result.Value
+  }
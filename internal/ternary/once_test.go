@@ -0,0 +1,68 @@
+package ternary
+
+import "testing"
+
+func TestEvaluateOnceOrUpdate(t *testing.T) {
+  	type step struct {
+      		update      bool // EvaluateOrUpdate instead of EvaluateOnce
+      		key, rule   string
+      		input       Trit
+      		want        Trit
+      		wantChanged bool
+      	}
+  	tests := []struct {
+      		name  string
+      		steps []step
+      	}{
+      		{
+            			name: "once caches whatever the inputs",
+            			steps: []step{
+                    				{key: "k", rule: "AND", input: TRUE, want: TRUE},
+                    				{key: "k", rule: "AND", input: FALSE, want: TRUE},
+                    				{key: "k", rule: "OR", input: FALSE, want: TRUE},
+                    				{key: "j", rule: "AND", input: FALSE, want: FALSE},
+                    			},
+            		},
+      		{
+            			name: "update detects changes",
+            			steps: []step{
+                    				{key: "k", rule: "AND", input: TRUE, want: TRUE},
+                    				{update: true, key: "k", rule: "AND", input: FALSE, want: FALSE, wantChanged: true},
+                    				{update: true, key: "k", rule: "AND", input: FALSE, want: FALSE},
+                    				{key: "k", rule: "AND", input: TRUE, want: FALSE},
+                    			},
+            		},
+      		{
+            			name: "first update is not a change",
+            			steps: []step{
+                    				{update: true, key: "k", rule: "AND", input: TRUE, want: TRUE},
+                    				{key: "k", rule: "AND", input: FALSE, want: TRUE},
+                    			},
+            		},
+      		{
+            			name: "failures not cached",
+            			steps: []step{
+                    				{key: "k", rule: "nope", input: TRUE, want: UNKNOWN},
+                    				{update: true, key: "k", rule: "nope", input: TRUE, want: UNKNOWN},
+                    				{key: "k", rule: "AND", input: FALSE, want: FALSE},
+                    			},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			for i, s := range tt.steps {
+                              				var r TernaryResult
+                              				changed := false
+                              				if s.update {
+                                          					r, changed = e.EvaluateOrUpdate(s.key, s.rule, s.input)
+                                          				} else {
+                                          					r = e.EvaluateOnce(s.key, s.rule, s.input)
+                                          				}
+                              				if r.Value != s.want || changed != s.wantChanged {
+                                          					t.Errorf("step %d = %v changed %v, want %v changed %v", i, r.Value, changed, s.want, s.wantChanged)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
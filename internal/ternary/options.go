@@ -0,0 +1,162 @@
+package ternary
+
+import (
+  	"fmt"
+  	"log/slog"
+  	"math"
+  	"sync"
+  	"time"
+  )
+
+// Option configures an Engine at construction
+type Option func(*Engine)
+
+// RecordOnlyOnChange makes Evaluate append a decision to the history only
+// when its value differs from the last recorded value of the same rule.
+// Skipped evaluations still return their result and count in Stats.
+func RecordOnlyOnChange() Option {
+  	return func(e *Engine) {
+      		e.onlyOnChange = true
+      	}
+  }
+
+// EmptyInputResult makes every rule evaluated with no inputs return v
+// instead of its own empty-input value (TRUE for AND, FALSE for OR, ...).
+// EmptyInputResult(UNKNOWN) is the conservative choice.
+func EmptyInputResult(v Trit) Option {
+  	return func(e *Engine) {
+      		e.emptyResult = &v
+      	}
+  }
+
+// CaptureInputs makes every result carry a copy of its inputs, as needed by
+// AgreementMatrix and log replay. See also Engine.SetCaptureInputs.
+func CaptureInputs() Option {
+  	return func(e *Engine) {
+      		e.capture = true
+      	}
+  }
+
+// WithClock makes the engine timestamp results with now instead of
+// time.Now, e.g. a fixed clock for golden-file tests. Read-only paths such
+// as Replay and ConcurrentEvaluate stamp results concurrently, so the engine
+// serializes its calls to now; now must not call back into the engine.
+func WithClock(now func() time.Time) Option {
+  	return func(e *Engine) {
+      		if now != nil {
+            			var mu sync.Mutex
+            			e.clock = func() time.Time {
+                    				mu.Lock()
+                    				defer mu.Unlock()
+                    				return now()
+                    			}
+            		}
+      	}
+  }
+
+// WithIDGenerator makes the engine identify results with IDs from next
+// instead of random UUIDs, e.g. a plain counter for golden-file tests. The
+// engine serializes its calls to next, so next needs no locking of its own
+// even when read-only paths run concurrently; it must not call back into
+// the engine.
+func WithIDGenerator(next func() string) Option {
+  	return func(e *Engine) {
+      		if next != nil {
+            			var mu sync.Mutex
+            			e.newID = func() string {
+                    				mu.Lock()
+                    				defer mu.Unlock()
+                    				return next()
+                    			}
+            		}
+      	}
+  }
+
+// WithLogger sets the logger for engine warnings such as deprecated rule
+// use. The default is slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+  	return func(e *Engine) {
+      		e.logger = logger
+      	}
+  }
+
+// WithInvalidInputs sets how evaluations treat invalid input trits; see
+// SetInvalidInputs
+func WithInvalidInputs(p InvalidInputs) Option {
+  	return func(e *Engine) {
+      		e.invalidMode = p
+      	}
+  }
+
+// WithEngineID names the engine. Every result it produces carries id in
+// EngineID, so results gathered from several engines keep their origin.
+func WithEngineID(id string) Option {
+  	return func(e *Engine) {
+      		e.engineID = id
+      	}
+  }
+
+// EngineID returns the ID set with WithEngineID, or ""
+func (e *Engine) EngineID() string {
+  	return e.engineID
+  }
+
+// SetConfidenceBounds clamps the confidence of later results to
+// [min, max]. By default confidence is capped at 1.0 and has no floor, so
+// an invalid trit's -1 shows through; a min of 0 floors it away.
+func (e *Engine) SetConfidenceBounds(min, max float64) error {
+  	if math.IsNaN(min) || math.IsNaN(max) || min > max {
+      		return fmt.Errorf("ternary: invalid confidence bounds [%v, %v]", min, max)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.confMin, e.confMax = min, max
+  	return nil
+  }
+
+// boundConfidence clamps c to the engine's confidence bounds. The caller
+// must hold e.mu.
+func (e *Engine) boundConfidence(c float64) float64 {
+  	if c > e.confMax {
+      		c = e.confMax
+      	}
+  	if c < e.confMin {
+      		c = e.confMin
+      	}
+  	return c
+  }
+
+// setConfidenceLocked sets the confidence of result from its unclamped
+// value raw. The caller must hold e.mu.
+func (e *Engine) setConfidenceLocked(result *TernaryResult, raw float64) {
+  	result.Confidence = e.boundConfidence(raw)
+  	if e.rawConf {
+      		result.RawConfidence = raw
+      	}
+  }
+
+// WithRawConfidence makes every result also carry its confidence before
+// clamping in RawConfidence, so decisions that all hit the 1.0 ceiling can
+// still be ranked by rule weight
+func WithRawConfidence() Option {
+  	return func(e *Engine) {
+      		e.rawConf = true
+      	}
+  }
+
+// SetRawConfidence turns RawConfidence reporting on or off for later
+// evaluations
+func (e *Engine) SetRawConfidence(enabled bool) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.rawConf = enabled
+  }
+
+// SetCaptureInputs turns input capture on or off for later evaluations.
+// Capture costs a copy of the inputs per retained decision.
+func (e *Engine) SetCaptureInputs(enabled bool) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.capture = enabled
+  }
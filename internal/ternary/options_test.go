@@ -0,0 +1,366 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"fmt"
+  	"math"
+  	"reflect"
+  	"strings"
+  	"sync"
+  	"testing"
+  	"time"
+  )
+
+func TestWithIDGeneratorConcurrent(t *testing.T) {
+  	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+  	n := 0
+  	e := NewEngine(WithClock(func() time.Time { return fixed }), WithIDGenerator(func() string {
+                    		n++
+                    		return fmt.Sprintf("id-%d", n)
+                    	}))
+
+  	const goroutines, calls = 8, 50
+  	ids := make(chan string, goroutines*calls)
+  	var wg sync.WaitGroup
+  	for g := 0; g < goroutines; g++ {
+      		wg.Add(1)
+      		go func() {
+            			defer wg.Done()
+            			for i := 0; i < calls; i++ {
+                    				// unknown rules mint failure results under the read lock
+                    				r := e.ConcurrentEvaluate([]EvalRequest{{Rule: "missing"}})[0]
+                    				if !r.Timestamp.Equal(fixed) {
+                              					t.Errorf("timestamp = %v, want %v", r.Timestamp, fixed)
+                              				}
+                    				ids <- r.ID
+                    			}
+            		}()
+      	}
+  	wg.Wait()
+  	close(ids)
+
+  	seen := map[string]bool{}
+  	for id := range ids {
+      		if seen[id] {
+            			t.Fatalf("duplicate ID %q", id)
+            		}
+      		seen[id] = true
+      	}
+  	if len(seen) != goroutines*calls {
+      		t.Fatalf("got %d IDs, want %d", len(seen), goroutines*calls)
+      	}
+  }
+
+func TestDeterministicMode(t *testing.T) {
+  	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+  	newEngine := func() *Engine {
+      		n := 0
+      		return NewEngine(WithClock(func() time.Time { return fixed }), WithIDGenerator(func() string {
+                              			n++
+                              			return fmt.Sprintf("id-%d", n)
+                              		}))
+      	}
+  	tests := []struct {
+      		name string
+      		eval func(e *Engine) TernaryResult
+      		want TernaryResult
+      	}{
+      		{
+            			name: "recorded",
+            			eval: func(e *Engine) TernaryResult { return e.Evaluate("OR", TRUE) },
+            			want: TernaryResult{ID: "id-1", Rule: "OR", Value: TRUE, Confidence: 1, Reason: "Rule[OR] evaluated 1 inputs", Timestamp: fixed, InputCount: 1},
+            		},
+      		{
+            			name: "failed",
+            			eval: func(e *Engine) TernaryResult { return e.Evaluate("missing") },
+            			want: TernaryResult{ID: "id-1", Value: UNKNOWN, Reason: "Rule 'missing' not found", Timestamp: fixed},
+            		},
+      		{
+            			name: "IDs in evaluation order",
+            			eval: func(e *Engine) TernaryResult {
+                    				e.Evaluate("AND", TRUE, FALSE)
+                    				e.Evaluate("missing")
+                    				return e.Evaluate("NOT", TRUE)
+                    			},
+            			want: TernaryResult{ID: "id-3", Rule: "NOT", Value: FALSE, Reason: "Rule[NOT] evaluated 1 inputs", Timestamp: fixed, InputCount: 1},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			a, b := tt.eval(newEngine()), tt.eval(newEngine())
+                    			if !reflect.DeepEqual(a, tt.want) || !reflect.DeepEqual(b, tt.want) {
+                              				t.Errorf("results = %+v and %+v, want %+v", a, b, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestRecordOnlyOnChange(t *testing.T) {
+  	type eval struct {
+      		rule  string
+      		input Trit
+      	}
+  	tests := []struct {
+      		name     string
+      		opts     []Option
+      		evals    []eval
+      		recorded []Trit
+      	}{
+      		{
+            			name:     "repeats skipped",
+            			opts:     []Option{RecordOnlyOnChange()},
+            			evals:    []eval{{"AND", TRUE}, {"AND", TRUE}, {"AND", FALSE}, {"AND", FALSE}, {"AND", TRUE}},
+            			recorded: []Trit{TRUE, FALSE, TRUE},
+            		},
+      		{
+            			name:     "tracked per rule",
+            			opts:     []Option{RecordOnlyOnChange()},
+            			evals:    []eval{{"AND", TRUE}, {"OR", TRUE}, {"AND", TRUE}, {"OR", FALSE}},
+            			recorded: []Trit{TRUE, TRUE, FALSE},
+            		},
+      		{
+            			name:     "off by default",
+            			evals:    []eval{{"AND", TRUE}, {"AND", TRUE}},
+            			recorded: []Trit{TRUE, TRUE},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(tt.opts...)
+                    			for _, ev := range tt.evals {
+                              				if r := e.Evaluate(ev.rule, ev.input); r.Value != ev.input {
+                                          					t.Fatalf("skipped evaluation returned %v, want %v", r.Value, ev.input)
+                                          				}
+                              			}
+                    			var got []Trit
+                    			for _, d := range e.GetDecisions(DecisionFilter{}) {
+                              				got = append(got, d.Value)
+                              			}
+                    			if !reflect.DeepEqual(got, tt.recorded) {
+                              				t.Errorf("recorded %v, want %v", got, tt.recorded)
+                              			}
+                    			if n := e.Stats()["total_evaluations"]; n != uint64(len(tt.evals)) {
+                              				t.Errorf("total_evaluations = %v, want %d", n, len(tt.evals))
+                              			}
+                    		})
+      	}
+  }
+
+func TestEmptyInputResult(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		opts   []Option
+      		rule   string
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{name: "AND default", rule: "AND", want: TRUE},
+      		{name: "OR default", rule: "OR", want: FALSE},
+      		{name: "AND overridden", opts: []Option{EmptyInputResult(UNKNOWN)}, rule: "AND", want: UNKNOWN},
+      		{name: "OR overridden", opts: []Option{EmptyInputResult(TRUE)}, rule: "OR", want: TRUE},
+      		{name: "non-empty untouched", opts: []Option{EmptyInputResult(UNKNOWN)}, rule: "AND", inputs: []Trit{TRUE}, want: TRUE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := NewEngine(tt.opts...).Evaluate(tt.rule, tt.inputs...).Value; got != tt.want {
+                              				t.Errorf("%s%v = %v, want %v", tt.rule, tt.inputs, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestSetConfidenceBounds(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		min, max float64
+      		rule     string
+      		want     float64
+      		wantErr  bool
+      	}{
+      		{name: "default ceiling", min: 0, max: 1, rule: "HEAVY", want: 1},
+      		{name: "lowered ceiling", min: 0, max: 0.9, rule: "HEAVY", want: 0.9},
+      		{name: "inside bounds", min: 0.2, max: 0.8, rule: "HALF", want: 0.5},
+      		{name: "raised floor", min: 0.6, max: 1, rule: "HALF", want: 0.6},
+      		{name: "floor hides invalid trit", min: 0, max: 1, rule: "BAD", want: 0},
+      		{name: "no floor shows invalid trit", min: math.Inf(-1), max: 1, rule: "BAD", want: -1},
+      		{name: "point bounds", min: 0.3, max: 0.3, rule: "HEAVY", want: 0.3},
+      		{name: "inverted", min: 0.5, max: 0.2, wantErr: true},
+      		{name: "NaN min", min: math.NaN(), max: 1, wantErr: true},
+      		{name: "NaN max", min: 0, max: math.NaN(), wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("HEAVY", TernaryRule{Name: "HEAVY", Weight: 2, Evaluate: func(...Trit) Trit { return TRUE }})
+                    			e.AddRule("HALF", TernaryRule{Name: "HALF", Weight: 1, Evaluate: func(...Trit) Trit { return UNKNOWN }})
+                    			e.AddRule("BAD", TernaryRule{Name: "BAD", Weight: 1, Evaluate: func(...Trit) Trit { return 5 }})
+                    			err := e.SetConfidenceBounds(tt.min, tt.max)
+                    			if (err != nil) != tt.wantErr {
+                              				t.Fatalf("SetConfidenceBounds(%v, %v) error = %v, want error %v", tt.min, tt.max, err, tt.wantErr)
+                              			}
+                    			if tt.wantErr {
+                              				return
+                              			}
+                    			if r := e.Evaluate(tt.rule); r.Confidence != tt.want {
+                              				t.Errorf("%s confidence = %v, want %v", tt.rule, r.Confidence, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestDefaultConfidenceBounds(t *testing.T) {
+  	e := NewEngine()
+  	e.AddRule("BAD", TernaryRule{Name: "BAD", Weight: 1, Evaluate: func(...Trit) Trit { return 5 }})
+  	if r := e.Evaluate("BAD"); r.Confidence != -1 {
+      		t.Errorf("invalid trit confidence = %v, want -1 with no floor", r.Confidence)
+      	}
+  }
+
+func TestWithEngineID(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		opts []Option
+      		eval func(e *Engine) TernaryResult
+      		want string
+      	}{
+      		{name: "recorded", opts: []Option{WithEngineID("alpha")}, eval: func(e *Engine) TernaryResult { return e.Evaluate("AND", TRUE) }, want: "alpha"},
+      		{name: "failed", opts: []Option{WithEngineID("beta")}, eval: func(e *Engine) TernaryResult { return e.Evaluate("missing") }, want: "beta"},
+      		{name: "weighted", opts: []Option{WithEngineID("gamma")}, eval: func(e *Engine) TernaryResult {
+                    			return e.EvaluateWeighted("WEIGHTED_CONSENSUS", []Trit{TRUE}, []float64{1})
+                    		}, want: "gamma"},
+      		{name: "unset", eval: func(e *Engine) TernaryResult { return e.Evaluate("AND") }, want: ""},
+      		{name: "last option wins", opts: []Option{WithEngineID("a"), WithEngineID("b")}, eval: func(e *Engine) TernaryResult { return e.Evaluate("OR") }, want: "b"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(tt.opts...)
+                    			if got := e.EngineID(); got != tt.want {
+                              				t.Errorf("EngineID() = %q, want %q", got, tt.want)
+                              			}
+                    			if r := tt.eval(e); r.EngineID != tt.want {
+                              				t.Errorf("result EngineID = %q, want %q", r.EngineID, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestRawConfidence(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		raw      bool
+      		eval     func(e *Engine) TernaryResult
+      		wantConf float64
+      		wantRaw  float64
+      	}{
+      		{name: "off", eval: func(e *Engine) TernaryResult { return e.Evaluate("EVOLVE", TRUE, TRUE) }, wantConf: 1},
+      		{name: "evolve", raw: true, eval: func(e *Engine) TernaryResult { return e.Evaluate("EVOLVE", TRUE, TRUE) }, wantConf: 1, wantRaw: 2},
+      		{name: "consensus", raw: true, eval: func(e *Engine) TernaryResult { return e.Evaluate("CONSENSUS", TRUE, TRUE) }, wantConf: 1, wantRaw: 1.5},
+      		{
+            			name:     "confidence rule",
+            			raw:      true,
+            			eval:     func(e *Engine) TernaryResult { return e.EvaluateConfidence("CONSENSUS", TRUE, FALSE) },
+            			wantConf: 0.75,
+            			wantRaw:  0.75,
+            		},
+      		{
+            			name:     "weighted not",
+            			raw:      true,
+            			eval:     func(e *Engine) TernaryResult { return e.EvaluateNot(WeightedTrit{Value: FALSE, Weight: 1}) },
+            			wantConf: 1,
+            			wantRaw:  1,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			var opts []Option
+                    			if tt.raw {
+                              				opts = append(opts, WithRawConfidence())
+                              			}
+                    			r := tt.eval(NewEngine(opts...))
+                    			if r.Confidence != tt.wantConf || r.RawConfidence != tt.wantRaw {
+                              				t.Errorf("confidence = %v raw %v, want %v raw %v", r.Confidence, r.RawConfidence, tt.wantConf, tt.wantRaw)
+                              			}
+                    		})
+      	}
+  }
+
+func TestSetRawConfidence(t *testing.T) {
+  	e := NewEngine()
+  	tests := []struct {
+      		enabled bool
+      		want    float64
+      	}{
+      		{enabled: true, want: 2},
+      		{enabled: false, want: 0},
+      	}
+  	for _, tt := range tests {
+      		e.SetRawConfidence(tt.enabled)
+      		if r := e.Evaluate("EVOLVE", TRUE, TRUE); r.RawConfidence != tt.want {
+            			t.Errorf("SetRawConfidence(%v): RawConfidence = %v, want %v", tt.enabled, r.RawConfidence, tt.want)
+            		}
+      	}
+  }
+
+func TestRawConfidenceJSON(t *testing.T) {
+  	tests := []struct {
+      		raw  bool
+      		want string
+      	}{
+      		{raw: true, want: `"raw_confidence":2`},
+      		{raw: false, want: ""},
+      	}
+  	for _, tt := range tests {
+      		e := NewEngine()
+      		e.SetRawConfidence(tt.raw)
+      		b, err := json.Marshal(e.Evaluate("EVOLVE", TRUE, TRUE))
+      		if err != nil {
+            			t.Fatal(err)
+            		}
+      		if got := strings.Contains(string(b), "raw_confidence"); got != tt.raw || !strings.Contains(string(b), tt.want) {
+            			t.Errorf("json.Marshal with raw %v = %s, want %s", tt.raw, b, tt.want)
+            		}
+      	}
+  }
+
+func TestRawConfidenceRanksCeiling(t *testing.T) {
+  	e := NewEngine(WithRawConfidence())
+  	results := []TernaryResult{
+      		e.Evaluate("AND", TRUE, TRUE),
+      		e.Evaluate("CONSENSUS", TRUE, TRUE),
+      		e.Evaluate("EVOLVE", TRUE, TRUE),
+      	}
+  	for i, r := range results {
+      		if r.Confidence != 1 {
+            			t.Errorf("%s confidence = %v, want the 1.0 ceiling", r.Rule, r.Confidence)
+            		}
+      		if i > 0 && !(r.RawConfidence > results[i-1].RawConfidence) {
+            			t.Errorf("%s raw %v does not outrank %s raw %v", r.Rule, r.RawConfidence, results[i-1].Rule, results[i-1].RawConfidence)
+            		}
+      	}
+  }
+
+func TestRawConfidenceOutsideBounds(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		min, max float64
+      		rule     string
+      		inputs   []Trit
+      		wantConf float64
+      		wantRaw  float64
+      	}{
+      		{name: "above ceiling", min: 0, max: 0.8, rule: "EVOLVE", inputs: []Trit{TRUE, TRUE}, wantConf: 0.8, wantRaw: 2},
+      		{name: "below floor", min: 0.6, max: 1, rule: "AND", inputs: []Trit{TRUE, UNKNOWN}, wantConf: 0.6, wantRaw: 0.5},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(WithRawConfidence())
+                    			if err := e.SetConfidenceBounds(tt.min, tt.max); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			r := e.Evaluate(tt.rule, tt.inputs...)
+                    			if r.Confidence != tt.wantConf || r.RawConfidence != tt.wantRaw {
+                              				t.Errorf("confidence = %v raw %v, want %v raw %v", r.Confidence, r.RawConfidence, tt.wantConf, tt.wantRaw)
+                              			}
+                    		})
+      	}
+  }
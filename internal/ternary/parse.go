@@ -0,0 +1,118 @@
+package ternary
+
+import (
+  	"bytes"
+  	"encoding/json"
+  	"fmt"
+  	"strings"
+  )
+
+// tritFromName maps a constant name such as "TRUE" to its Trit
+func tritFromName(name string) (Trit, bool) {
+  	switch strings.ToUpper(strings.TrimSpace(name)) {
+      	case "TRUE":
+      		return TRUE, true
+      	case "FALSE":
+      		return FALSE, true
+      	case "UNKNOWN":
+      		return UNKNOWN, true
+      	default:
+      		return UNKNOWN, false
+      	}
+  }
+
+// ParseTrit parses a trit from configuration text. It accepts, ignoring
+// case and surrounding space, "true"/"t"/"1", "false"/"f"/"-1" and
+// "unknown"/"u"/"0", as well as the String forms such as "█ TRUE" and their
+// bare glyphs. Anything else is an error rather than UNKNOWN.
+func ParseTrit(s string) (Trit, error) {
+  	switch strings.ToLower(strings.TrimSpace(s)) {
+      	case "true", "t", "1", "█", "█ true":
+      		return TRUE, nil
+      	case "false", "f", "-1", "░", "░ false":
+      		return FALSE, nil
+      	case "unknown", "u", "0", "▒", "▒ unknown":
+      		return UNKNOWN, nil
+      	default:
+      		return UNKNOWN, fmt.Errorf("ternary: cannot parse %q as a trit", s)
+      	}
+  }
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTrit
+func (t *Trit) UnmarshalText(text []byte) error {
+  	v, err := ParseTrit(string(text))
+  	if err != nil {
+      		return err
+      	}
+  	*t = v
+  	return nil
+  }
+
+// MarshalJSON implements json.Marshaler, encoding t as its constant name
+// ("TRUE", "FALSE" or "UNKNOWN"). Invalid trits encode as "INVALID", which
+// UnmarshalJSON rejects.
+func (t Trit) MarshalJSON() ([]byte, error) {
+  	return json.Marshal(tritName(t))
+  }
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the constant names
+// and other strings parsed by ParseTrit as well as the legacy integer form
+// (-1, 0, 1) that trits were encoded in before MarshalJSON.
+func (t *Trit) UnmarshalJSON(data []byte) error {
+  	trimmed := bytes.TrimSpace(data)
+  	if bytes.Equal(trimmed, []byte("null")) {
+      		return nil
+      	}
+  	if len(trimmed) > 0 && trimmed[0] == '"' {
+      		var s string
+      		if err := json.Unmarshal(trimmed, &s); err != nil {
+            			return err
+            		}
+      		return t.UnmarshalText([]byte(s))
+      	}
+  	var n int8
+  	if err := json.Unmarshal(trimmed, &n); err != nil || !IsValid(Trit(n)) {
+      		return fmt.Errorf("ternary: cannot decode %s as a trit", trimmed)
+      	}
+  	*t = Trit(n)
+  	return nil
+  }
+
+// tritName returns the constant name of t, or "INVALID"
+func tritName(t Trit) string {
+  	switch t {
+      	case TRUE:
+      		return "TRUE"
+      	case FALSE:
+      		return "FALSE"
+      	case UNKNOWN:
+      		return "UNKNOWN"
+      	default:
+      		return "INVALID"
+      	}
+  }
+
+// FromJSONField maps an optional JSON boolean to a trit: absent or null is
+// UNKNOWN, true is TRUE and false is FALSE. Non-boolean values are also
+// UNKNOWN; use FromJSONFieldE to reject them.
+func FromJSONField(raw json.RawMessage) Trit {
+  	t, _ := FromJSONFieldE(raw)
+  	return t
+  }
+
+// FromJSONFieldE is FromJSONField returning an error for present values
+// that are neither a boolean nor null
+func FromJSONFieldE(raw json.RawMessage) (Trit, error) {
+  	trimmed := bytes.TrimSpace(raw)
+  	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+      		return UNKNOWN, nil
+      	}
+  	var b bool
+  	if err := json.Unmarshal(trimmed, &b); err != nil {
+      		return UNKNOWN, fmt.Errorf("ternary: JSON field %s is not a boolean", trimmed)
+      	}
+  	if b {
+      		return TRUE, nil
+      	}
+  	return FALSE, nil
+  }
@@ -0,0 +1,207 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"fmt"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestParseTrit(t *testing.T) {
+  	tests := []struct {
+      		in      string
+      		want    Trit
+      		wantErr bool
+      	}{
+      		{in: "true", want: TRUE},
+      		{in: "T", want: TRUE},
+      		{in: "1", want: TRUE},
+      		{in: " false ", want: FALSE},
+      		{in: "f", want: FALSE},
+      		{in: "-1", want: FALSE},
+      		{in: "Unknown", want: UNKNOWN},
+      		{in: "U", want: UNKNOWN},
+      		{in: "0", want: UNKNOWN},
+      		{in: TRUE.String(), want: TRUE},
+      		{in: FALSE.String(), want: FALSE},
+      		{in: UNKNOWN.String(), want: UNKNOWN},
+      		{in: "█", want: TRUE},
+      		{in: "░", want: FALSE},
+      		{in: "▒", want: UNKNOWN},
+      		{in: "ture", wantErr: true},
+      		{in: "", wantErr: true},
+      		{in: "2", wantErr: true},
+      		{in: "yes", wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		got, err := ParseTrit(tt.in)
+      		if tt.wantErr {
+            			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%q", tt.in)) {
+                    				t.Errorf("ParseTrit(%q) = %v, %v, want error naming the input", tt.in, got, err)
+                    			}
+            			continue
+            		}
+      		if err != nil || got != tt.want {
+            			t.Errorf("ParseTrit(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+            		}
+      	}
+  }
+
+func TestTritUnmarshalText(t *testing.T) {
+  	var cfg struct{ A, B, C Trit }
+  	if err := json.Unmarshal([]byte(`{"A":"true","B":-1,"C":"u"}`), &cfg); err != nil || cfg.A != TRUE || cfg.B != FALSE || cfg.C != UNKNOWN {
+      		t.Errorf("Unmarshal config = %+v, %v", cfg, err)
+      	}
+  	var m map[Trit]int
+  	if err := json.Unmarshal([]byte(`{"1":2,"-1":3,"unknown":4}`), &m); err != nil || m[TRUE] != 2 || m[FALSE] != 3 || m[UNKNOWN] != 4 {
+      		t.Errorf("Unmarshal map keys = %v, %v", m, err)
+      	}
+  	if err := json.Unmarshal([]byte(`{"maybe":1}`), &m); err == nil {
+      		t.Error("Unmarshal accepted map key \"maybe\"")
+      	}
+  }
+
+func TestTritUnmarshalJSON(t *testing.T) {
+  	tests := []struct {
+      		in      string
+      		want    Trit
+      		wantErr bool
+      	}{
+      		{in: `"TRUE"`, want: TRUE},
+      		{in: `"unknown"`, want: UNKNOWN},
+      		{in: `"F"`, want: FALSE},
+      		{in: `1`, want: TRUE},
+      		{in: `0`, want: UNKNOWN},
+      		{in: `-1`, want: FALSE},
+      		{in: ` -1 `, want: FALSE},
+      		{in: `2`, wantErr: true},
+      		{in: `-2`, wantErr: true},
+      		{in: `127`, wantErr: true},
+      		{in: `300`, wantErr: true},
+      		{in: `0.5`, wantErr: true},
+      		{in: `"INVALID"`, wantErr: true},
+      		{in: `"yes"`, wantErr: true},
+      		{in: `true`, wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		var got Trit
+      		err := json.Unmarshal([]byte(tt.in), &got)
+      		if tt.wantErr {
+            			if err == nil {
+                    				t.Errorf("Unmarshal(%s) = %v, want error", tt.in, got)
+                    			}
+            			continue
+            		}
+      		if err != nil || got != tt.want {
+            			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.in, got, err, tt.want)
+            		}
+      	}
+  }
+
+func TestTritUnmarshalJSONOutOfRange(t *testing.T) {
+  	for _, in := range []string{`2`, `-2`, `127`} {
+      		var got Trit
+      		err := json.Unmarshal([]byte(in), &got)
+      		if err == nil || !strings.Contains(err.Error(), "cannot decode "+in+" as a trit") {
+            			t.Errorf("Unmarshal(%s) error = %v", in, err)
+            		}
+      	}
+  }
+
+func TestTritJSONRoundTrip(t *testing.T) {
+  	for _, v := range []Trit{TRUE, FALSE, UNKNOWN} {
+      		data, err := json.Marshal(v)
+      		if err != nil {
+            			t.Fatal(err)
+            		}
+      		var back Trit
+      		if err := json.Unmarshal(data, &back); err != nil || back != v {
+            			t.Errorf("round trip of %v = %v, %v", v, back, err)
+            		}
+      	}
+  	if data, _ := json.Marshal(Trit(7)); string(data) != `"INVALID"` {
+      		t.Errorf("Marshal(Trit(7)) = %s", data)
+      	}
+  }
+
+func TestFromJSONField(t *testing.T) {
+  	tests := []struct {
+      		name    string
+      		raw     string
+      		want    Trit
+      		wantErr bool
+      	}{
+      		{name: "absent", raw: "", want: UNKNOWN},
+      		{name: "null", raw: "null", want: UNKNOWN},
+      		{name: "padded null", raw: " null\n", want: UNKNOWN},
+      		{name: "true", raw: "true", want: TRUE},
+      		{name: "false", raw: "false", want: FALSE},
+      		{name: "string", raw: `"yes"`, want: UNKNOWN, wantErr: true},
+      		{name: "number", raw: "1", want: UNKNOWN, wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := FromJSONFieldE(json.RawMessage(tt.raw))
+                    			if got != tt.want || (err != nil) != tt.wantErr {
+                              				t.Errorf("FromJSONFieldE(%q) = %v, %v, want %v, wantErr %v", tt.raw, got, err, tt.want, tt.wantErr)
+                              			}
+                    			if got := FromJSONField(json.RawMessage(tt.raw)); got != tt.want {
+                              				t.Errorf("FromJSONField(%q) = %v, want %v", tt.raw, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestFromJSONFieldStruct(t *testing.T) {
+  	var v struct {
+      		A json.RawMessage `json:"a"`
+      		B json.RawMessage `json:"b"`
+      		C json.RawMessage `json:"c"`
+      	}
+  	if err := json.Unmarshal([]byte(`{"b":null,"c":true}`), &v); err != nil {
+      		t.Fatal(err)
+      	}
+  	if a, b, c := FromJSONField(v.A), FromJSONField(v.B), FromJSONField(v.C); a != UNKNOWN || b != UNKNOWN || c != TRUE {
+      		t.Errorf("fields = %v, %v, %v, want UNKNOWN, UNKNOWN, TRUE", a, b, c)
+      	}
+  }
+
+func TestTernaryResultJSON(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		json       string
+      		wantValue  Trit
+      		wantInputs []Trit
+      	}{
+      		{name: "names", json: `{"id":"x","value":"UNKNOWN","inputs":["TRUE","UNKNOWN"]}`, wantValue: UNKNOWN, wantInputs: []Trit{TRUE, UNKNOWN}},
+      		{name: "legacy integers", json: `{"id":"x","value":-1,"inputs":[1,0]}`, wantValue: FALSE, wantInputs: []Trit{TRUE, UNKNOWN}},
+      		{name: "mixed", json: `{"id":"x","value":"t","inputs":[-1,"false"]}`, wantValue: TRUE, wantInputs: []Trit{FALSE, FALSE}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			var r TernaryResult
+                    			if err := json.Unmarshal([]byte(tt.json), &r); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if r.Value != tt.wantValue || !reflect.DeepEqual(r.Inputs, tt.wantInputs) {
+                              				t.Errorf("Unmarshal(%s) = %v %v, want %v %v", tt.json, r.Value, r.Inputs, tt.wantValue, tt.wantInputs)
+                              			}
+                    		})
+      	}
+
+  	r := NewEngine(CaptureInputs()).Evaluate("AND", TRUE, UNKNOWN)
+  	data, err := json.Marshal(r)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	for _, want := range []string{`"value":"UNKNOWN"`, `"inputs":["TRUE","UNKNOWN"]`} {
+      		if !strings.Contains(string(data), want) {
+            			t.Errorf("Marshal = %s, want it to contain %s", data, want)
+            		}
+      	}
+  	var back TernaryResult
+  	if err := json.Unmarshal(data, &back); err != nil || back.Value != r.Value || !reflect.DeepEqual(back.Inputs, r.Inputs) {
+      		t.Errorf("round trip = %+v, %v", back, err)
+      	}
+  }
@@ -0,0 +1,107 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"fmt"
+  	"math"
+  	"sort"
+  )
+
+// rulePatch is the parsed change to one rule in PatchConfig
+type rulePatch struct {
+  	weight  *float64
+  	enabled *bool
+  	tags    []string
+  	setTags bool
+  }
+
+// PatchConfig applies a JSON merge patch (RFC 7396) to the rule
+// configuration, an object keyed by rule name whose members mirror
+// RuleInfo:
+//
+//	{"CONSENSUS": {"weight": 2}, "EVOLVE": {"enabled": false, "tags": ["beta"]}}
+//
+// Fields left out are untouched and a null "tags" clears the tags. The
+// whole patch is validated before anything changes: unknown rules or
+// fields, removing a rule or its weight or enabled flag, and negative or
+// non-finite weights are errors. Enabling a rule disables the other
+// members of its exclusive groups, as SetRuleEnabled does.
+func (e *Engine) PatchConfig(patch []byte) error {
+  	var doc map[string]map[string]json.RawMessage
+  	if err := json.Unmarshal(patch, &doc); err != nil {
+      		return fmt.Errorf("ternary: config patch: %w", err)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	names := make([]string, 0, len(doc))
+  	changes := make(map[string]rulePatch, len(doc))
+  	for name, fields := range doc {
+      		if _, exists := e.rules[name]; !exists {
+            			return fmt.Errorf("ternary: config patch: unknown rule %q", name)
+            		}
+      		if fields == nil {
+            			return fmt.Errorf("ternary: config patch: rule %q cannot be removed", name)
+            		}
+      		change, err := parseRulePatch(name, fields)
+      		if err != nil {
+            			return err
+            		}
+      		names = append(names, name)
+      		changes[name] = change
+      	}
+  	sort.Strings(names)
+
+  	for _, name := range names {
+      		change := changes[name]
+      		rule := e.rules[name]
+      		if change.weight != nil {
+            			rule.Weight = *change.weight
+            		}
+      		if change.setTags {
+            			rule.Tags = change.tags
+            		}
+      		e.rules[name] = rule
+      		if change.enabled != nil {
+            			e.setRuleEnabledLocked(name, *change.enabled)
+            		}
+      	}
+  	return nil
+  }
+
+// parseRulePatch decodes and validates the patch fields of rule name
+func parseRulePatch(name string, fields map[string]json.RawMessage) (rulePatch, error) {
+  	var change rulePatch
+  	for field, raw := range fields {
+      		isNull := string(raw) == "null"
+      		var err error
+      		switch field {
+            		case "weight":
+            			if isNull {
+                    				return change, fmt.Errorf("ternary: config patch: rule %q weight cannot be removed", name)
+                    			}
+            			var w float64
+            			if err = json.Unmarshal(raw, &w); err == nil && (w < 0 || math.IsInf(w, 0)) {
+                    				err = fmt.Errorf("invalid weight %v", w)
+                    			}
+            			change.weight = &w
+            		case "enabled":
+            			if isNull {
+                    				return change, fmt.Errorf("ternary: config patch: rule %q enabled cannot be removed", name)
+                    			}
+            			var enabled bool
+            			err = json.Unmarshal(raw, &enabled)
+            			change.enabled = &enabled
+            		case "tags":
+            			change.setTags = true
+            			err = json.Unmarshal(raw, &change.tags)
+            		default:
+            			return change, fmt.Errorf("ternary: config patch: rule %q has unknown field %q", name, field)
+            		}
+      		if err != nil {
+            			return change, fmt.Errorf("ternary: config patch: rule %q %s: %w", name, field, err)
+            		}
+      	}
+  	return change, nil
+  }
@@ -0,0 +1,77 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestPatchConfig(t *testing.T) {
+  	tests := []struct {
+      		name    string
+      		setup   func(e *Engine)
+      		patch   string
+      		want    map[string]RuleInfo // rules expected to change
+      		wantErr string
+      	}{
+      		{
+            			name:  "weight",
+            			patch: `{"CONSENSUS": {"weight": 2.5}}`,
+            			want:  map[string]RuleInfo{"CONSENSUS": {Name: "CONSENSUS", Weight: 2.5, Enabled: true}},
+            		},
+      		{
+            			name:  "enabled and tags",
+            			patch: `{"EVOLVE": {"enabled": false, "tags": ["beta"]}}`,
+            			want:  map[string]RuleInfo{"EVOLVE": {Name: "EVOLVE", Weight: 2, Enabled: false, Tags: []string{"beta"}}},
+            		},
+      		{
+            			name:  "null tags clear",
+            			setup: func(e *Engine) { e.PatchConfig([]byte(`{"AND": {"tags": ["core"]}}`)) },
+            			patch: `{"AND": {"tags": null}}`,
+            			want:  map[string]RuleInfo{"AND": {Name: "AND", Weight: 1, Enabled: true}},
+            		},
+      		{
+            			name:  "several rules",
+            			patch: `{"AND": {"weight": 0}, "OR": {"weight": 3}}`,
+            			want:  map[string]RuleInfo{"AND": {Name: "AND", Enabled: true}, "OR": {Name: "OR", Weight: 3, Enabled: true}},
+            		},
+      		{
+            			name:  "exclusive group",
+            			setup: func(e *Engine) { e.SetExclusiveGroup("g", "AND", "OR") },
+            			patch: `{"OR": {"enabled": true}}`,
+            			want:  map[string]RuleInfo{"AND": {Name: "AND", Weight: 1, Enabled: false}, "OR": {Name: "OR", Weight: 1, Enabled: true}},
+            		},
+      		{name: "empty", patch: `{}`},
+      		{name: "not an object", patch: `[]`, wantErr: "config patch"},
+      		{name: "unknown rule", patch: `{"NOPE": {"weight": 1}}`, wantErr: `unknown rule "NOPE"`},
+      		{name: "rule removed", patch: `{"AND": null}`, wantErr: `rule "AND" cannot be removed`},
+      		{name: "unknown field", patch: `{"AND": {"colour": 1}}`, wantErr: `unknown field "colour"`},
+      		{name: "negative weight", patch: `{"AND": {"weight": -1}}`, wantErr: "invalid weight -1"},
+      		{name: "weight removed", patch: `{"AND": {"weight": null}}`, wantErr: "weight cannot be removed"},
+      		{name: "enabled removed", patch: `{"AND": {"enabled": null}}`, wantErr: "enabled cannot be removed"},
+      		{name: "bad type", patch: `{"AND": {"enabled": "yes"}}`, wantErr: `rule "AND" enabled`},
+      		{name: "nothing applied on error", patch: `{"AND": {"weight": 3}, "OR": {"weight": "x"}}`, wantErr: `rule "OR" weight`},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if tt.setup != nil {
+                              				tt.setup(e)
+                              			}
+                    			before := e.Rules()
+                    			err := e.PatchConfig([]byte(tt.patch))
+                    			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+                              				t.Fatalf("PatchConfig(%s) error = %v, want %q", tt.patch, err, tt.wantErr)
+                              			}
+                    			for i, r := range e.Rules() {
+                              				want, changed := tt.want[r.Name]
+                              				if !changed {
+                                          					want = before[i]
+                                          				}
+                              				if !reflect.DeepEqual(r, want) {
+                                          					t.Errorf("rule %s = %+v, want %+v", r.Name, r, want)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,114 @@
+package ternary
+
+import "strings"
+
+// Pipeline chains rule evaluations, feeding each step's value to the next
+// step as its first input. Build one with Engine.Begin:
+//
+//	r := engine.Begin().Apply("AND", a, b).Apply("OR", c).Result()
+//
+// evaluates OR(AND(a, b), c). A Pipeline is not safe for concurrent use.
+type Pipeline struct {
+  	engine  *Engine
+  	last    TernaryResult
+  	started bool
+  	failed  bool
+  }
+
+// Begin starts an empty Pipeline on the engine
+func (e *Engine) Begin() *Pipeline {
+  	return &Pipeline{engine: e}
+  }
+
+// Apply evaluates ruleName over the previous step's value followed by
+// inputs; the first step sees only inputs. Each step is recorded like an
+// Evaluate call. Once a step names an unknown or disabled rule the pipeline
+// keeps that failure and ignores later steps.
+func (p *Pipeline) Apply(ruleName string, inputs ...Trit) *Pipeline {
+  	if p.failed {
+      		return p
+      	}
+
+  	args := inputs
+  	if p.started {
+      		args = append([]Trit{p.last.Value}, inputs...)
+      	}
+
+  	p.engine.mu.Lock()
+  	result, ok := p.engine.tryEvaluateLocked(ruleName, args)
+  	p.engine.mu.Unlock()
+
+  	p.last, p.started, p.failed = result, true, !ok
+  	return p
+  }
+
+// Result returns the last step's result, or UNKNOWN if no step was applied
+func (p *Pipeline) Result() TernaryResult {
+  	if !p.started {
+      		return p.engine.failedResult("Empty pipeline")
+      	}
+  	return p.last
+  }
+
+// ChainRules returns a composite rule, for registering with AddRule, that
+// runs the named rules in order as Pipeline does: the first rule sees the
+// inputs and every later rule sees the previous rule's value followed by
+// the same inputs. With n inputs the first rule is thus called with n
+// trits and the others with n+1, so a later rule with a positive Arity
+// must have Arity n+1 or its step, and the chain, yields UNKNOWN. The
+// composite takes the Arity of the first rule.
+//
+// The rules are looked up when ChainRules is called and are used as
+// registered then, regardless of later changes or disabling. If any of
+// them is not registered the composite always evaluates to UNKNOWN.
+func (e *Engine) ChainRules(names ...string) TernaryRule {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	chain := TernaryRule{
+      		Name:   "CHAIN(" + strings.Join(names, ", ") + ")",
+      		Weight: 1.0,
+      	}
+  	steps := make([]TernaryRule, len(names))
+  	for i, name := range names {
+      		rule, exists := e.rules[name]
+      		if !exists {
+            			chain.Evaluate = func(inputs ...Trit) Trit { return UNKNOWN }
+            			return chain
+            		}
+      		steps[i] = rule
+      	}
+  	if len(steps) > 0 {
+      		chain.Arity = steps[0].Arity
+      	}
+
+  	chain.Evaluate = func(inputs ...Trit) Trit {
+      		if len(steps) == 0 {
+            			return UNKNOWN
+            		}
+      		value := chainStep(steps[0], inputs)
+      		if len(steps) == 1 {
+            			return value
+            		}
+      		args := make([]Trit, len(inputs)+1)
+      		copy(args[1:], inputs)
+      		for _, rule := range steps[1:] {
+            			args[0] = value
+            			value = chainStep(rule, args)
+            		}
+      		return value
+      	}
+  	return chain
+  }
+
+// chainStep runs one rule of a ChainRules composite over args
+func chainStep(rule TernaryRule, args []Trit) Trit {
+  	if rule.Arity > 0 && len(args) != rule.Arity {
+      		return UNKNOWN
+      	}
+  	if rule.mu != nil {
+      		rule.mu.Lock()
+      		defer rule.mu.Unlock()
+      	}
+  	return rule.Evaluate(args...)
+  }
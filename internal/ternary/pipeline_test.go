@@ -0,0 +1,138 @@
+package ternary
+
+import (
+  	"slices"
+  	"strings"
+  	"testing"
+  )
+
+func TestPipeline(t *testing.T) {
+  	type step struct {
+      		rule   string
+      		inputs []Trit
+      	}
+  	tests := []struct {
+      		name       string
+      		steps      []step
+      		want       Trit
+      		wantCount  int
+      		wantReason string
+      		recorded   int
+      	}{
+      		{name: "empty", want: UNKNOWN, wantReason: "Empty pipeline"},
+      		{name: "single step", steps: []step{{"AND", []Trit{TRUE, FALSE}}}, want: FALSE, wantCount: 2, recorded: 1},
+      		{name: "value fed forward", steps: []step{{"AND", []Trit{TRUE, FALSE}}, {"OR", []Trit{TRUE}}}, want: TRUE, wantCount: 2, recorded: 2},
+      		{name: "unary step", steps: []step{{"AND", []Trit{TRUE, TRUE}}, {"NOT", nil}}, want: FALSE, wantCount: 1, recorded: 2},
+      		{
+            			name:       "failure sticks",
+            			steps:      []step{{"AND", []Trit{TRUE}}, {"nope", nil}, {"OR", []Trit{TRUE}}},
+            			want:       UNKNOWN,
+            			wantReason: "not found",
+            			recorded:   1,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			p := e.Begin()
+                    			for _, s := range tt.steps {
+                              				p = p.Apply(s.rule, s.inputs...)
+                              			}
+                    			r := p.Result()
+                    			if r.Value != tt.want || r.InputCount != tt.wantCount || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("Result = %v over %d inputs %q, want %v over %d containing %q",
+                                          					r.Value, r.InputCount, r.Reason, tt.want, tt.wantCount, tt.wantReason)
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{})); n != tt.recorded {
+                              				t.Errorf("recorded %d decisions, want %d", n, tt.recorded)
+                              			}
+                    		})
+      	}
+  }
+
+func TestChainRules(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		rules  []string
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{name: "nand true", rules: []string{"AND", "NOT"}, inputs: []Trit{TRUE, TRUE}, want: FALSE},
+      		{name: "nand false", rules: []string{"AND", "NOT"}, inputs: []Trit{TRUE, FALSE}, want: TRUE},
+      		{name: "nand unknown", rules: []string{"AND", "NOT"}, inputs: []Trit{UNKNOWN, TRUE}, want: UNKNOWN},
+      		// CONSENSUS(OR(AND(a, b), a, b), a, b)
+      		{name: "triple mixed", rules: []string{"AND", "OR", "CONSENSUS"}, inputs: []Trit{TRUE, FALSE}, want: TRUE},
+      		{name: "triple false", rules: []string{"AND", "OR", "CONSENSUS"}, inputs: []Trit{FALSE, FALSE}, want: FALSE},
+      		{name: "triple unknown", rules: []string{"AND", "OR", "CONSENSUS"}, inputs: []Trit{TRUE, UNKNOWN}, want: TRUE},
+      		// IMPLIES has Arity 2, so only sees two trits after a one-input NOT
+      		{name: "arity met", rules: []string{"NOT", "IMPLIES"}, inputs: []Trit{TRUE}, want: TRUE},
+      		{name: "arity missed", rules: []string{"NOT", "IMPLIES"}, inputs: []Trit{TRUE, TRUE}, want: UNKNOWN},
+      		{name: "unregistered", rules: []string{"AND", "NOPE"}, inputs: []Trit{TRUE}, want: UNKNOWN},
+      		{name: "empty", inputs: []Trit{TRUE}, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("CHAINED", e.ChainRules(tt.rules...))
+                    			if got := e.Evaluate("CHAINED", tt.inputs...).Value; got != tt.want {
+                              				t.Errorf("ChainRules(%v)(%v) = %v, want %v", tt.rules, tt.inputs, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestChainRulesShape(t *testing.T) {
+  	tests := []struct {
+      		rules     []string
+      		wantName  string
+      		wantArity int
+      	}{
+      		{rules: []string{"IMPLIES", "NOT"}, wantName: "CHAIN(IMPLIES, NOT)", wantArity: 2},
+      		{rules: []string{"AND", "NOT"}, wantName: "CHAIN(AND, NOT)", wantArity: 0},
+      		{rules: []string{"NOT"}, wantName: "CHAIN(NOT)", wantArity: 0},
+      	}
+  	for _, tt := range tests {
+      		c := NewEngine().ChainRules(tt.rules...)
+      		if c.Name != tt.wantName || c.Arity != tt.wantArity {
+            			t.Errorf("ChainRules(%v) = %q arity %d, want %q arity %d", tt.rules, c.Name, c.Arity, tt.wantName, tt.wantArity)
+            		}
+      	}
+  }
+
+func TestChainRulesStepInputs(t *testing.T) {
+  	e := NewEngine()
+  	var seen [][]Trit
+  	spy := func(out Trit) TernaryRule {
+      		return TernaryRule{Weight: 1, Evaluate: func(inputs ...Trit) Trit {
+                    			seen = append(seen, slices.Clone(inputs))
+                    			return out
+                    		}}
+      	}
+  	e.AddRule("FIRST", spy(UNKNOWN))
+  	e.AddRule("SECOND", spy(FALSE))
+  	e.AddRule("THIRD", spy(TRUE))
+  	e.AddRule("CHAINED", e.ChainRules("FIRST", "SECOND", "THIRD"))
+
+  	if got := e.Evaluate("CHAINED", TRUE, FALSE).Value; got != TRUE {
+      		t.Errorf("chain = %v, want the last rule's TRUE", got)
+      	}
+  	want := [][]Trit{
+      		{TRUE, FALSE},
+      		{UNKNOWN, TRUE, FALSE},
+      		{FALSE, TRUE, FALSE},
+      	}
+  	if !slices.EqualFunc(seen, want, slices.Equal[[]Trit]) {
+      		t.Errorf("steps saw %v, want %v", seen, want)
+      	}
+  }
+
+func TestChainRulesBindsAtCall(t *testing.T) {
+  	e := NewEngine()
+  	e.AddRule("CHAINED", e.ChainRules("AND", "NOT"))
+  	e.AddRule("NOT", TernaryRule{Weight: 1, Evaluate: func(inputs ...Trit) Trit { return UNKNOWN }})
+  	e.DisableRule("AND")
+
+  	if got := e.Evaluate("CHAINED", TRUE, TRUE).Value; got != FALSE {
+      		t.Errorf("chain after redefining NOT and disabling AND = %v, want FALSE from the rules bound at ChainRules", got)
+      	}
+  }
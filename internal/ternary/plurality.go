@@ -0,0 +1,168 @@
+package ternary
+
+import "fmt"
+
+// TieBreak decides between candidates that are tied in Plurality and
+// MostConfidentRule. The zero value, TieBreakUnknown, is the default.
+type TieBreak int
+
+const (
+  	// TieBreakUnknown prefers UNKNOWN when it is among the tied values, and
+  	// otherwise the candidate appearing earliest, as TieBreakFirst
+  	TieBreakUnknown TieBreak = iota
+  	// TieBreakFirst prefers the candidate appearing earliest
+  	TieBreakFirst
+  	// TieBreakTrue prefers TRUE, then UNKNOWN, then FALSE
+  	TieBreakTrue
+  )
+
+// SetTieBreak sets the tie-break used by the PLURALITY rule and MostConfidentRule
+func (e *Engine) SetTieBreak(tb TieBreak) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.tieBreak = tb
+  }
+
+// Plurality returns the most frequent value among inputs, resolving ties
+// with tb. Empty input yields UNKNOWN.
+func Plurality(tb TieBreak, inputs ...Trit) Trit {
+  	if len(inputs) == 0 {
+      		return UNKNOWN
+      	}
+
+  	counts := make(map[Trit]int, 3)
+  	best := 0
+  	for _, inp := range inputs {
+      		counts[inp]++
+      		if counts[inp] > best {
+            			best = counts[inp]
+            		}
+      	}
+
+  	// tied holds the leading values in order of first appearance
+  	var tied []Trit
+  	seen := make(map[Trit]bool, 3)
+  	for _, inp := range inputs {
+      		if counts[inp] == best && !seen[inp] {
+            			seen[inp] = true
+            			tied = append(tied, inp)
+            		}
+      	}
+  	return breakTie(tb, tied)
+  }
+
+// breakTie picks one of the candidate values, which are ordered by first
+// appearance
+func breakTie(tb TieBreak, candidates []Trit) Trit {
+  	if len(candidates) == 1 {
+      		return candidates[0]
+      	}
+  	switch tb {
+      	case TieBreakUnknown:
+      		for _, c := range candidates {
+            			if c == UNKNOWN {
+                    				return c
+                    			}
+            		}
+      	case TieBreakTrue:
+      		for _, pref := range []Trit{TRUE, UNKNOWN, FALSE} {
+            			for _, c := range candidates {
+                    				if c == pref {
+                              					return c
+                              				}
+                    			}
+            		}
+      	}
+  	return candidates[0]
+  }
+
+// MostConfidentRule evaluates each named rule over inputs and returns the
+// result with the highest confidence. Results tied on confidence are
+// resolved with the engine's TieBreak applied to their values, in the order
+// the rules were named; TieBreakUnknown keeps an UNKNOWN result if one is
+// tied and otherwise falls back to the first tied rule. The call counts as one
+// evaluation and only the returned result is recorded.
+func (e *Engine) MostConfidentRule(ruleNames []string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+  	var tied []TernaryResult
+  	rejected := ""
+  	for _, name := range ruleNames {
+      		if _, _, ok := e.ruleLocked(name); !ok {
+            			continue
+            		}
+      		result, ok := e.previewLocked(name, inputs)
+      		if !ok {
+            			rejected = result.Reason
+            			continue
+            		}
+      		switch {
+            		case len(tied) == 0 || result.Confidence > tied[0].Confidence:
+            			tied = []TernaryResult{result}
+            		case result.Confidence == tied[0].Confidence:
+            			tied = append(tied, result)
+            		}
+      	}
+
+  	if len(tied) == 0 {
+      		if rejected != "" {
+            			return e.failedResult(rejected)
+            		}
+      		return e.failedResult(fmt.Sprintf("None of rules %v available", ruleNames))
+      	}
+
+  	values := make([]Trit, len(tied))
+  	for i, r := range tied {
+      		values[i] = r.Value
+      	}
+  	winner := tied[0]
+  	want := breakTie(e.tieBreak, values)
+  	for _, r := range tied {
+      		if r.Value == want {
+            			winner = r
+            			break
+            		}
+      	}
+  	return e.recordLocked(winner)
+  }
+
+// Margin returns the more common definite value when its count leads the
+// other definite value's by at least minGap, and UNKNOWN otherwise. UNKNOWN
+// inputs count for neither side.
+func Margin(minGap int, inputs ...Trit) Trit {
+  	trueCount, falseCount := 0, 0
+  	for _, inp := range inputs {
+      		switch inp {
+            		case TRUE:
+            			trueCount++
+            		case FALSE:
+            			falseCount++
+            		}
+      	}
+  	switch gap := trueCount - falseCount; {
+      	case gap > 0 && gap >= minGap:
+      		return TRUE
+      	case gap < 0 && -gap >= minGap:
+      		return FALSE
+      	default:
+      		return UNKNOWN
+      	}
+  }
+
+// EvaluateMargin records a MARGIN decision: the dominant definite value if
+// it leads by at least minGap votes, else UNKNOWN
+func (e *Engine) EvaluateMargin(minGap int, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+  	inputs, reason, ok := e.sanitizeLocked("MARGIN", inputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	value := Margin(minGap, inputs...)
+  	reason = fmt.Sprintf("Rule[MARGIN] evaluated %d inputs with minimum gap %d", len(inputs), minGap)
+  	return e.recordLocked(e.resultLocked("MARGIN", 1.0, value, inputs, reason))
+  }
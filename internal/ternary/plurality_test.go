@@ -0,0 +1,174 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestPlurality(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		inputs []Trit
+      		want   map[TieBreak]Trit
+      	}{
+      		{
+            			name:   "no tie",
+            			inputs: []Trit{FALSE, TRUE, FALSE},
+            			want:   map[TieBreak]Trit{TieBreakUnknown: FALSE, TieBreakFirst: FALSE, TieBreakTrue: FALSE},
+            		},
+      		{
+            			name:   "TRUE FALSE tie",
+            			inputs: []Trit{FALSE, TRUE},
+            			want:   map[TieBreak]Trit{TieBreakUnknown: FALSE, TieBreakFirst: FALSE, TieBreakTrue: TRUE},
+            		},
+      		{
+            			name:   "TRUE FALSE tie, TRUE first",
+            			inputs: []Trit{TRUE, FALSE, UNKNOWN, FALSE, TRUE},
+            			want:   map[TieBreak]Trit{TieBreakUnknown: TRUE, TieBreakFirst: TRUE, TieBreakTrue: TRUE},
+            		},
+      		{
+            			name:   "three-way tie",
+            			inputs: []Trit{FALSE, TRUE, UNKNOWN},
+            			want:   map[TieBreak]Trit{TieBreakUnknown: UNKNOWN, TieBreakFirst: FALSE, TieBreakTrue: TRUE},
+            		},
+      		{
+            			name:   "FALSE UNKNOWN tie",
+            			inputs: []Trit{FALSE, UNKNOWN},
+            			want:   map[TieBreak]Trit{TieBreakUnknown: UNKNOWN, TieBreakFirst: FALSE, TieBreakTrue: UNKNOWN},
+            		},
+      		{
+            			name: "empty",
+            			want: map[TieBreak]Trit{TieBreakUnknown: UNKNOWN, TieBreakFirst: UNKNOWN, TieBreakTrue: UNKNOWN},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			for tb, want := range tt.want {
+                              				// ties must resolve the same way on every run
+                              				for i := 0; i < 20; i++ {
+                                          					if got := Plurality(tb, tt.inputs...); got != want {
+                                                        						t.Fatalf("Plurality(%d, %v) = %v, want %v", tb, tt.inputs, got, want)
+                                                        					}
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestPluralityRule(t *testing.T) {
+  	e := NewEngine()
+  	if r := e.Evaluate("PLURALITY", FALSE, TRUE); r.Value != FALSE {
+      		t.Errorf("default PLURALITY(FALSE, TRUE) = %v, want FALSE", r.Value)
+      	}
+  	e.SetTieBreak(TieBreakTrue)
+  	if r := e.Evaluate("PLURALITY", FALSE, TRUE, UNKNOWN); r.Value != TRUE {
+      		t.Errorf("PLURALITY(FALSE, TRUE, UNKNOWN) with TieBreakTrue = %v, want TRUE", r.Value)
+      	}
+  }
+
+func TestMostConfidentRule(t *testing.T) {
+  	constant := func(v Trit, weight float64) TernaryRule {
+      		return TernaryRule{Evaluate: func(...Trit) Trit { return v }, Weight: weight}
+      	}
+  	tests := []struct {
+      		name  string
+      		tb    TieBreak
+      		rules []string
+      		want  string
+      	}{
+      		{name: "highest confidence", tb: TieBreakUnknown, rules: []string{"MAYBE", "YES"}, want: "YES"},
+      		{name: "unknown tied", tb: TieBreakUnknown, rules: []string{"HALF_YES", "MAYBE"}, want: "MAYBE"},
+      		{name: "unknown not tied", tb: TieBreakUnknown, rules: []string{"NO", "ZERO_YES"}, want: "NO"},
+      		{name: "first", tb: TieBreakFirst, rules: []string{"HALF_YES", "MAYBE"}, want: "HALF_YES"},
+      		{name: "true", tb: TieBreakTrue, rules: []string{"NO", "ZERO_YES"}, want: "ZERO_YES"},
+      		{name: "missing rules skipped", tb: TieBreakUnknown, rules: []string{"nope", "MAYBE"}, want: "MAYBE"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("YES", constant(TRUE, 1))
+                    			e.AddRule("HALF_YES", constant(TRUE, 0.5))
+                    			e.AddRule("ZERO_YES", constant(TRUE, 0))
+                    			e.AddRule("MAYBE", constant(UNKNOWN, 1))
+                    			e.AddRule("NO", constant(FALSE, 1))
+                    			e.SetTieBreak(tt.tb)
+                    			if r := e.MostConfidentRule(tt.rules); r.Rule != tt.want {
+                              				t.Errorf("MostConfidentRule(%v) picked %q, want %q", tt.rules, r.Rule, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestMostConfidentRuleRecordsWinner(t *testing.T) {
+  	tests := []struct {
+      		name        string
+      		rules       []string
+      		inputs      []Trit
+      		wantRule    string
+      		wantHistory int
+      	}{
+      		{name: "three candidates", rules: []string{"AND", "OR", "CONSENSUS"}, inputs: []Trit{TRUE, FALSE}, wantRule: "OR", wantHistory: 1},
+      		{name: "missing skipped", rules: []string{"nope", "AND"}, inputs: []Trit{FALSE}, wantRule: "AND", wantHistory: 1},
+      		{name: "none available", rules: []string{"nope"}, wantHistory: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r := e.MostConfidentRule(tt.rules, tt.inputs...)
+                    			if r.Rule != tt.wantRule {
+                              				t.Errorf("MostConfidentRule(%v) picked %q, want %q", tt.rules, r.Rule, tt.wantRule)
+                              			}
+                    			history := e.GetDecisions(DecisionFilter{})
+                    			if len(history) != tt.wantHistory || (tt.wantHistory == 1 && history[0].ID != r.ID) {
+                              				t.Errorf("history = %v, want only the winner", history)
+                              			}
+                    			if got := e.Stats()["total_evaluations"]; got != uint64(1) {
+                              				t.Errorf("total_evaluations = %v, want 1", got)
+                              			}
+                    			for name, st := range e.RuleStats() {
+                              				if name != tt.wantRule && st.Invocations != 0 {
+                                          					t.Errorf("RuleStats[%s] = %+v, want no evaluations for a losing rule", name, st)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestMostConfidentRuleRejectedInputs(t *testing.T) {
+  	e := NewEngine(WithInvalidInputs(RejectInvalid))
+  	r := e.MostConfidentRule([]string{"AND", "OR"}, TRUE, Trit(7))
+  	if r.Value != UNKNOWN || !strings.Contains(r.Reason, "rejected invalid input 7") {
+      		t.Errorf("MostConfidentRule with invalid input = %v %q, want the rejection", r.Value, r.Reason)
+      	}
+  	if n := len(e.GetDecisions(DecisionFilter{})); n != 0 {
+      		t.Errorf("recorded %d decisions, want 0", n)
+      	}
+  }
+
+func TestMargin(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		minGap int
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{name: "lead meets gap", minGap: 1, inputs: []Trit{TRUE, TRUE, TRUE, FALSE, FALSE}, want: TRUE},
+      		{name: "lead short of gap", minGap: 2, inputs: []Trit{TRUE, TRUE, TRUE, FALSE, FALSE}, want: UNKNOWN},
+      		{name: "false lead", minGap: 2, inputs: []Trit{FALSE, FALSE, FALSE, TRUE}, want: FALSE},
+      		{name: "unknowns count for neither", minGap: 1, inputs: []Trit{UNKNOWN, UNKNOWN, UNKNOWN, TRUE}, want: TRUE},
+      		{name: "tie", minGap: 0, inputs: []Trit{TRUE, FALSE}, want: UNKNOWN},
+      		{name: "zero gap", minGap: 0, inputs: []Trit{TRUE}, want: TRUE},
+      		{name: "empty", minGap: 0, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := Margin(tt.minGap, tt.inputs...); got != tt.want {
+                              				t.Errorf("Margin(%d, %v) = %v, want %v", tt.minGap, tt.inputs, got, tt.want)
+                              			}
+                    			r := NewEngine().EvaluateMargin(tt.minGap, tt.inputs...)
+                    			if r.Value != tt.want || r.Rule != "MARGIN" || r.InputCount != len(tt.inputs) {
+                              				t.Errorf("EvaluateMargin = %v from %q over %d inputs, want %v", r.Value, r.Rule, r.InputCount, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,58 @@
+package ternary
+
+import "fmt"
+
+// EvaluateWithPrior evaluates ruleName with the value of its most recent
+// retained decision added as one more input of weight priorWeight, the
+// other inputs weighing 1, and records the decision. The rule must accept
+// weights, as CONSENSUS and WEIGHTED_CONSENSUS do. Without a prior decision
+// this is a plain Evaluate.
+func (e *Engine) EvaluateWithPrior(ruleName string, priorWeight float64, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	prior, found := e.lastDecisionLocked(ruleName)
+  	if !found {
+      		return e.evaluateLocked(ruleName, inputs)
+      	}
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+  	if rule.Weighted == nil {
+      		return e.failedResult(fmt.Sprintf("Rule '%s' does not accept weights", ruleName))
+      	}
+
+  	inputs, reason, ok := e.sanitizeLocked(ruleName, inputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	all := make([]Trit, 0, len(inputs)+1)
+  	all = append(append(all, inputs...), prior.Value)
+  	weights := make([]float64, len(all))
+  	for i := range inputs {
+      		weights[i] = 1.0
+      	}
+  	weights[len(inputs)] = priorWeight
+
+  	value := e.weightedLocked(rule, all, weights)
+  	reason = fmt.Sprintf("Rule[%s] evaluated %d inputs with prior %s (weight %g)",
+      		ruleName, len(inputs), tritName(prior.Value), priorWeight)
+  	return e.recordLocked(e.resultLocked(ruleName, rule.Weight, value, all, reason))
+  }
+
+// lastDecisionLocked returns the newest retained decision of ruleName,
+// walking the ring backwards from e.head without copying it. The caller
+// must hold e.mu.
+func (e *Engine) lastDecisionLocked(ruleName string) (TernaryResult, bool) {
+  	n := len(e.decisions)
+  	for k := 1; k <= n; k++ {
+      		if d := &e.decisions[(e.head-k+n)%n]; d.Rule == ruleName {
+            			return *d, true
+            		}
+      	}
+  	return TernaryResult{}, false
+  }
@@ -0,0 +1,112 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestEvaluateWithPrior(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		capacity   int
+      		history    func(e *Engine)
+      		rule       string
+      		inputs     []Trit
+      		want       Trit
+      		wantReason string
+      	}{
+      		{
+            			name:       "no prior",
+            			capacity:   DefaultHistoryCapacity,
+            			history:    func(e *Engine) {},
+            			rule:       "CONSENSUS",
+            			inputs:     []Trit{TRUE, FALSE},
+            			want:       UNKNOWN,
+            			wantReason: "evaluated 2 inputs",
+            		},
+      		{
+            			name:     "prior outweighs",
+            			capacity: DefaultHistoryCapacity,
+            			history: func(e *Engine) {
+                    				e.Evaluate("CONSENSUS", TRUE, TRUE, FALSE)
+                    			},
+            			rule:       "CONSENSUS",
+            			inputs:     []Trit{TRUE, FALSE},
+            			want:       TRUE,
+            			wantReason: "with prior TRUE (weight 3)",
+            		},
+      		{
+            			name:     "newest prior in wrapped ring",
+            			capacity: 3,
+            			history: func(e *Engine) {
+                    				e.Evaluate("CONSENSUS", FALSE)
+                    				e.Evaluate("CONSENSUS", TRUE)
+                    				e.Evaluate("AND", TRUE)
+                    				e.Evaluate("AND", TRUE)
+                    			},
+            			rule:       "CONSENSUS",
+            			inputs:     []Trit{FALSE},
+            			want:       TRUE,
+            			wantReason: "with prior TRUE",
+            		},
+      		{
+            			name:     "prior evicted",
+            			capacity: 2,
+            			history: func(e *Engine) {
+                    				e.Evaluate("CONSENSUS", TRUE)
+                    				e.Evaluate("AND", TRUE)
+                    				e.Evaluate("AND", TRUE)
+                    			},
+            			rule:       "CONSENSUS",
+            			inputs:     []Trit{FALSE},
+            			want:       FALSE,
+            			wantReason: "evaluated 1 inputs",
+            		},
+      		{
+            			name:     "rule without weights",
+            			capacity: DefaultHistoryCapacity,
+            			history: func(e *Engine) {
+                    				e.Evaluate("AND", TRUE)
+                    			},
+            			rule:       "AND",
+            			inputs:     []Trit{TRUE},
+            			want:       UNKNOWN,
+            			wantReason: "does not accept weights",
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e, err := NewEngineWithCapacity(tt.capacity)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			tt.history(e)
+                    			r := e.EvaluateWithPrior(tt.rule, 3, tt.inputs...)
+                    			if r.Value != tt.want || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateWithPrior = %v %q, want %v containing %q", r.Value, r.Reason, tt.want, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestLastDecisionNoCopy(t *testing.T) {
+  	e, err := NewEngineWithCapacity(64)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Evaluate("CONSENSUS", TRUE)
+  	for i := 0; i < 100; i++ {
+      		e.Evaluate("AND", TRUE)
+      	}
+  	e.Evaluate("CONSENSUS", FALSE)
+  	e.Evaluate("AND", TRUE)
+
+  	allocs := testing.AllocsPerRun(100, func() {
+            		if d, ok := e.lastDecisionLocked("CONSENSUS"); !ok || d.Value != FALSE {
+                    			t.Fatalf("lastDecisionLocked = %v, %v, want FALSE", d.Value, ok)
+                    		}
+            	})
+  	if allocs != 0 {
+      		t.Errorf("lastDecisionLocked allocates %v times per call on a wrapped ring", allocs)
+      	}
+  }
@@ -0,0 +1,17 @@
+package ternary
+
+import "sort"
+
+// EvaluateByPriority tries ruleNames from highest to lowest Weight and
+// records the first definite result, falling back like EvaluateWithFallback.
+// Rules of equal weight keep their listed order.
+func (e *Engine) EvaluateByPriority(ruleNames []string, inputs ...Trit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	ordered := append([]string(nil), ruleNames...)
+  	sort.SliceStable(ordered, func(i, j int) bool {
+            		return e.rules[ordered[i]].Weight > e.rules[ordered[j]].Weight
+            	})
+  	return e.firstDefiniteLocked(ordered, inputs)
+  }
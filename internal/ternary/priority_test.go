@@ -0,0 +1,35 @@
+package ternary
+
+import "testing"
+
+func TestEvaluateByPriority(t *testing.T) {
+  	rule := func(name string, weight float64, v Trit) TernaryRule {
+      		return TernaryRule{Name: name, Weight: weight, Evaluate: func(...Trit) Trit { return v }}
+      	}
+  	tests := []struct {
+      		name     string
+      		order    []string
+      		want     Trit
+      		wantRule string
+      	}{
+      		{name: "heaviest unknown falls back", order: []string{"LO", "HI"}, want: FALSE, wantRule: "LO"},
+      		{name: "by weight not listed order", order: []string{"LO", "MID", "HI"}, want: TRUE, wantRule: "MID"},
+      		{name: "equal weights keep listed order", order: []string{"LO2", "LO"}, want: TRUE, wantRule: "LO2"},
+      		{name: "equal weights keep listed order reversed", order: []string{"LO", "LO2"}, want: FALSE, wantRule: "LO"},
+      		{name: "missing rules skipped", order: []string{"missing", "LO"}, want: FALSE, wantRule: "LO"},
+      		{name: "none definite", order: []string{"HI"}, want: UNKNOWN, wantRule: "HI"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("HI", rule("HI", 0.9, UNKNOWN))
+                    			e.AddRule("MID", rule("MID", 0.5, TRUE))
+                    			e.AddRule("LO", rule("LO", 0.2, FALSE))
+                    			e.AddRule("LO2", rule("LO2", 0.2, TRUE))
+                    			r := e.EvaluateByPriority(tt.order)
+                    			if r.Value != tt.want || r.Rule != tt.wantRule {
+                              				t.Errorf("EvaluateByPriority(%v) = %v from %q, want %v from %q", tt.order, r.Value, r.Rule, tt.want, tt.wantRule)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,35 @@
+package ternary
+
+import "sort"
+
+// ProximityScore rates how close candidate is to target: 1.0 for an exact
+// match, 0.5 when one of them is UNKNOWN, and 0.0 for opposite definite
+// values
+func ProximityScore(candidate, target Trit) float64 {
+  	switch d := candidate - target; {
+      	case d == 0:
+      		return 1.0
+      	case d == 1 || d == -1:
+      		return 0.5
+      	default:
+      		return 0.0
+      	}
+  }
+
+// ProximityRank is one input ranked by EvaluateProximity
+type ProximityRank struct {
+  	Index int     `json:"index"` // position among the inputs
+  	Value Trit    `json:"value"`
+  	Score float64 `json:"score"`
+  }
+
+// EvaluateProximity ranks inputs by ProximityScore against target, closest
+// first; inputs with equal scores keep their original order
+func (e *Engine) EvaluateProximity(target Trit, inputs ...Trit) []ProximityRank {
+  	ranks := make([]ProximityRank, len(inputs))
+  	for i, inp := range inputs {
+      		ranks[i] = ProximityRank{Index: i, Value: inp, Score: ProximityScore(inp, target)}
+      	}
+  	sort.SliceStable(ranks, func(i, j int) bool { return ranks[i].Score > ranks[j].Score })
+  	return ranks
+  }
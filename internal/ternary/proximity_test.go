@@ -0,0 +1,61 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  )
+
+func TestProximityScore(t *testing.T) {
+  	vals := []Trit{FALSE, UNKNOWN, TRUE}
+  	// rows candidate, columns target
+  	want := [3][3]float64{
+      		{1, 0.5, 0},
+      		{0.5, 1, 0.5},
+      		{0, 0.5, 1},
+      	}
+  	for i, c := range vals {
+      		for j, target := range vals {
+            			if got := ProximityScore(c, target); got != want[i][j] {
+                    				t.Errorf("ProximityScore(%v, %v) = %v, want %v", c, target, got, want[i][j])
+                    			}
+            		}
+      	}
+  }
+
+func TestEvaluateProximity(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		target Trit
+      		inputs []Trit
+      		want   []ProximityRank
+      	}{
+      		{
+            			name:   "closest first, stable",
+            			target: TRUE,
+            			inputs: []Trit{FALSE, UNKNOWN, TRUE, TRUE},
+            			want: []ProximityRank{
+                    				{Index: 2, Value: TRUE, Score: 1},
+                    				{Index: 3, Value: TRUE, Score: 1},
+                    				{Index: 1, Value: UNKNOWN, Score: 0.5},
+                    				{Index: 0, Value: FALSE, Score: 0},
+                    			},
+            		},
+      		{
+            			name:   "unknown target",
+            			target: UNKNOWN,
+            			inputs: []Trit{TRUE, UNKNOWN},
+            			want: []ProximityRank{
+                    				{Index: 1, Value: UNKNOWN, Score: 1},
+                    				{Index: 0, Value: TRUE, Score: 0.5},
+                    			},
+            		},
+      		{name: "no inputs", target: TRUE, want: []ProximityRank{}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := NewEngine().EvaluateProximity(tt.target, tt.inputs...); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("EvaluateProximity = %v, want %v", got, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,56 @@
+package ternary
+
+import "fmt"
+
+// Quantize maps a value on a [0,1] scale to a trit around the midpoint 0.5.
+// Values strictly within unknownBand/2 of 0.5 are UNKNOWN; otherwise values
+// at or above 0.5 are TRUE and values below are FALSE, so a zero band never
+// yields UNKNOWN. Quantize returns an error if unknownBand is outside [0, 1].
+func Quantize(value float64, unknownBand float64) (Trit, error) {
+  	if !(unknownBand >= 0 && unknownBand <= 1) {
+      		return UNKNOWN, fmt.Errorf("ternary: Quantize band %v outside [0, 1]", unknownBand)
+      	}
+
+  	half := unknownBand / 2
+  	switch {
+      	case value >= 0.5+half:
+      		return TRUE, nil
+      	case value <= 0.5-half:
+      		return FALSE, nil
+      	default:
+      		return UNKNOWN, nil
+      	}
+  }
+
+// QuantizeRange maps a confidence in [0, 1] to a trit: FALSE below
+// lowThreshold, TRUE above highThreshold and UNKNOWN from lowThreshold to
+// highThreshold inclusive. It is the inverse of Trit.Confidence whenever
+// lowThreshold < 0.5 < highThreshold, since Confidence maps FALSE, UNKNOWN
+// and TRUE to 0, 0.5 and 1. QuantizeRange returns an error unless
+// 0 <= lowThreshold <= highThreshold <= 1.
+//
+// It is not named Quantize, as that name already belongs to the
+// single-band quantizer above.
+func QuantizeRange(confidence, lowThreshold, highThreshold float64) (Trit, error) {
+  	if !(lowThreshold >= 0 && lowThreshold <= highThreshold && highThreshold <= 1) {
+      		return UNKNOWN, fmt.Errorf("ternary: QuantizeRange thresholds %v, %v not ordered within [0, 1]", lowThreshold, highThreshold)
+      	}
+  	return quantizeRange(confidence, lowThreshold, highThreshold), nil
+  }
+
+// QuantizeDefault is QuantizeRange with thresholds 0.33 and 0.67
+func QuantizeDefault(confidence float64) Trit {
+  	return quantizeRange(confidence, 0.33, 0.67)
+  }
+
+// quantizeRange is QuantizeRange without the threshold check
+func quantizeRange(confidence, lowThreshold, highThreshold float64) Trit {
+  	switch {
+      	case confidence < lowThreshold:
+      		return FALSE
+      	case confidence > highThreshold:
+      		return TRUE
+      	default:
+      		return UNKNOWN
+      	}
+  }
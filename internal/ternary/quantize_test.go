@@ -0,0 +1,143 @@
+package ternary
+
+import (
+  	"math"
+  	"strings"
+  	"testing"
+  )
+
+func TestQuantize(t *testing.T) {
+  	below := func(x float64) float64 { return math.Nextafter(x, 0) }
+  	above := func(x float64) float64 { return math.Nextafter(x, 1) }
+  	tests := []struct {
+      		name        string
+      		value, band float64
+      		want        Trit
+      	}{
+      		{name: "zero band midpoint", value: 0.5, band: 0, want: TRUE},
+      		{name: "zero band just below midpoint", value: below(0.5), band: 0, want: FALSE},
+      		{name: "band midpoint", value: 0.5, band: 0.2, want: UNKNOWN},
+      		{name: "upper edge", value: 0.6, band: 0.2, want: TRUE},
+      		{name: "inside upper edge", value: below(0.6), band: 0.2, want: UNKNOWN},
+      		{name: "inside lower edge", value: above(0.4), band: 0.2, want: UNKNOWN},
+      		{name: "lower edge", value: 0.4, band: 0.2, want: FALSE},
+      		{name: "full band", value: 0.5, band: 1, want: UNKNOWN},
+      		{name: "full band top", value: 1, band: 1, want: TRUE},
+      		{name: "full band bottom", value: 0, band: 1, want: FALSE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := Quantize(tt.value, tt.band)
+                    			if err != nil || got != tt.want {
+                              				t.Errorf("Quantize(%v, %v) = %v, %v, want %v", tt.value, tt.band, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestQuantizeZeroBand(t *testing.T) {
+  	for i := 0; i <= 1000; i++ {
+      		v := float64(i) / 1000
+      		if got, _ := Quantize(v, 0); got == UNKNOWN {
+            			t.Fatalf("Quantize(%v, 0) = UNKNOWN, want a definite value", v)
+            		}
+      	}
+  }
+
+func TestQuantizeBandErrors(t *testing.T) {
+  	for _, band := range []float64{-0.1, math.Nextafter(1, 2), 1.1, math.NaN(), math.Inf(1)} {
+      		got, err := Quantize(0.5, band)
+      		if err == nil || !strings.Contains(err.Error(), "outside [0, 1]") || got != UNKNOWN {
+            			t.Errorf("Quantize(0.5, %v) = %v, %v, want UNKNOWN and a band error", band, got, err)
+            		}
+      	}
+  }
+
+func TestQuantizeRange(t *testing.T) {
+  	below := func(x float64) float64 { return math.Nextafter(x, 0) }
+  	above := func(x float64) float64 { return math.Nextafter(x, 1) }
+  	tests := []struct {
+      		name                  string
+      		confidence, low, high float64
+      		want                  Trit
+      	}{
+      		{name: "just below low", confidence: below(0.2), low: 0.2, high: 0.9, want: FALSE},
+      		{name: "at low", confidence: 0.2, low: 0.2, high: 0.9, want: UNKNOWN},
+      		{name: "just above low", confidence: above(0.2), low: 0.2, high: 0.9, want: UNKNOWN},
+      		{name: "just below high", confidence: below(0.9), low: 0.2, high: 0.9, want: UNKNOWN},
+      		{name: "at high", confidence: 0.9, low: 0.2, high: 0.9, want: UNKNOWN},
+      		{name: "just above high", confidence: above(0.9), low: 0.2, high: 0.9, want: TRUE},
+      		{name: "equal thresholds below", confidence: below(0.5), low: 0.5, high: 0.5, want: FALSE},
+      		{name: "equal thresholds at", confidence: 0.5, low: 0.5, high: 0.5, want: UNKNOWN},
+      		{name: "equal thresholds above", confidence: above(0.5), low: 0.5, high: 0.5, want: TRUE},
+      		{name: "zero low keeps 0 UNKNOWN", confidence: 0, low: 0, high: 0.5, want: UNKNOWN},
+      		{name: "unit high keeps 1 UNKNOWN", confidence: 1, low: 0.5, high: 1, want: UNKNOWN},
+      		{name: "whole range UNKNOWN", confidence: 0.99, low: 0, high: 1, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := QuantizeRange(tt.confidence, tt.low, tt.high)
+                    			if err != nil || got != tt.want {
+                              				t.Errorf("QuantizeRange(%v, %v, %v) = %v, %v, want %v", tt.confidence, tt.low, tt.high, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestQuantizeRangeErrors(t *testing.T) {
+  	tests := []struct {
+      		low, high float64
+      	}{
+      		{low: 0.6, high: 0.4},
+      		{low: -0.1, high: 0.5},
+      		{low: 0.5, high: 1.1},
+      		{low: math.NaN(), high: 1},
+      		{low: 0, high: math.NaN()},
+      	}
+  	for _, tt := range tests {
+      		got, err := QuantizeRange(0.5, tt.low, tt.high)
+      		if err == nil || !strings.Contains(err.Error(), "not ordered within [0, 1]") || got != UNKNOWN {
+            			t.Errorf("QuantizeRange(0.5, %v, %v) = %v, %v, want UNKNOWN and a threshold error", tt.low, tt.high, got, err)
+            		}
+      	}
+  }
+
+func TestQuantizeRangeInvertsConfidence(t *testing.T) {
+  	thresholds := []struct{ low, high float64 }{
+      		{0.33, 0.67}, {0.1, 0.9}, {math.Nextafter(0.5, 0), math.Nextafter(0.5, 1)},
+      	}
+  	for _, th := range thresholds {
+      		for _, v := range []Trit{FALSE, UNKNOWN, TRUE} {
+            			if got, _ := QuantizeRange(v.Confidence(), th.low, th.high); got != v {
+                    				t.Errorf("QuantizeRange(%v.Confidence(), %v, %v) = %v, want %v", v, th.low, th.high, got, v)
+                    			}
+            		}
+      	}
+  }
+
+func TestQuantizeDefault(t *testing.T) {
+  	tests := []struct {
+      		confidence float64
+      		want       Trit
+      	}{
+      		{0, FALSE},
+      		{math.Nextafter(0.33, 0), FALSE},
+      		{0.33, UNKNOWN},
+      		{math.Nextafter(0.33, 1), UNKNOWN},
+      		{0.5, UNKNOWN},
+      		{math.Nextafter(0.67, 0), UNKNOWN},
+      		{0.67, UNKNOWN},
+      		{math.Nextafter(0.67, 1), TRUE},
+      		{1, TRUE},
+      	}
+  	for _, tt := range tests {
+      		if got := QuantizeDefault(tt.confidence); got != tt.want {
+            			t.Errorf("QuantizeDefault(%v) = %v, want %v", tt.confidence, got, tt.want)
+            		}
+      	}
+  	for _, v := range []Trit{FALSE, UNKNOWN, TRUE} {
+      		if got := QuantizeDefault(v.Confidence()); got != v {
+            			t.Errorf("QuantizeDefault(%v.Confidence()) = %v, want %v", v, got, v)
+            		}
+      	}
+  }
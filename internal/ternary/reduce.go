@@ -0,0 +1,11 @@
+package ternary
+
+// MapReduce maps each item to a trit with pred and evaluates ruleName over
+// the resulting trits, in item order
+func MapReduce[T any](e *Engine, items []T, pred func(T) Trit, ruleName string) TernaryResult {
+  	inputs := make([]Trit, len(items))
+  	for i, item := range items {
+      		inputs[i] = pred(item)
+      	}
+  	return e.Evaluate(ruleName, inputs...)
+  }
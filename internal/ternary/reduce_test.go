@@ -0,0 +1,33 @@
+package ternary
+
+import "testing"
+
+func TestMapReduce(t *testing.T) {
+  	even := func(n int) Trit {
+      		if n%2 == 0 {
+            			return TRUE
+            		}
+      		return FALSE
+      	}
+  	tests := []struct {
+      		name  string
+      		items []int
+      		rule  string
+      		want  Trit
+      		count int
+      	}{
+      		{name: "majority even", items: []int{2, 4, 5}, rule: "CONSENSUS", want: TRUE, count: 3},
+      		{name: "majority odd", items: []int{1, 3, 4}, rule: "CONSENSUS", want: FALSE, count: 3},
+      		{name: "all even", items: []int{2, 4, 6}, rule: "AND", want: TRUE, count: 3},
+      		{name: "one odd", items: []int{2, 4, 7}, rule: "AND", want: FALSE, count: 3},
+      		{name: "no items", rule: "OR", want: FALSE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := MapReduce(NewEngine(), tt.items, even, tt.rule)
+                    			if r.Value != tt.want || r.InputCount != tt.count {
+                              				t.Errorf("MapReduce = %v over %d inputs, want %v over %d", r.Value, r.InputCount, tt.want, tt.count)
+                              			}
+                    		})
+      	}
+  }
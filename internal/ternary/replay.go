@@ -0,0 +1,168 @@
+package ternary
+
+import (
+  	"bufio"
+  	"context"
+  	"encoding/json"
+  	"fmt"
+  	"io"
+  	"time"
+  )
+
+// ReplayJSONL re-evaluates a JSON Lines log of TernaryResults under the
+// current rules. Each line must carry its rule and captured inputs, so the
+// log has to be written with input capture enabled. It returns the replayed
+// results, without recording them, and an error for every line that could
+// not be replayed or whose replayed value differs from the logged one.
+//
+// The whole log is read and decoded before the engine lock is taken, so a
+// slow reader does not hold up evaluations.
+func (e *Engine) ReplayJSONL(r io.Reader) ([]TernaryResult, []error) {
+  	// replayLine is one non-blank line of the log, decoded or failed
+  	type replayLine struct {
+      		n      int
+      		logged TernaryResult
+      		err    error
+      	}
+
+  	var lines []replayLine
+  	scanner := bufio.NewScanner(r)
+  	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+  	n := 0
+  	for scanner.Scan() {
+      		n++
+      		if len(scanner.Bytes()) == 0 {
+            			continue
+            		}
+      		l := replayLine{n: n}
+      		if err := json.Unmarshal(scanner.Bytes(), &l.logged); err != nil {
+            			l.err = fmt.Errorf("line %d: %w", n, err)
+            		} else if l.logged.Rule == "" {
+            			l.err = fmt.Errorf("line %d: no rule recorded", n)
+            		} else if len(l.logged.Inputs) != l.logged.InputCount {
+            			l.err = fmt.Errorf("line %d: inputs not captured", n)
+            		}
+      		lines = append(lines, l)
+      	}
+
+  	var (
+      		results []TernaryResult
+      		errs    []error
+      	)
+
+  	e.mu.RLock()
+  	for _, l := range lines {
+      		if l.err != nil {
+            			errs = append(errs, l.err)
+            			continue
+            		}
+      		replayed, ok := e.previewLocked(l.logged.Rule, l.logged.Inputs)
+      		if !ok {
+            			errs = append(errs, fmt.Errorf("line %d: %s", l.n, replayed.Reason))
+            			continue
+            		}
+      		results = append(results, replayed)
+      		if replayed.Value != l.logged.Value {
+            			errs = append(errs, fmt.Errorf("line %d: Rule[%s] replayed %s, logged %s",
+                              				l.n, l.logged.Rule, tritName(replayed.Value), tritName(l.logged.Value)))
+            		}
+      	}
+  	e.mu.RUnlock()
+
+  	if err := scanner.Err(); err != nil {
+      		errs = append(errs, err)
+      	}
+  	return results, errs
+  }
+
+// ReplayDiff compares a historical decision with its re-evaluation under
+// the current rules
+type ReplayDiff struct {
+  	ID      string `json:"id"`
+  	Rule    string `json:"rule"`
+  	Old     Trit   `json:"old"`
+  	New     Trit   `json:"new"`
+  	Changed bool   `json:"changed"`
+  	Failure string `json:"failure,omitempty"` // why the decision could not be replayed
+  }
+
+// Replay re-evaluates each decision of history under the current rules,
+// for regression analysis of rule changes. The inputs of history[i] are
+// capturedInputs[i] when capturedInputs has such an entry that is not nil,
+// and otherwise the decision's own Inputs, so history recorded with input
+// capture (CaptureInputs or SetCaptureInputs) can be replayed with a nil
+// capturedInputs. Nothing is recorded.
+//
+// A decision without a rule or inputs, or whose rule is now unknown,
+// disabled or rejects the inputs, gets New UNKNOWN, Changed false and the
+// cause in Failure.
+func (e *Engine) Replay(history []TernaryResult, capturedInputs [][]Trit) []ReplayDiff {
+  	diffs := make([]ReplayDiff, len(history))
+
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	for i, old := range history {
+      		d := ReplayDiff{ID: old.ID, Rule: old.Rule, Old: old.Value, New: UNKNOWN}
+      		inputs := old.Inputs
+      		if i < len(capturedInputs) && capturedInputs[i] != nil {
+            			inputs = capturedInputs[i]
+            		}
+      		switch {
+            		case old.Rule == "":
+            			d.Failure = "no rule recorded"
+            		case len(inputs) != old.InputCount:
+            			d.Failure = "inputs not captured"
+            		default:
+            			replayed, ok := e.previewLocked(old.Rule, inputs)
+            			if !ok {
+                    				d.Failure = replayed.Reason
+                    				break
+                    			}
+            			d.New = replayed.Value
+            			d.Changed = d.New != d.Old
+            		}
+      		diffs[i] = d
+      	}
+  	return diffs
+  }
+
+// ReplayTimed emits results on the returned channel spaced by their
+// original Timestamp gaps divided by speed, so speed 2 replays twice as
+// fast. The first result is sent at once; results out of timestamp order
+// are sent without delay. The channel is closed after the last result or
+// when ctx is done. It returns an error if speed is not positive.
+func (e *Engine) ReplayTimed(ctx context.Context, results []TernaryResult, speed float64) (<-chan TernaryResult, error) {
+  	if !(speed > 0) {
+      		return nil, fmt.Errorf("ternary: replay speed %v must be positive", speed)
+      	}
+  	results = append([]TernaryResult(nil), results...)
+
+  	out := make(chan TernaryResult)
+  	go func() {
+      		defer close(out)
+      		timer := time.NewTimer(0)
+      		defer timer.Stop()
+
+      		for i, r := range results {
+            			if i > 0 {
+                    				gap := r.Timestamp.Sub(results[i-1].Timestamp)
+                    				if gap < 0 {
+                              					gap = 0
+                              				}
+                    				timer.Reset(time.Duration(float64(gap) / speed))
+                    				select {
+                              				case <-ctx.Done():
+                              					return
+                              				case <-timer.C:
+                              				}
+                    			}
+            			select {
+                    			case <-ctx.Done():
+                    				return
+                    			case out <- r:
+                    			}
+            		}
+      	}()
+  	return out, nil
+  }
@@ -0,0 +1,341 @@
+package ternary
+
+import (
+  	"context"
+  	"encoding/json"
+  	"fmt"
+  	"strings"
+  	"testing"
+  	"time"
+  )
+
+func TestReplayTimed(t *testing.T) {
+  	const gap = 80 * time.Millisecond
+  	base := time.Now()
+  	tests := []struct {
+      		name     string
+      		offsets  []time.Duration // of each result from base
+      		speed    float64
+      		wantGaps []time.Duration // between successive emissions
+      	}{
+      		{name: "real time", offsets: []time.Duration{0, gap, 2 * gap}, speed: 1, wantGaps: []time.Duration{gap, gap}},
+      		{name: "double speed halves the interval", offsets: []time.Duration{0, gap, 2 * gap}, speed: 2, wantGaps: []time.Duration{gap / 2, gap / 2}},
+      		{name: "half speed doubles the interval", offsets: []time.Duration{0, gap / 2}, speed: 0.5, wantGaps: []time.Duration{gap}},
+      		{name: "uneven gaps", offsets: []time.Duration{0, gap, 3 * gap}, speed: 4, wantGaps: []time.Duration{gap / 4, gap / 2}},
+      		{name: "out of order sent at once", offsets: []time.Duration{gap, 0, gap}, speed: 1, wantGaps: []time.Duration{0, gap}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			rs := make([]TernaryResult, len(tt.offsets))
+                    			for i, off := range tt.offsets {
+                              				rs[i] = TernaryResult{ID: fmt.Sprint(i), Timestamp: base.Add(off)}
+                              			}
+                    			ch, err := NewEngine().ReplayTimed(context.Background(), rs, tt.speed)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			var got []time.Duration
+                    			last, i := time.Time{}, 0
+                    			for r := range ch {
+                              				now := time.Now()
+                              				if r.ID != rs[i].ID {
+                                          					t.Errorf("result %d has ID %q, want %q", i, r.ID, rs[i].ID)
+                                          				}
+                              				if i > 0 {
+                                          					got = append(got, now.Sub(last))
+                                          				}
+                              				last = now
+                              				i++
+                              			}
+                    			if len(got) != len(tt.wantGaps) {
+                              				t.Fatalf("got %d gaps, want %d", len(got), len(tt.wantGaps))
+                              			}
+                    			for i, want := range tt.wantGaps {
+                              				if got[i] < want-5*time.Millisecond || got[i] > want+30*time.Millisecond {
+                                          					t.Errorf("gap %d = %v, want about %v", i, got[i], want)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestReplayTimedCancel(t *testing.T) {
+  	base := time.Now()
+  	rs := []TernaryResult{{Timestamp: base}, {Timestamp: base.Add(time.Hour)}, {Timestamp: base.Add(2 * time.Hour)}}
+  	tests := []struct {
+      		name     string
+      		received int // results read before cancelling
+      	}{
+      		{name: "while waiting", received: 1},
+      		{name: "before the first", received: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			ctx, cancel := context.WithCancel(context.Background())
+                    			ch, err := NewEngine().ReplayTimed(ctx, rs, 1)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			for i := 0; i < tt.received; i++ {
+                              				<-ch
+                              			}
+                    			cancel()
+                    			select {
+                              			case _, ok := <-ch:
+                              				// the first result may race the cancellation; nothing follows it
+                              				if ok {
+                                          					_, ok = <-ch
+                                          				}
+                              				if ok {
+                                          					t.Error("channel still open after cancel")
+                                          				}
+                              			case <-time.After(time.Second):
+                              				t.Error("stream not stopped by cancel")
+                              			}
+                    		})
+      	}
+  }
+
+func TestReplayTimedSpeed(t *testing.T) {
+  	e := NewEngine()
+  	for _, speed := range []float64{0, -1} {
+      		if ch, err := e.ReplayTimed(context.Background(), nil, speed); err == nil || ch != nil {
+            			t.Errorf("ReplayTimed speed %v = %v, %v, want error", speed, ch, err)
+            		}
+      	}
+  }
+
+func TestReplayJSONL(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	and := e.Evaluate("AND", TRUE, FALSE)
+  	or := e.Evaluate("OR", TRUE, FALSE)
+  	line := func(r TernaryResult) string {
+      		data, err := json.Marshal(r)
+      		if err != nil {
+            			t.Fatal(err)
+            		}
+      		return string(data)
+      	}
+  	tampered := or
+  	tampered.Value = FALSE
+  	uncaptured := NewEngine().Evaluate("AND", TRUE)
+  	missing := and
+  	missing.Rule = "nope"
+  	noRule := and
+  	noRule.Rule = ""
+
+  	tests := []struct {
+      		name     string
+      		log      []string
+      		replayed int
+      		wantErrs []string
+      	}{
+      		{name: "clean", log: []string{line(and), line(or)}, replayed: 2},
+      		{name: "blank lines skipped", log: []string{"", line(and), ""}, replayed: 1},
+      		{name: "changed value", log: []string{line(and), line(tampered)}, replayed: 2, wantErrs: []string{"line 2: Rule[OR] replayed TRUE, logged FALSE"}},
+      		{name: "bad json", log: []string{"{", line(or)}, replayed: 1, wantErrs: []string{"line 1: "}},
+      		{name: "no rule", log: []string{line(noRule)}, wantErrs: []string{"line 1: no rule recorded"}},
+      		{name: "inputs not captured", log: []string{line(uncaptured)}, wantErrs: []string{"line 1: inputs not captured"}},
+      		{name: "unknown rule", log: []string{line(missing)}, wantErrs: []string{"line 1: Rule 'nope' not found"}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			before := e.Stats()["total_decisions"]
+                    			results, errs := e.ReplayJSONL(strings.NewReader(strings.Join(tt.log, "\n")))
+                    			if len(results) != tt.replayed {
+                              				t.Errorf("replayed %d results, want %d", len(results), tt.replayed)
+                              			}
+                    			if len(errs) != len(tt.wantErrs) {
+                              				t.Fatalf("errors = %v, want %d", errs, len(tt.wantErrs))
+                              			}
+                    			for i, want := range tt.wantErrs {
+                              				if !strings.Contains(errs[i].Error(), want) {
+                                          					t.Errorf("error %d = %q, want %q", i, errs[i], want)
+                                          				}
+                              			}
+                    			if after := e.Stats()["total_decisions"]; after != before {
+                              				t.Errorf("replay recorded decisions: %v -> %v", before, after)
+                              			}
+                    		})
+      	}
+  }
+
+// blockingReader delivers data only after release is closed, signalling
+// reading when its first Read starts
+type blockingReader struct {
+  	data    *strings.Reader
+  	reading chan struct{}
+  	release chan struct{}
+  }
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+  	select {
+      	case <-r.reading:
+      	default:
+      		close(r.reading)
+      	}
+  	<-r.release
+  	return r.data.Read(p)
+  }
+
+func TestReplayJSONLSlowReader(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	logged, err := json.Marshal(e.Evaluate("AND", TRUE, FALSE))
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	r := &blockingReader{data: strings.NewReader(string(logged)), reading: make(chan struct{}), release: make(chan struct{})}
+  	done := make(chan []TernaryResult)
+  	go func() {
+      		results, _ := e.ReplayJSONL(r)
+      		done <- results
+      	}()
+
+  	<-r.reading
+  	evaluated := make(chan struct{})
+  	go func() {
+      		e.Evaluate("OR", TRUE)
+      		close(evaluated)
+      	}()
+  	select {
+      	case <-evaluated:
+      	case <-time.After(time.Second):
+      		t.Error("Evaluate blocked behind ReplayJSONL reading its input")
+      	}
+  	close(r.release)
+  	if results := <-done; len(results) != 1 || results[0].Value != FALSE {
+      		t.Errorf("ReplayJSONL = %v, want one FALSE result", results)
+      	}
+  }
+
+func TestReplay(t *testing.T) {
+  	e := NewEngine()
+  	a := e.Evaluate("CONSENSUS", TRUE, TRUE, FALSE)
+  	b := e.Evaluate("AND", TRUE, UNKNOWN)
+  	e.SetCaptureInputs(true)
+  	c := e.Evaluate("OR", UNKNOWN, UNKNOWN)
+  	d := e.Evaluate("XOR", TRUE)
+  	f := e.Evaluate("AND", TRUE, TRUE)
+  	e.SetCaptureInputs(false)
+
+  	e.AddRule("CONSENSUS", TernaryRule{Name: "CONSENSUS", Weight: 1, Evaluate: func(in ...Trit) Trit { return FALSE }})
+  	e.SetLogicSystem(LogicLukasiewicz)
+  	e.DisableRule("XOR")
+  	recorded := len(e.GetDecisions(DecisionFilter{}))
+
+  	tests := []struct {
+      		name     string
+      		old      TernaryResult
+      		captured []Trit
+      		want     ReplayDiff
+      	}{
+      		{
+            			name:     "rule replaced",
+            			old:      a,
+            			captured: []Trit{TRUE, TRUE, FALSE},
+            			want:     ReplayDiff{ID: a.ID, Rule: "CONSENSUS", Old: TRUE, New: FALSE, Changed: true},
+            		},
+      		{
+            			name: "inputs not captured",
+            			old:  b,
+            			want: ReplayDiff{ID: b.ID, Rule: "AND", Old: UNKNOWN, New: UNKNOWN, Failure: "inputs not captured"},
+            		},
+      		{
+            			name: "logic system changed",
+            			old:  c,
+            			want: ReplayDiff{ID: c.ID, Rule: "OR", Old: UNKNOWN, New: TRUE, Changed: true},
+            		},
+      		{
+            			name: "rule disabled",
+            			old:  d,
+            			want: ReplayDiff{ID: d.ID, Rule: "XOR", Old: TRUE, New: UNKNOWN, Failure: "Rule 'XOR' disabled"},
+            		},
+      		{
+            			name: "unchanged",
+            			old:  f,
+            			want: ReplayDiff{ID: f.ID, Rule: "AND", Old: TRUE, New: TRUE},
+            		},
+      		{
+            			name:     "captured overrides recorded inputs",
+            			old:      f,
+            			captured: []Trit{TRUE, FALSE},
+            			want:     ReplayDiff{ID: f.ID, Rule: "AND", Old: TRUE, New: FALSE, Changed: true},
+            		},
+      		{
+            			name: "no rule",
+            			old:  TernaryResult{Value: TRUE},
+            			want: ReplayDiff{Old: TRUE, New: UNKNOWN, Failure: "no rule recorded"},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			diffs := e.Replay([]TernaryResult{tt.old}, [][]Trit{tt.captured})
+                    			if len(diffs) != 1 || diffs[0] != tt.want {
+                              				t.Errorf("Replay = %+v, want [%+v]", diffs, tt.want)
+                              			}
+                    		})
+      	}
+  	if got := len(e.GetDecisions(DecisionFilter{})); got != recorded {
+      		t.Errorf("Replay recorded decisions: %d, want %d", got, recorded)
+      	}
+  }
+
+func TestReplayCapturedInputsPerEntry(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	history := []TernaryResult{
+      		e.Evaluate("AND", TRUE, TRUE),
+      		e.Evaluate("OR", FALSE, FALSE),
+      		e.Evaluate("AND", TRUE, FALSE),
+      		e.Evaluate("ALL_KNOWN"),
+      	}
+  	tests := []struct {
+      		name     string
+      		captured [][]Trit
+      		want     []Trit
+      		failures []string
+      	}{
+      		{
+            			name: "recorded inputs",
+            			want: []Trit{TRUE, FALSE, FALSE, TRUE},
+            		},
+      		{
+            			name:     "nil entry falls back",
+            			captured: [][]Trit{{TRUE, FALSE}, nil},
+            			want:     []Trit{FALSE, FALSE, FALSE, TRUE},
+            		},
+      		{
+            			name:     "shorter list falls back",
+            			captured: [][]Trit{nil, {TRUE, FALSE}},
+            			want:     []Trit{TRUE, TRUE, FALSE, TRUE},
+            		},
+      		{
+            			name:     "wrong input count",
+            			captured: [][]Trit{nil, nil, {TRUE}},
+            			want:     []Trit{TRUE, FALSE, UNKNOWN, TRUE},
+            			failures: []string{"", "", "inputs not captured", ""},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			diffs := e.Replay(history, tt.captured)
+                    			if len(diffs) != len(history) {
+                              				t.Fatalf("Replay returned %d diffs, want %d", len(diffs), len(history))
+                              			}
+                    			for i, d := range diffs {
+                              				if d.New != tt.want[i] {
+                                          					t.Errorf("diff %d New = %v, want %v", i, d.New, tt.want[i])
+                                          				}
+                              				wantFailure := ""
+                              				if tt.failures != nil {
+                                          					wantFailure = tt.failures[i]
+                                          				}
+                              				if d.Failure != wantFailure {
+                                          					t.Errorf("diff %d Failure = %q, want %q", i, d.Failure, wantFailure)
+                                          				}
+                              				if d.Changed != (d.Failure == "" && d.New != history[i].Value) {
+                                          					t.Errorf("diff %d Changed = %v for %v -> %v", i, d.Changed, history[i].Value, d.New)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
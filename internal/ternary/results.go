@@ -0,0 +1,32 @@
+package ternary
+
+// NormalizeConfidences returns copies of results with Confidence min-max
+// scaled into [0, 1] across the set, so the most confident result maps to
+// 1.0 and the least to 0.0. Values are preserved. When all confidences are
+// equal every copy gets 1.0.
+func NormalizeConfidences(results []TernaryResult) []TernaryResult {
+  	out := make([]TernaryResult, len(results))
+  	copy(out, results)
+  	if len(out) == 0 {
+      		return out
+      	}
+
+  	lo, hi := out[0].Confidence, out[0].Confidence
+  	for _, r := range out[1:] {
+      		if r.Confidence < lo {
+            			lo = r.Confidence
+            		}
+      		if r.Confidence > hi {
+            			hi = r.Confidence
+            		}
+      	}
+
+  	for i := range out {
+      		if hi == lo {
+            			out[i].Confidence = 1.0
+            		} else {
+            			out[i].Confidence = (out[i].Confidence - lo) / (hi - lo)
+            		}
+      	}
+  	return out
+  }
@@ -0,0 +1,42 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  )
+
+func TestNormalizeConfidences(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		in   []float64
+      		want []float64
+      	}{
+      		{name: "empty", in: []float64{}, want: []float64{}},
+      		{name: "single", in: []float64{0.3}, want: []float64{1}},
+      		{name: "all equal", in: []float64{0.4, 0.4}, want: []float64{1, 1}},
+      		{name: "scaled", in: []float64{0.5, 2, 1.25}, want: []float64{0, 1, 0.5}},
+      		{name: "negative", in: []float64{-1, 0, 1}, want: []float64{0, 0.5, 1}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			in := make([]TernaryResult, len(tt.in))
+                    			for i, c := range tt.in {
+                              				in[i] = TernaryResult{Value: Trit(i%3 - 1), Confidence: c}
+                              			}
+                    			out := NormalizeConfidences(in)
+                    			got := make([]float64, len(out))
+                    			for i, r := range out {
+                              				got[i] = r.Confidence
+                              				if r.Value != in[i].Value {
+                                          					t.Errorf("result %d value changed to %v", i, r.Value)
+                                          				}
+                              				if in[i].Confidence != tt.in[i] {
+                                          					t.Errorf("input %d modified", i)
+                                          				}
+                              			}
+                    			if !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("NormalizeConfidences(%v) = %v, want %v", tt.in, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,166 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"sort"
+  	"strings"
+  )
+
+// RuleInfo describes a registered rule without its evaluation function
+type RuleInfo struct {
+  	Name    string   `json:"name"`
+  	Weight  float64  `json:"weight"`
+  	Enabled bool     `json:"enabled"`
+  	Tags    []string `json:"tags,omitempty"`
+  }
+
+// Rules returns the registered rules sorted by name
+func (e *Engine) Rules() []RuleInfo {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	infos := make([]RuleInfo, 0, len(e.rules))
+  	for name, rule := range e.rules {
+      		infos = append(infos, RuleInfo{
+                    			Name:    name,
+                    			Weight:  rule.Weight,
+                    			Enabled: !e.disabled[name],
+                    			Tags:    append([]string(nil), rule.Tags...),
+                    		})
+      	}
+  	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+  	return infos
+  }
+
+// RulesJSON returns Rules encoded as a JSON array
+func (e *Engine) RulesJSON() ([]byte, error) {
+  	return json.Marshal(e.Rules())
+  }
+
+// SetRuleEnabled enables or disables a registered rule and reports whether
+// the rule exists. A disabled rule stays registered but evaluates to UNKNOWN.
+// Enabling a rule disables the other members of its exclusive groups.
+func (e *Engine) SetRuleEnabled(name string, enabled bool) bool {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	return e.setRuleEnabledLocked(name, enabled)
+  }
+
+// setRuleEnabledLocked implements SetRuleEnabled. The caller must hold e.mu.
+func (e *Engine) setRuleEnabledLocked(name string, enabled bool) bool {
+  	if _, exists := e.rules[name]; !exists {
+      		return false
+      	}
+  	if !enabled {
+      		e.disabled[name] = true
+      		return true
+      	}
+
+  	delete(e.disabled, name)
+  	for _, members := range e.groups {
+      		if !containsString(members, name) {
+            			continue
+            		}
+      		for _, other := range members {
+            			if other != name {
+                    				e.disabled[other] = true
+                    			}
+            		}
+      	}
+  	return true
+  }
+
+// DisableRule keeps a rule registered but makes it evaluate to UNKNOWN with
+// a "disabled" reason, and reports whether the rule exists
+func (e *Engine) DisableRule(name string) bool {
+  	return e.SetRuleEnabled(name, false)
+  }
+
+// EnableRule re-enables a disabled rule and reports whether the rule
+// exists. Like SetRuleEnabled it disables the rest of its exclusive groups.
+func (e *Engine) EnableRule(name string) bool {
+  	return e.SetRuleEnabled(name, true)
+  }
+
+// RemoveRule unregisters a rule and reports whether it existed. Default
+// rules may be removed too; a later AddRule of the same name starts out
+// enabled. Exclusive groups keep listing the name.
+func (e *Engine) RemoveRule(name string) bool {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if _, exists := e.rules[name]; !exists {
+      		return false
+      	}
+  	delete(e.rules, name)
+  	delete(e.disabled, name)
+  	delete(e.deprecated, name)
+  	e.ruleIndex = nil
+  	return true
+  }
+
+// SetExclusiveGroup declares that at most one of ruleNames may be enabled at
+// a time, replacing any previous group of the same name. Within a group the
+// last rule enabled wins: enabling one disables all the others. When the
+// group is declared, the first listed rule that is currently enabled stays
+// enabled and the rest are disabled.
+func (e *Engine) SetExclusiveGroup(groupName string, ruleNames ...string) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.groups[groupName] = append([]string(nil), ruleNames...)
+  	for _, name := range ruleNames {
+      		if _, exists := e.rules[name]; exists && !e.disabled[name] {
+            			e.setRuleEnabledLocked(name, true)
+            			return
+            		}
+      	}
+  }
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+  	for _, v := range list {
+      		if v == s {
+            			return true
+            		}
+      	}
+  	return false
+  }
+
+// RulesWithPrefix returns the sorted names of registered rules starting with
+// prefix, e.g. "security/" for a namespace
+func (e *Engine) RulesWithPrefix(prefix string) []string {
+  	index := e.sortedRules()
+  	var matches []string
+  	for i := sort.SearchStrings(index, prefix); i < len(index); i++ {
+      		if !strings.HasPrefix(index[i], prefix) {
+            			break
+            		}
+      		matches = append(matches, index[i])
+      	}
+  	return matches
+  }
+
+// sortedRules returns the sorted rule names, rebuilding the index under the
+// write lock only when it is stale. The index is replaced, never modified,
+// so it can be read after the lock is released.
+func (e *Engine) sortedRules() []string {
+  	e.mu.RLock()
+  	index := e.ruleIndex
+  	e.mu.RUnlock()
+  	if index != nil {
+      		return index
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	if e.ruleIndex == nil {
+      		index := make([]string, 0, len(e.rules))
+      		for name := range e.rules {
+            			index = append(index, name)
+            		}
+      		sort.Strings(index)
+      		e.ruleIndex = index
+      	}
+  	return e.ruleIndex
+  }
@@ -0,0 +1,219 @@
+package ternary
+
+import (
+  	"fmt"
+  	"reflect"
+  	"strings"
+  	"sync"
+  	"testing"
+  )
+
+func TestExclusiveGroup(t *testing.T) {
+  	tests := []struct {
+      		name        string
+      		setup       func(e *Engine)
+      		wantEnabled map[string]bool
+      	}{
+      		{
+            			name:        "first enabled member stays enabled",
+            			setup:       func(e *Engine) { e.SetExclusiveGroup("g", "AND", "OR", "NOT") },
+            			wantEnabled: map[string]bool{"AND": true, "OR": false, "NOT": false},
+            		},
+      		{
+            			name: "disabled member skipped at declaration",
+            			setup: func(e *Engine) {
+                    				e.SetRuleEnabled("AND", false)
+                    				e.SetExclusiveGroup("g", "AND", "OR", "NOT")
+                    			},
+            			wantEnabled: map[string]bool{"AND": false, "OR": true, "NOT": false},
+            		},
+      		{
+            			name: "last enabled wins",
+            			setup: func(e *Engine) {
+                    				e.SetExclusiveGroup("g", "AND", "OR", "NOT")
+                    				e.SetRuleEnabled("NOT", true)
+                    			},
+            			wantEnabled: map[string]bool{"AND": false, "OR": false, "NOT": true},
+            		},
+      		{
+            			name: "redeclared group replaces the old one",
+            			setup: func(e *Engine) {
+                    				e.SetExclusiveGroup("g", "AND", "OR")
+                    				e.SetExclusiveGroup("g", "NOT", "IMPLIES")
+                    				e.SetRuleEnabled("OR", true)
+                    			},
+            			wantEnabled: map[string]bool{"AND": true, "OR": true, "NOT": true, "IMPLIES": false},
+            		},
+      		{
+            			name: "rules outside the group untouched",
+            			setup: func(e *Engine) {
+                    				e.SetExclusiveGroup("g", "AND", "OR")
+                    				e.SetRuleEnabled("OR", true)
+                    			},
+            			wantEnabled: map[string]bool{"AND": false, "OR": true, "XOR": true},
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			tt.setup(e)
+                    			for rule, enabled := range tt.wantEnabled {
+                              				r := e.Evaluate(rule, TRUE)
+                              				if disabled := strings.Contains(r.Reason, "disabled"); disabled == enabled {
+                                          					t.Errorf("%s enabled = %v, want %v (reason %q)", rule, !disabled, enabled, r.Reason)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestSetRuleEnabledMissing(t *testing.T) {
+  	e := NewEngine()
+  	if e.SetRuleEnabled("nope", true) || e.SetRuleEnabled("nope", false) {
+      		t.Error("SetRuleEnabled reported a missing rule as existing")
+      	}
+  	if !e.SetRuleEnabled("AND", false) {
+      		t.Error("SetRuleEnabled(AND) reported false")
+      	}
+  	if r := e.Evaluate("AND", TRUE); r.Value != UNKNOWN {
+      		t.Errorf("disabled AND = %v, want UNKNOWN", r.Value)
+      	}
+  }
+
+func TestRulesWithPrefix(t *testing.T) {
+  	always := TernaryRule{Evaluate: func(...Trit) Trit { return TRUE }}
+  	tests := []struct {
+      		name   string
+      		prefix string
+      		change func(e *Engine)
+      		want   []string
+      	}{
+      		{name: "namespace", prefix: "security/", want: []string{"security/a", "security/b"}},
+      		{name: "bare prefix", prefix: "security", want: []string{"security/a", "security/b", "securityx"}},
+      		{name: "no match", prefix: "zzz"},
+      		{name: "added rule", prefix: "security/", change: func(e *Engine) { e.AddRule("security/0", always) }, want: []string{"security/0", "security/a", "security/b"}},
+      		{name: "removed rule", prefix: "security/", change: func(e *Engine) { e.RemoveRule("security/a") }, want: []string{"security/b"}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("security/b", always)
+                    			e.AddRule("security/a", always)
+                    			e.AddRule("securityx", always)
+                    			e.RulesWithPrefix("") // build the index before any change
+                    			if tt.change != nil {
+                              				tt.change(e)
+                              			}
+                    			if got := e.RulesWithPrefix(tt.prefix); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("RulesWithPrefix(%q) = %v, want %v", tt.prefix, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestRulesWithPrefixConcurrent(t *testing.T) {
+  	e := NewEngine()
+  	always := TernaryRule{Evaluate: func(...Trit) Trit { return TRUE }}
+  	var wg sync.WaitGroup
+  	for i := 0; i < 8; i++ {
+      		wg.Add(2)
+      		go func() {
+            			defer wg.Done()
+            			e.AddRule(fmt.Sprintf("ns/%d", i), always)
+            		}()
+      		go func() {
+            			defer wg.Done()
+            			for _, name := range e.RulesWithPrefix("ns/") {
+                    				if !strings.HasPrefix(name, "ns/") {
+                              					t.Errorf("RulesWithPrefix(ns/) returned %q", name)
+                              				}
+                    			}
+            		}()
+      	}
+  	wg.Wait()
+  	if got := len(e.RulesWithPrefix("ns/")); got != 8 {
+      		t.Errorf("RulesWithPrefix(ns/) found %d rules, want 8", got)
+      	}
+  }
+
+func TestRemoveDisableRule(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		setup      func(e *Engine) []bool
+      		wantOK     []bool
+      		rule       string
+      		want       Trit
+      		wantReason string
+      	}{
+      		{
+            			name:       "disable",
+            			setup:      func(e *Engine) []bool { return []bool{e.DisableRule("OR")} },
+            			wantOK:     []bool{true},
+            			rule:       "OR",
+            			want:       UNKNOWN,
+            			wantReason: "disabled",
+            		},
+      		{
+            			name:   "re-enable",
+            			setup:  func(e *Engine) []bool { return []bool{e.DisableRule("OR"), e.EnableRule("OR")} },
+            			wantOK: []bool{true, true},
+            			rule:   "OR",
+            			want:   TRUE,
+            		},
+      		{
+            			name:       "remove",
+            			setup:      func(e *Engine) []bool { return []bool{e.RemoveRule("AND"), e.RemoveRule("AND")} },
+            			wantOK:     []bool{true, false},
+            			rule:       "AND",
+            			want:       UNKNOWN,
+            			wantReason: "Rule 'AND' not found",
+            		},
+      		{
+            			name: "missing",
+            			setup: func(e *Engine) []bool {
+                    				return []bool{e.DisableRule("nope"), e.EnableRule("nope"), e.RemoveRule("nope")}
+                    			},
+            			wantOK:     []bool{false, false, false},
+            			rule:       "nope",
+            			want:       UNKNOWN,
+            			wantReason: "not found",
+            		},
+      		{
+            			name: "re-added after removing a disabled rule",
+            			setup: func(e *Engine) []bool {
+                    				ok := []bool{e.DisableRule("AND"), e.RemoveRule("AND")}
+                    				e.AddRule("AND", TernaryRule{Name: "AND", Weight: 1, Evaluate: func(...Trit) Trit { return FALSE }})
+                    				return ok
+                    			},
+            			wantOK: []bool{true, true},
+            			rule:   "AND",
+            			want:   FALSE,
+            		},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if ok := tt.setup(e); !reflect.DeepEqual(ok, tt.wantOK) {
+                              				t.Errorf("setup returned %v, want %v", ok, tt.wantOK)
+                              			}
+                    			r := e.Evaluate(tt.rule, TRUE)
+                    			if r.Value != tt.want || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("%s(TRUE) = %v %q, want %v containing %q", tt.rule, r.Value, r.Reason, tt.want, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestRemoveRuleConcurrent(t *testing.T) {
+  	e := NewEngine()
+  	var wg sync.WaitGroup
+  	for i := 0; i < 4; i++ {
+      		wg.Add(2)
+      		go func() { defer wg.Done(); e.Evaluate("XOR", TRUE) }()
+      		go func() { defer wg.Done(); e.RemoveRule("XOR") }()
+      	}
+  	wg.Wait()
+  	if r := e.Evaluate("XOR", TRUE); !strings.Contains(r.Reason, "not found") {
+      		t.Errorf("XOR still registered: %q", r.Reason)
+      	}
+  }
@@ -0,0 +1,67 @@
+package ternary
+
+// RuleStat summarizes the decisions one rule has produced
+type RuleStat struct {
+  	Invocations   uint64  `json:"invocations"`
+  	True          uint64  `json:"true"`
+  	False         uint64  `json:"false"`
+  	Unknown       uint64  `json:"unknown"`
+  	AvgConfidence float64 `json:"avg_confidence"`
+  }
+
+// ruleCounter accumulates a RuleStat
+type ruleCounter struct {
+  	stat    RuleStat
+  	confSum float64
+  }
+
+// countLocked adds result to the statistics of its rule. Every decision
+// counts, including those the history skips through RecordOnlyOnChange or
+// sampling. The caller must hold e.mu.
+func (e *Engine) countLocked(result TernaryResult) {
+  	if result.Rule == "" {
+      		return
+      	}
+  	c := e.ruleStats[result.Rule]
+  	if c == nil {
+      		c = new(ruleCounter)
+      		e.ruleStats[result.Rule] = c
+      	}
+
+  	c.stat.Invocations++
+  	e.ruleTotals[result.Rule]++
+  	switch result.Value {
+      	case TRUE:
+      		c.stat.True++
+      	case FALSE:
+      		c.stat.False++
+      	case UNKNOWN:
+      		c.stat.Unknown++
+      	}
+  	c.confSum += result.Confidence
+  	e.confHist.observe(result.Confidence)
+  }
+
+// RuleStats returns per-rule decision statistics since the engine was
+// created or ResetStats was last called. Rules that have not produced a
+// decision are absent.
+func (e *Engine) RuleStats() map[string]RuleStat {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	out := make(map[string]RuleStat, len(e.ruleStats))
+  	for name, c := range e.ruleStats {
+      		stat := c.stat
+      		stat.AvgConfidence = c.confSum / float64(stat.Invocations)
+      		out[name] = stat
+      	}
+  	return out
+  }
+
+// ResetStats zeroes the per-rule statistics. The aggregate counters of
+// Stats and the decision history are kept.
+func (e *Engine) ResetStats() {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	clear(e.ruleStats)
+  }
@@ -0,0 +1,63 @@
+package ternary
+
+import (
+  	"math"
+  	"testing"
+  )
+
+func TestRuleStats(t *testing.T) {
+  	type eval struct {
+      		rule   string
+      		inputs []Trit
+      	}
+  	tests := []struct {
+      		name  string
+      		evals []eval
+      		want  map[string]RuleStat
+      	}{
+      		{name: "none", want: map[string]RuleStat{}},
+      		{
+            			name:  "one rule",
+            			evals: []eval{{"AND", []Trit{TRUE}}, {"AND", []Trit{FALSE}}, {"AND", []Trit{UNKNOWN}}, {"AND", []Trit{TRUE}}},
+            			want:  map[string]RuleStat{"AND": {Invocations: 4, True: 2, False: 1, Unknown: 1, AvgConfidence: 0.625}},
+            		},
+      		{
+            			name:  "per rule",
+            			evals: []eval{{"AND", []Trit{TRUE}}, {"NOT", []Trit{TRUE}}, {"NOT", []Trit{UNKNOWN}}},
+            			want:  map[string]RuleStat{"AND": {Invocations: 1, True: 1, AvgConfidence: 1}, "NOT": {Invocations: 2, False: 1, Unknown: 1, AvgConfidence: 0.25}},
+            		},
+      		{name: "failures not counted", evals: []eval{{"missing", nil}, {"IMPLIES", nil}}, want: map[string]RuleStat{"IMPLIES": {Invocations: 1, Unknown: 1, AvgConfidence: 0.5}}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			for _, ev := range tt.evals {
+                              				e.Evaluate(ev.rule, ev.inputs...)
+                              			}
+                    			got := e.RuleStats()
+                    			if len(got) != len(tt.want) {
+                              				t.Fatalf("RuleStats = %v, want %v", got, tt.want)
+                              			}
+                    			for name, want := range tt.want {
+                              				g := got[name]
+                              				avg := g.AvgConfidence
+                              				g.AvgConfidence, want.AvgConfidence = 0, 0
+                              				if g != want || math.Abs(avg-tt.want[name].AvgConfidence) > 1e-9 {
+                                          					t.Errorf("RuleStats[%s] = %+v (avg %v), want %+v", name, got[name], avg, tt.want[name])
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestResetStats(t *testing.T) {
+  	e := NewEngine()
+  	e.Evaluate("AND", TRUE)
+  	e.ResetStats()
+  	if st := e.RuleStats(); len(st) != 0 {
+      		t.Errorf("RuleStats after ResetStats = %v", st)
+      	}
+  	if st := e.Stats(); st["total_evaluations"] != uint64(1) || st["total_decisions"] != 1 {
+      		t.Errorf("ResetStats cleared the aggregate stats: %v", st)
+      	}
+  }
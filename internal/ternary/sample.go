@@ -0,0 +1,36 @@
+package ternary
+
+import (
+  	"fmt"
+  	"math"
+  )
+
+// SetSampleRate records only a fraction rate of decisions in the history,
+// for engines evaluating faster than the history is useful. The sampler is
+// a counter: with N = round(1/rate) it keeps the first decision and then
+// exactly one of every N that would otherwise be recorded, so over k such
+// decisions it keeps ceil(k/N). Every evaluation still counts in Stats and
+// returns its result, and confidence-crossing callbacks still see it;
+// skipped decisions are not written to the WAL. A rate of 1 records all.
+func (e *Engine) SetSampleRate(rate float64) error {
+  	if !(rate > 0 && rate <= 1) {
+      		return fmt.Errorf("ternary: sample rate %v outside (0, 1]", rate)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.sampleEvery = uint64(math.Round(1 / rate))
+  	e.sampleSeen = 0
+  	return nil
+  }
+
+// sampleLocked reports whether the decision being recorded is kept by the
+// sampler. The caller must hold e.mu.
+func (e *Engine) sampleLocked() bool {
+  	if e.sampleEvery <= 1 {
+      		return true
+      	}
+  	keep := e.sampleSeen%e.sampleEvery == 0
+  	e.sampleSeen++
+  	return keep
+  }
@@ -0,0 +1,56 @@
+package ternary
+
+import (
+  	"math"
+  	"testing"
+  )
+
+func TestSetSampleRate(t *testing.T) {
+  	tests := []struct {
+      		rate      float64
+      		evals     int
+      		wantKept  int
+      		wantError bool
+      	}{
+      		{rate: 1, evals: 10, wantKept: 10},
+      		{rate: 0.5, evals: 10, wantKept: 5},
+      		{rate: 0.5, evals: 9, wantKept: 5},
+      		{rate: 0.1, evals: 1000, wantKept: 100},
+      		{rate: 0.3, evals: 10, wantKept: 4},
+      		{rate: 0.001, evals: 5, wantKept: 1},
+      		{rate: 0, wantError: true},
+      		{rate: -0.5, wantError: true},
+      		{rate: 1.5, wantError: true},
+      		{rate: math.NaN(), wantError: true},
+      	}
+  	for _, tt := range tests {
+      		e := NewEngine()
+      		err := e.SetSampleRate(tt.rate)
+      		if (err != nil) != tt.wantError {
+            			t.Errorf("SetSampleRate(%v) error = %v, want error %v", tt.rate, err, tt.wantError)
+            			continue
+            		}
+      		for i := 0; i < tt.evals; i++ {
+            			e.Evaluate("AND", TRUE)
+            		}
+      		st := e.Stats()
+      		if st["total_decisions"] != tt.wantKept || st["total_evaluations"] != uint64(tt.evals) {
+            			t.Errorf("rate %v over %d evaluations: kept %v of %v, want %d", tt.rate, tt.evals, st["total_decisions"], st["total_evaluations"], tt.wantKept)
+            		}
+      	}
+  }
+
+func TestSampleRateReset(t *testing.T) {
+  	e := NewEngine()
+  	if err := e.SetSampleRate(0.5); err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Evaluate("AND", TRUE)
+  	// restarting the sampler keeps the next decision again
+  	if err := e.SetSampleRate(0.5); err != nil {
+      		t.Fatal(err)
+      	}
+  	if r := e.Evaluate("AND", FALSE); len(e.GetDecisions(DecisionFilter{})) != 2 {
+      		t.Errorf("decision %v not kept after SetSampleRate", r.ID)
+      	}
+  }
@@ -0,0 +1,93 @@
+package ternary
+
+import (
+  	"encoding/json"
+  	"errors"
+  	"fmt"
+  	"io/fs"
+  	"os"
+  )
+
+// RuleScore tallies the feedback given on one rule's decisions
+type RuleScore struct {
+  	Correct uint64 `json:"correct"`
+  	Total   uint64 `json:"total"`
+  }
+
+// Accuracy returns the Laplace-smoothed share of correct decisions,
+// (Correct+1)/(Total+2), so a rule without feedback scores 0.5 and a
+// single verdict cannot make it 0 or 1
+func (s RuleScore) Accuracy() float64 {
+  	return float64(s.Correct+1) / float64(s.Total+2)
+  }
+
+// Feedback scores result against the actual outcome on the scorecard of
+// the rule that produced it: the decision is correct when its value equals
+// actual. Results of no rule are ignored.
+func (e *Engine) Feedback(result TernaryResult, actual Trit) {
+  	if result.Rule == "" {
+      		return
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	score := e.scores[result.Rule]
+  	score.Total++
+  	if result.Value == actual {
+      		score.Correct++
+      	}
+  	e.scores[result.Rule] = score
+  	e.version++
+  }
+
+// Scorecard returns a copy of the feedback tallies by rule
+func (e *Engine) Scorecard() map[string]RuleScore {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+
+  	out := make(map[string]RuleScore, len(e.scores))
+  	for name, score := range e.scores {
+      		out[name] = score
+      	}
+  	return out
+  }
+
+// accuracy returns the scorecard accuracy of ruleName
+func (e *Engine) accuracy(ruleName string) float64 {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+  	return e.scores[ruleName].Accuracy()
+  }
+
+// SaveScorecard writes the scorecard to path as JSON, replacing the file
+// atomically
+func (e *Engine) SaveScorecard(path string) error {
+  	data, err := json.MarshalIndent(e.Scorecard(), "", "  ")
+  	if err != nil {
+      		return fmt.Errorf("ternary: encode scorecard: %w", err)
+      	}
+  	return writeFileAtomic(path, data)
+  }
+
+// LoadScorecard replaces the scorecard with the one saved at path. A
+// missing file loads as an empty scorecard.
+func (e *Engine) LoadScorecard(path string) error {
+  	scores := make(map[string]RuleScore)
+  	data, err := os.ReadFile(path)
+  	switch {
+      	case errors.Is(err, fs.ErrNotExist):
+      	case err != nil:
+      		return fmt.Errorf("ternary: read scorecard: %w", err)
+      	default:
+      		if err := json.Unmarshal(data, &scores); err != nil {
+            			return fmt.Errorf("ternary: decode scorecard %s: %w", path, err)
+            		}
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.scores = scores
+  	e.version++
+  	return nil
+  }
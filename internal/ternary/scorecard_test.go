@@ -0,0 +1,80 @@
+package ternary
+
+import (
+  	"math"
+  	"os"
+  	"path/filepath"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestRuleScoreAccuracy(t *testing.T) {
+  	tests := []struct {
+      		score RuleScore
+      		want  float64
+      	}{
+      		{RuleScore{}, 0.5},
+      		{RuleScore{Correct: 1, Total: 1}, 2.0 / 3},
+      		{RuleScore{Correct: 0, Total: 1}, 1.0 / 3},
+      		{RuleScore{Correct: 20, Total: 20}, 21.0 / 22},
+      		{RuleScore{Correct: 5, Total: 10}, 0.5},
+      	}
+  	for _, tt := range tests {
+      		if got := tt.score.Accuracy(); math.Abs(got-tt.want) > 1e-9 {
+            			t.Errorf("%+v.Accuracy() = %v, want %v", tt.score, got, tt.want)
+            		}
+      	}
+  }
+
+func TestFeedback(t *testing.T) {
+  	e := NewEngine()
+  	e.Feedback(e.Evaluate("AND", TRUE), TRUE)
+  	e.Feedback(e.Evaluate("AND", TRUE), FALSE)
+  	e.Feedback(e.Evaluate("OR", FALSE), FALSE)
+  	e.Feedback(TernaryResult{Value: TRUE}, TRUE) // no rule: ignored
+  	want := map[string]RuleScore{"AND": {Correct: 1, Total: 2}, "OR": {Correct: 1, Total: 1}}
+  	if got := e.Scorecard(); !reflect.DeepEqual(got, want) {
+      		t.Errorf("Scorecard = %v, want %v", got, want)
+      	}
+  }
+
+func TestScorecardPersistence(t *testing.T) {
+  	dir := t.TempDir()
+  	saved := filepath.Join(dir, "scores.json")
+  	e := NewEngine()
+  	e.Feedback(e.Evaluate("AND", TRUE), TRUE)
+  	e.Feedback(e.Evaluate("AND", TRUE), FALSE)
+  	if err := e.SaveScorecard(saved); err != nil {
+      		t.Fatal(err)
+      	}
+  	corrupt := filepath.Join(dir, "corrupt.json")
+  	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+      		t.Fatal(err)
+      	}
+
+  	tests := []struct {
+      		name    string
+      		path    string
+      		want    map[string]RuleScore
+      		wantErr string
+      	}{
+      		{name: "saved", path: saved, want: map[string]RuleScore{"AND": {Correct: 1, Total: 2}}},
+      		{name: "missing", path: filepath.Join(dir, "missing.json"), want: map[string]RuleScore{}},
+      		{name: "corrupt", path: corrupt, want: map[string]RuleScore{"OR": {Correct: 1, Total: 1}}, wantErr: "decode scorecard"},
+      		{name: "unreadable", path: dir, want: map[string]RuleScore{"OR": {Correct: 1, Total: 1}}, wantErr: "read scorecard"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			f := NewEngine()
+                    			f.Feedback(f.Evaluate("OR", FALSE), FALSE)
+                    			err := f.LoadScorecard(tt.path)
+                    			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+                              				t.Errorf("LoadScorecard error = %v, want %q", err, tt.wantErr)
+                              			}
+                    			if got := f.Scorecard(); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("Scorecard = %v, want %v", got, tt.want)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,62 @@
+package ternary
+
+import (
+  	"fmt"
+  	"iter"
+  )
+
+// EvaluateSeq evaluates a rule over the trits yielded by seq and records the
+// decision like Evaluate. The variadic built-in rules (AND, OR, CONSENSUS,
+// XOR, EQ, UNANIMOUS, ...) fold the sequence with their running state as it
+// is consumed, so memory stays constant however many trits seq yields.
+// Other rules take their inputs as a slice, so for them, and whenever the
+// inputs themselves are needed (a Cacheable rule, CaptureInputs), the
+// sequence is drained into a buffer proportional to its length. Either way
+// seq is consumed without holding the engine lock, so a slow sequence
+// never blocks other evaluations.
+func (e *Engine) EvaluateSeq(ruleName string, seq iter.Seq[Trit]) TernaryResult {
+  	e.mu.RLock()
+  	rule, exists := e.rules[ruleName]
+  	fold := exists && rule.incremental != nil && !rule.Cacheable && !e.capture
+  	coerce := e.invalidMode == CoerceInvalid
+  	e.mu.RUnlock()
+
+  	if !fold {
+      		var inputs []Trit
+      		for t := range seq {
+            			inputs = append(inputs, t)
+            		}
+      		return e.Evaluate(ruleName, inputs...)
+      	}
+
+  	step := rule.incremental()
+  	value, n := UNKNOWN, 0
+  	for t := range seq {
+      		if !IsValid(t) {
+            			if !coerce {
+                    				e.mu.Lock()
+                    				defer e.mu.Unlock()
+                    				e.evalCount++
+                    				return e.failedResult(invalidInputReason(ruleName, t, n))
+                    			}
+            			t = UNKNOWN
+            		}
+      		value = step(t)
+      		n++
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if n == 0 {
+      		return e.evaluateLocked(ruleName, nil)
+      	}
+  	e.evalCount++
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+  	result := e.resultLocked(ruleName, rule.Weight, value, nil, fmt.Sprintf("Rule[%s] evaluated %d inputs", ruleName, n))
+  	result.InputCount = n
+  	return e.recordLocked(result)
+  }
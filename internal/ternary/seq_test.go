@@ -0,0 +1,145 @@
+package ternary
+
+import (
+  	"iter"
+  	"slices"
+  	"strings"
+  	"testing"
+  )
+
+// allInputs returns every input vector of length 0 through maxLen
+func allInputs(maxLen int) [][]Trit {
+  	var out [][]Trit
+  	for n := 0; n <= maxLen; n++ {
+      		forEachInput(n, func(inputs []Trit) bool {
+                    			out = append(out, append([]Trit(nil), inputs...))
+                    			return true
+                    		})
+      	}
+  	return out
+  }
+
+func TestEvaluateSeq(t *testing.T) {
+  	seq := func(yield func(Trit) bool) {
+      		for _, v := range []Trit{TRUE, TRUE, FALSE} {
+            			if !yield(v) {
+                    				return
+                    			}
+            		}
+      	}
+  	tests := []struct {
+      		rule string
+      		want Trit
+      	}{
+      		{"CONSENSUS", TRUE},
+      		{"AND", FALSE},
+      		{"OR", TRUE},
+      		{"XOR", FALSE},
+      		{"UNANIMOUS", UNKNOWN},
+      		{"PLURALITY", TRUE},
+      	}
+  	for _, tt := range tests {
+      		if r := NewEngine().EvaluateSeq(tt.rule, seq); r.Value != tt.want || r.InputCount != 3 || r.Rule != tt.rule {
+            			t.Errorf("EvaluateSeq(%s) = %+v, want %v over 3 inputs", tt.rule, r, tt.want)
+            		}
+      	}
+  }
+
+func TestEvaluateSeqMatchesEvaluate(t *testing.T) {
+  	e := NewEngine()
+  	e.AddRule("FIRST", TernaryRule{Name: "FIRST", Weight: 1, Evaluate: func(in ...Trit) Trit {
+                    		if len(in) == 0 {
+                              			return UNKNOWN
+                              		}
+                    		return in[0]
+                    	}})
+  	rules := []string{"AND", "OR", "CONSENSUS", "XOR", "XNOR", "EQ", "UNANIMOUS", "HAS_UNKNOWN", "ALL_KNOWN", "SQL_AND", "SQL_OR", "PLURALITY", "FIRST"}
+  	for _, name := range rules {
+      		for _, inputs := range allInputs(4) {
+            			got := e.EvaluateSeq(name, slices.Values(inputs))
+            			want := e.Evaluate(name, inputs...)
+            			if got.Value != want.Value || got.Confidence != want.Confidence || got.InputCount != want.InputCount || got.Reason != want.Reason {
+                    				t.Fatalf("%s%v: EvaluateSeq = %v %q, Evaluate = %v %q", name, inputs, got.Value, got.Reason, want.Value, want.Reason)
+                    			}
+            		}
+      	}
+  	if st := e.Stats(); st["total_evaluations"] != st["lifetime_decisions"] {
+      		t.Errorf("stats = %v", st)
+      	}
+  }
+
+func TestEvaluateSeqEmptyInputResult(t *testing.T) {
+  	e := NewEngine(EmptyInputResult(UNKNOWN))
+  	if r := e.EvaluateSeq("AND", slices.Values([]Trit(nil))); r.Value != UNKNOWN || r.InputCount != 0 {
+      		t.Errorf("got %+v", r)
+      	}
+  }
+
+// countingSeq yields n copies of v, counting how many were consumed
+func countingSeq(n int, v Trit, consumed *int) iter.Seq[Trit] {
+  	return func(yield func(Trit) bool) {
+      		for i := 0; i < n; i++ {
+            			*consumed++
+            			if !yield(v) {
+                    				return
+                    			}
+            		}
+      	}
+  }
+
+func TestEvaluateSeqConstantMemory(t *testing.T) {
+  	e := NewEngine()
+  	consumed := 0
+  	allocs := testing.AllocsPerRun(5, func() {
+            		e.EvaluateSeq("CONSENSUS", countingSeq(100000, TRUE, &consumed))
+            	})
+  	if allocs > 20 {
+      		t.Errorf("EvaluateSeq over 100000 trits made %v allocations, want a constant few", allocs)
+      	}
+  	if consumed != 6*100000 {
+      		t.Errorf("consumed %d trits", consumed)
+      	}
+  }
+
+func TestEvaluateSeqInvalidInputs(t *testing.T) {
+  	seq := func(consumed *int) iter.Seq[Trit] {
+      		return func(yield func(Trit) bool) {
+            			for _, v := range []Trit{TRUE, 8, TRUE, TRUE} {
+                    				*consumed++
+                    				if !yield(v) {
+                              					return
+                              				}
+                    			}
+            		}
+      	}
+  	for _, rule := range []string{"AND", "CONSENSUS"} {
+      		consumed := 0
+      		e := NewEngine()
+      		if r := e.EvaluateSeq(rule, seq(&consumed)); r.Rule != "" || !strings.Contains(r.Reason, "rejected invalid input 8 at position 1") {
+            			t.Errorf("%s reject: got %+v", rule, r)
+            		}
+      		if consumed != 2 {
+            			t.Errorf("%s reject: consumed %d trits, want to stop at the invalid one", rule, consumed)
+            		}
+      		if len(e.GetDecisions(DecisionFilter{})) != 0 {
+            			t.Errorf("%s reject: recorded", rule)
+            		}
+
+      		c := NewEngine(WithInvalidInputs(CoerceInvalid))
+      		want := c.Evaluate(rule, TRUE, UNKNOWN, TRUE, TRUE).Value
+      		if r := c.EvaluateSeq(rule, seq(&consumed)); r.Value != want || r.InputCount != 4 {
+            			t.Errorf("%s coerce: got %+v, want %v", rule, r, want)
+            		}
+      	}
+  }
+
+func TestEvaluateSeqBuffered(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	r := e.EvaluateSeq("AND", slices.Values([]Trit{TRUE, UNKNOWN}))
+  	if r.Value != UNKNOWN || !slices.Equal(r.Inputs, []Trit{TRUE, UNKNOWN}) {
+      		t.Errorf("captured: got %+v", r)
+      	}
+  	if r := e.EvaluateSeq("MISSING", slices.Values([]Trit{TRUE})); !strings.Contains(r.Reason, "not found") {
+      		t.Errorf("missing rule: got %+v", r)
+      	}
+  }
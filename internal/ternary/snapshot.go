@@ -0,0 +1,140 @@
+package ternary
+
+import (
+  	"crypto/sha256"
+  	"encoding/hex"
+  	"encoding/json"
+  	"fmt"
+  	"sort"
+  	"strings"
+  )
+
+// snapshotVersion is the format version written by Snapshot
+const snapshotVersion = 1
+
+// snapshotEnvelope wraps the engine state with its format version and the
+// SHA-256 of the encoded state
+type snapshotEnvelope struct {
+  	Version  int             `json:"version"`
+  	Checksum string          `json:"checksum"`
+  	State    json.RawMessage `json:"state"`
+  }
+
+// snapshotState is the engine state saved by Snapshot
+type snapshotState struct {
+  	Decisions  []TernaryResult `json:"decisions"`
+  	Lifetime   uint64          `json:"lifetime"`
+  	EvalCount  uint64          `json:"eval_count"`
+  	TruthTable map[string]Trit `json:"truth_table"`
+  	Rules      []RuleInfo      `json:"rules"`
+  }
+
+// MissingRulesError is returned by Restore when the snapshot names rules
+// that are not registered on the engine. Their evaluation functions cannot
+// be serialized, so custom rules must be added with AddRule before Restore
+// to be re-linked; everything else in the snapshot has been restored.
+type MissingRulesError struct {
+  	Rules []string // sorted
+  }
+
+func (err *MissingRulesError) Error() string {
+  	return fmt.Sprintf("ternary: snapshot rules not registered: %s", strings.Join(err.Rules, ", "))
+  }
+
+// Snapshot serializes the engine state: the decision history, the
+// evaluation count, the truth table and the name, weight, enabled flag and
+// tags of every registered rule. Restore reads it back.
+func (e *Engine) Snapshot() ([]byte, error) {
+  	e.mu.RLock()
+  	state := snapshotState{
+      		Decisions:  e.historyLocked(),
+      		Lifetime:   e.lifetime,
+      		EvalCount:  e.evalCount,
+      		TruthTable: e.truthTable,
+      		Rules:      make([]RuleInfo, 0, len(e.rules)),
+      	}
+  	for name, rule := range e.rules {
+      		state.Rules = append(state.Rules, RuleInfo{
+                    			Name:    name,
+                    			Weight:  rule.Weight,
+                    			Enabled: !e.disabled[name],
+                    			Tags:    rule.Tags,
+                    		})
+      	}
+  	raw, err := json.Marshal(state)
+  	e.mu.RUnlock()
+  	if err != nil {
+      		return nil, fmt.Errorf("ternary: encode snapshot: %w", err)
+      	}
+
+  	sum := sha256.Sum256(raw)
+  	return json.Marshal(snapshotEnvelope{
+            		Version:  snapshotVersion,
+            		Checksum: hex.EncodeToString(sum[:]),
+            		State:    raw,
+            	})
+  }
+
+// Restore replaces the engine state with a Snapshot. Data of another
+// format version or failing its checksum is rejected and leaves the engine
+// unchanged. Rules are matched by name against those registered, which
+// include the defaults; the weight, enabled flag and tags of each are
+// restored. Snapshot rules that are not registered are reported in a
+// *MissingRulesError.
+func (e *Engine) Restore(data []byte) error {
+  	var env snapshotEnvelope
+  	if err := json.Unmarshal(data, &env); err != nil {
+      		return fmt.Errorf("ternary: decode snapshot: %w", err)
+      	}
+  	if env.Version != snapshotVersion {
+      		return fmt.Errorf("ternary: snapshot version %d, want %d", env.Version, snapshotVersion)
+      	}
+  	sum := sha256.Sum256(env.State)
+  	if hex.EncodeToString(sum[:]) != env.Checksum {
+      		return fmt.Errorf("ternary: snapshot checksum mismatch")
+      	}
+  	var state snapshotState
+  	if err := json.Unmarshal(env.State, &state); err != nil {
+      		return fmt.Errorf("ternary: decode snapshot state: %w", err)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.resetHistoryLocked(state.Decisions)
+  	e.lifetime = max(state.Lifetime, uint64(len(e.decisions)))
+  	clear(e.lastValue)
+  	for _, r := range state.Decisions {
+      		e.lastValue[r.Rule] = r.Value
+      	}
+  	if state.EvalCount < e.evalCount {
+      		e.evalDropped += e.evalCount - state.EvalCount
+      	}
+  	e.evalCount = state.EvalCount
+  	e.truthTable = make(map[string]Trit, len(state.TruthTable))
+  	for k, v := range state.TruthTable {
+      		e.truthTable[k] = v
+      	}
+
+  	var missing []string
+  	for _, info := range state.Rules {
+      		rule, exists := e.rules[info.Name]
+      		if !exists {
+            			missing = append(missing, info.Name)
+            			continue
+            		}
+      		rule.Weight = info.Weight
+      		rule.Tags = info.Tags
+      		e.rules[info.Name] = rule
+      		if info.Enabled {
+            			delete(e.disabled, info.Name)
+            		} else {
+            			e.disabled[info.Name] = true
+            		}
+      	}
+  	if len(missing) > 0 {
+      		sort.Strings(missing)
+      		return &MissingRulesError{Rules: missing}
+      	}
+  	return nil
+  }
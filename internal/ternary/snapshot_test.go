@@ -0,0 +1,107 @@
+package ternary
+
+import (
+  	"bytes"
+  	"errors"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+// snapshotEngine returns an engine carrying a custom rule, two decisions,
+// a truth table entry and a patched default rule, with its Snapshot.
+func snapshotEngine(t *testing.T) (*Engine, []byte) {
+  	t.Helper()
+  	e := NewEngine()
+  	e.AddRule("MINE", TernaryRule{Name: "MINE", Weight: 0.5, Evaluate: func(in ...Trit) Trit { return TRUE }})
+  	e.Evaluate("AND", TRUE, FALSE)
+  	e.Evaluate("MINE")
+  	e.truthTable["k"] = TRUE
+  	if err := e.PatchConfig([]byte(`{"CONSENSUS":{"weight":2,"enabled":false,"tags":["x"]}}`)); err != nil {
+      		t.Fatal(err)
+      	}
+  	data, err := e.Snapshot()
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	return e, data
+  }
+
+func withoutRule(infos []RuleInfo, drop string) []RuleInfo {
+  	var out []RuleInfo
+  	for _, info := range infos {
+      		if info.Name != drop {
+            			out = append(out, info)
+            		}
+      	}
+  	return out
+  }
+
+func TestSnapshotRestore(t *testing.T) {
+  	src, data := snapshotEngine(t)
+  	tests := []struct {
+      		name        string
+      		addCustom   bool
+      		wantMissing []string
+      		wantRules   []RuleInfo
+      	}{
+      		{name: "custom rule missing", wantMissing: []string{"MINE"}, wantRules: withoutRule(src.Rules(), "MINE")},
+      		{name: "custom rule registered", addCustom: true, wantRules: src.Rules()},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if tt.addCustom {
+                              				e.AddRule("MINE", TernaryRule{Name: "MINE", Evaluate: func(in ...Trit) Trit { return TRUE }})
+                              			}
+                    			err := e.Restore(data)
+                    			var me *MissingRulesError
+                    			if tt.wantMissing == nil {
+                              				if err != nil {
+                                          					t.Fatalf("Restore = %v, want nil", err)
+                                          				}
+                              			} else if !errors.As(err, &me) || !reflect.DeepEqual(me.Rules, tt.wantMissing) {
+                              				t.Fatalf("Restore = %v, want missing %v", err, tt.wantMissing)
+                              			}
+                    			got, want := e.GetDecisions(DecisionFilter{}), src.GetDecisions(DecisionFilter{})
+                    			if len(got) != len(want) || got[1].ID != want[1].ID {
+                              				t.Errorf("decisions = %v, want %v", got, want)
+                              			}
+                    			if got := e.Stats()["total_evaluations"]; got != uint64(2) {
+                              				t.Errorf("total_evaluations = %v, want 2", got)
+                              			}
+                    			if got := e.truthTable["k"]; got != TRUE {
+                              				t.Errorf("truthTable[k] = %v, want TRUE", got)
+                              			}
+                    			if got := e.Rules(); !reflect.DeepEqual(got, tt.wantRules) {
+                              				t.Errorf("Rules() = %v, want %v", got, tt.wantRules)
+                              			}
+                    		})
+      	}
+  }
+
+func TestRestoreErrors(t *testing.T) {
+  	_, data := snapshotEngine(t)
+  	tests := []struct {
+      		name    string
+      		data    []byte
+      		wantErr string
+      	}{
+      		{name: "corrupt state", data: bytes.Replace(data, []byte(`AND`), []byte(`ANX`), 1), wantErr: "checksum"},
+      		{name: "other version", data: bytes.Replace(data, []byte(`"version":1`), []byte(`"version":2`), 1), wantErr: "version 2"},
+      		{name: "garbage", data: []byte("{"), wantErr: "decode snapshot"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.Evaluate("OR", TRUE)
+                    			err := e.Restore(tt.data)
+                    			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                              				t.Fatalf("Restore = %v, want error containing %q", err, tt.wantErr)
+                              			}
+                    			if got := len(e.GetDecisions(DecisionFilter{})); got != 1 {
+                              				t.Errorf("decisions after failed Restore = %d, want 1", got)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,56 @@
+package ternary
+
+import "reflect"
+
+// StatDelta is one changed entry reported by DiffStats
+type StatDelta struct {
+  	Before interface{} `json:"before"`
+  	After  interface{} `json:"after"`
+  	Delta  float64     `json:"delta"` // After - Before for numeric values, else 0
+  }
+
+// DiffStats compares two Stats snapshots and returns a StatDelta for every
+// key whose value changed, including keys present in only one snapshot
+// (the missing side is nil). Unchanged keys are omitted.
+func DiffStats(before, after map[string]interface{}) map[string]interface{} {
+  	diff := make(map[string]interface{})
+  	for k, b := range before {
+      		a, ok := after[k]
+      		if !ok {
+            			diff[k] = StatDelta{Before: b}
+            			continue
+            		}
+      		bf, bNum := statNumber(b)
+      		af, aNum := statNumber(a)
+      		switch {
+            		case bNum && aNum:
+            			if af != bf {
+                    				diff[k] = StatDelta{Before: b, After: a, Delta: af - bf}
+                    			}
+            		case !reflect.DeepEqual(a, b):
+            			diff[k] = StatDelta{Before: b, After: a}
+            		}
+      	}
+  	for k, a := range after {
+      		if _, ok := before[k]; !ok {
+            			diff[k] = StatDelta{After: a}
+            		}
+      	}
+  	return diff
+  }
+
+// statNumber converts a numeric stat value to float64
+func statNumber(v interface{}) (float64, bool) {
+  	switch n := v.(type) {
+      	case int:
+      		return float64(n), true
+      	case int64:
+      		return float64(n), true
+      	case uint64:
+      		return float64(n), true
+      	case float64:
+      		return n, true
+      	default:
+      		return 0, false
+      	}
+  }
@@ -0,0 +1,43 @@
+package ternary
+
+import (
+  	"reflect"
+  	"testing"
+  )
+
+func TestDiffStats(t *testing.T) {
+  	tests := []struct {
+      		name          string
+      		before, after map[string]interface{}
+      		want          map[string]interface{}
+      	}{
+      		{name: "unchanged", before: map[string]interface{}{"n": 1}, after: map[string]interface{}{"n": 1}, want: map[string]interface{}{}},
+      		{name: "int", before: map[string]interface{}{"n": 1}, after: map[string]interface{}{"n": 4}, want: map[string]interface{}{"n": StatDelta{Before: 1, After: 4, Delta: 3}}},
+      		{name: "mixed numeric types", before: map[string]interface{}{"n": int64(2)}, after: map[string]interface{}{"n": 0.5}, want: map[string]interface{}{"n": StatDelta{Before: int64(2), After: 0.5, Delta: -1.5}}},
+      		{name: "same number different type", before: map[string]interface{}{"n": 2}, after: map[string]interface{}{"n": uint64(2)}, want: map[string]interface{}{}},
+      		{name: "non-numeric", before: map[string]interface{}{"s": "a"}, after: map[string]interface{}{"s": "b"}, want: map[string]interface{}{"s": StatDelta{Before: "a", After: "b"}}},
+      		{name: "removed", before: map[string]interface{}{"n": 1}, after: map[string]interface{}{}, want: map[string]interface{}{"n": StatDelta{Before: 1}}},
+      		{name: "added", before: map[string]interface{}{}, after: map[string]interface{}{"n": 1}, want: map[string]interface{}{"n": StatDelta{After: 1}}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := DiffStats(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+                              				t.Errorf("DiffStats(%v, %v) = %v, want %v", tt.before, tt.after, got, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestDiffStatsEngine(t *testing.T) {
+  	e := NewEngine()
+  	before := e.Stats()
+  	e.Evaluate("AND", TRUE)
+  	e.Evaluate("AND", TRUE)
+  	d := DiffStats(before, e.Stats())
+  	if got, ok := d["total_evaluations"].(StatDelta); !ok || got.Delta != 2 {
+      		t.Errorf("total_evaluations delta = %v, want 2", d["total_evaluations"])
+      	}
+  	if _, ok := d["registered_rules"]; ok {
+      		t.Errorf("registered_rules reported unchanged: %v", d["registered_rules"])
+      	}
+  }
@@ -0,0 +1,167 @@
+package ternary
+
+import (
+  	"context"
+  	"fmt"
+  )
+
+// EvaluateStream evaluates ruleName over a growing input set: each trit
+// received from in is appended to the inputs and the rule's result over
+// all inputs so far is recorded and sent on the returned channel. The
+// variadic built-in rules (AND, OR, CONSENSUS, XOR, EQ, UNANIMOUS, ...) keep
+// running state, so each input costs O(1); other rules, and those rules
+// too whenever the inputs themselves are needed (a Cacheable rule,
+// CaptureInputs), are re-evaluated over every input received, and their
+// results carry captured Inputs as usual.
+//
+// The rule is looked up when the first input arrives; until it is found
+// and enabled, each input yields the failure result instead. Invalid
+// inputs are handled as SetInvalidInputs says, a rejected one yielding a
+// failure result and being left out of the inputs. The output
+// channel is closed when in is closed or ctx is done.
+func (e *Engine) EvaluateStream(ctx context.Context, ruleName string, in <-chan Trit) <-chan TernaryResult {
+  	out := make(chan TernaryResult)
+  	go func() {
+      		defer close(out)
+      		s := &evalStream{engine: e, ruleName: ruleName}
+      		for {
+            			var x Trit
+            			select {
+                    			case <-ctx.Done():
+                    				return
+                    			case v, ok := <-in:
+                    				if !ok {
+                              					return
+                              				}
+                    				x = v
+                    			}
+            			select {
+                    			case <-ctx.Done():
+                    				return
+                    			case out <- s.next(x):
+                    			}
+            		}
+      	}()
+  	return out
+  }
+
+// evalStream is the state of one EvaluateStream
+type evalStream struct {
+  	engine   *Engine
+  	ruleName string
+  	rule     TernaryRule
+  	resolved bool
+  	step     func(Trit) Trit // running evaluator, if the rule has one
+  	inputs   []Trit          // every input, kept only without step
+  	n        int
+  }
+
+// next evaluates the stream with x appended and records the result
+func (s *evalStream) next(x Trit) TernaryResult {
+  	e := s.engine
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	if !s.resolved {
+      		rule, failed, ok := e.ruleLocked(s.ruleName)
+      		if !ok {
+            			return failed
+            		}
+      		s.rule, s.resolved = rule, true
+      		if rule.incremental != nil && !rule.Cacheable && !e.capture {
+            			s.step = rule.incremental()
+            		}
+      	}
+
+  	in, reason, ok := e.sanitizeLocked(s.ruleName, []Trit{x})
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	x = in[0]
+
+  	s.n++
+  	var value Trit
+  	note := ""
+  	if s.step != nil {
+      		value = s.step(x)
+      	} else {
+      		s.inputs = append(s.inputs, x)
+      		value, note = e.ruleValueLocked(s.ruleName, s.rule, s.inputs)
+      	}
+  	reason = fmt.Sprintf("Rule[%s] streamed %d inputs", s.ruleName, s.n)
+  	if note != "" {
+      		reason = note
+      	}
+  	result := e.resultLocked(s.ruleName, s.rule.Weight, value, s.inputs, reason)
+  	result.InputCount = s.n
+  	return e.recordLocked(result)
+  }
+
+// foldStream returns a running evaluator factory folding op from start
+func foldStream(start Trit, op func(a, b Trit) Trit) func() func(Trit) Trit {
+  	return func() func(Trit) Trit {
+      		acc := start
+      		return func(x Trit) Trit {
+            			acc = op(acc, x)
+            			return acc
+            		}
+      	}
+  }
+
+// unanimousStream is the running evaluator of UNANIMOUS
+func unanimousStream() func(Trit) Trit {
+  	var first Trit
+  	n, split := 0, false
+  	return func(x Trit) Trit {
+      		if n == 0 {
+            			first = x
+            		} else if x != first {
+            			split = true
+            		}
+      		n++
+      		if split {
+            			return UNKNOWN
+            		}
+      		return first
+      	}
+  }
+
+// anyUnknown is the HAS_UNKNOWN step: TRUE once an UNKNOWN was seen
+func anyUnknown(acc, x Trit) Trit {
+  	if x == UNKNOWN {
+      		return TRUE
+      	}
+  	return acc
+  }
+
+// allKnown is the ALL_KNOWN step: FALSE once an UNKNOWN was seen
+func allKnown(acc, x Trit) Trit {
+  	if x == UNKNOWN {
+      		return FALSE
+      	}
+  	return acc
+  }
+
+// consensusStream is the running evaluator of CONSENSUS
+func consensusStream() func(Trit) Trit {
+  	trueCount, falseCount, total := 0, 0, 0
+  	return func(x Trit) Trit {
+      		total++
+      		switch x {
+            		case TRUE:
+            			trueCount++
+            		case FALSE:
+            			falseCount++
+            		}
+      		switch {
+            		case trueCount > total/2:
+            			return TRUE
+            		case falseCount > total/2:
+            			return FALSE
+            		default:
+            			return UNKNOWN
+            		}
+      	}
+  }
@@ -0,0 +1,159 @@
+package ternary
+
+import (
+  	"context"
+  	"slices"
+  	"strings"
+  	"testing"
+  )
+
+// feed returns a closed, buffered channel holding seq
+func feed(seq []Trit) <-chan Trit {
+  	in := make(chan Trit, len(seq))
+  	for _, x := range seq {
+      		in <- x
+      	}
+  	close(in)
+  	return in
+  }
+
+func TestEvaluateStream(t *testing.T) {
+  	seq := []Trit{TRUE, FALSE, UNKNOWN, TRUE, FALSE, FALSE, UNKNOWN, TRUE, UNKNOWN}
+  	tests := []struct {
+      		rule string
+      		opts []Option
+      	}{
+      		{rule: "AND"},
+      		{rule: "OR"},
+      		{rule: "CONSENSUS"},
+      		{rule: "XOR"},
+      		{rule: "PLURALITY"},
+      		{rule: "AND", opts: []Option{WithLogicSystem(LogicLukasiewicz)}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.rule, func(t *testing.T) {
+                    			e := NewEngine(tt.opts...)
+                    			i := 0
+                    			for r := range e.EvaluateStream(context.Background(), tt.rule, feed(seq)) {
+                              				want := NewEngine(tt.opts...).Evaluate(tt.rule, seq[:i+1]...)
+                              				if r.Value != want.Value || r.Confidence != want.Confidence || r.InputCount != i+1 {
+                                          					t.Errorf("result %d = %v (%v, %d inputs), want %v (%v, %d inputs)",
+                                                        						i, r.Value, r.Confidence, r.InputCount, want.Value, want.Confidence, i+1)
+                                          				}
+                              				i++
+                              			}
+                    			if i != len(seq) {
+                              				t.Errorf("got %d results, want %d", i, len(seq))
+                              			}
+                    			if got := e.Stats()["total_evaluations"]; got != uint64(len(seq)) {
+                              				t.Errorf("total_evaluations = %v, want %d", got, len(seq))
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateStreamFailure(t *testing.T) {
+  	e := NewEngine()
+  	e.DisableRule("OR")
+  	tests := []struct {
+      		rule       string
+      		wantReason string
+      	}{
+      		{rule: "nope", wantReason: "not found"},
+      		{rule: "OR", wantReason: "disabled"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.rule, func(t *testing.T) {
+                    			for r := range e.EvaluateStream(context.Background(), tt.rule, feed([]Trit{TRUE, TRUE})) {
+                              				if r.Value != UNKNOWN || !strings.Contains(r.Reason, tt.wantReason) {
+                                          					t.Errorf("result = %v %q, want UNKNOWN with %q", r.Value, r.Reason, tt.wantReason)
+                                          				}
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateStreamCancel(t *testing.T) {
+  	e := NewEngine()
+  	ctx, cancel := context.WithCancel(context.Background())
+  	in := make(chan Trit)
+  	out := e.EvaluateStream(ctx, "AND", in)
+  	in <- TRUE
+  	if r := <-out; r.Value != TRUE {
+      		t.Errorf("first result = %v, want TRUE", r.Value)
+      	}
+  	cancel()
+  	if _, ok := <-out; ok {
+      		t.Error("output not closed after cancel")
+      	}
+  }
+
+func TestEvaluateStreamCacheable(t *testing.T) {
+  	// cacheableAND is AND answering TRUE, TRUE from the truth table as FALSE
+  	cacheableAND := func() *Engine {
+      		e := NewEngine()
+      		and := e.rules["AND"]
+      		and.Cacheable = true
+      		e.AddRule("AND", and)
+      		e.Memoize(TruthKey("AND", TRUE, TRUE), FALSE)
+      		return e
+      	}
+
+  	e, ref := cacheableAND(), cacheableAND()
+  	seq := []Trit{TRUE, TRUE, TRUE}
+  	var got []TernaryResult
+  	for r := range e.EvaluateStream(context.Background(), "AND", feed(seq)) {
+      		got = append(got, r)
+      	}
+  	want := []Trit{TRUE, FALSE, TRUE}
+  	if len(got) != len(want) {
+      		t.Fatalf("got %d results, want %d", len(got), len(want))
+      	}
+  	for i, r := range got {
+      		if r.Value != want[i] {
+            			t.Errorf("result %d = %v, want %v", i, r.Value, want[i])
+            		}
+      		if ev := ref.Evaluate("AND", seq[:i+1]...); r.Value != ev.Value {
+            			t.Errorf("result %d = %v, Evaluate gives %v", i, r.Value, ev.Value)
+            		}
+      	}
+  	if !strings.Contains(got[1].Reason, "truth table") {
+      		t.Errorf("memoized result reason = %q, want the truth table note", got[1].Reason)
+      	}
+  }
+
+func TestEvaluateStreamCaptureInputs(t *testing.T) {
+  	e := NewEngine(CaptureInputs())
+  	seq := []Trit{TRUE, UNKNOWN, FALSE}
+  	i := 0
+  	for r := range e.EvaluateStream(context.Background(), "OR", feed(seq)) {
+      		if !slices.Equal(r.Inputs, seq[:i+1]) {
+            			t.Errorf("result %d inputs = %v, want %v", i, r.Inputs, seq[:i+1])
+            		}
+      		i++
+      	}
+  }
+
+func TestEvaluateStreamRunningState(t *testing.T) {
+  	e := NewEngine()
+  	or := e.rules["OR"]
+  	calls := 0
+  	or.Evaluate = func(inputs ...Trit) Trit {
+      		calls++
+      		return UNKNOWN
+      	}
+  	e.AddRule("OR", or)
+
+  	seq := []Trit{FALSE, UNKNOWN, TRUE, FALSE}
+  	want := []Trit{FALSE, UNKNOWN, TRUE, TRUE}
+  	i := 0
+  	for r := range e.EvaluateStream(context.Background(), "OR", feed(seq)) {
+      		if r.Value != want[i] || r.Inputs != nil {
+            			t.Errorf("result %d = %v with inputs %v, want %v without inputs", i, r.Value, r.Inputs, want[i])
+            		}
+      		i++
+      	}
+  	if calls != 0 {
+      		t.Errorf("Evaluate called %d times, want the running state only", calls)
+      	}
+  }
@@ -0,0 +1,237 @@
+package ternary
+
+import (
+  	"errors"
+  	"fmt"
+  )
+
+const (
+  	// maxSynthSize bounds the number of nodes in a synthesized expression
+  	maxSynthSize = 9
+  	// maxSynthCandidates bounds the expressions SynthesizeRule evaluates
+  	maxSynthCandidates = 1 << 20
+  )
+
+// InputKey returns the key of an input vector in a truth table, e.g.
+// "TRUE, UNKNOWN" for SynthesizeRule
+func InputKey(inputs ...Trit) string {
+  	return formatInputs(inputs)
+  }
+
+// synthEntry is a synthesized expression with its outputs on the table's
+// input vectors
+type synthEntry struct {
+  	expr Expr
+  	sig  string
+  }
+
+// SynthesizeRule searches compositions of the available rules for the
+// smallest expression reproducing table, a truth table over arity inputs
+// keyed by InputKey. Vectors missing from the table are don't-cares. Leaves
+// are the variables "x0" through "x<arity-1>", standing for the inputs in
+// order, and the three constants; a rule without a fixed Arity is tried
+// with one and with two children. The search is exhaustive by size up to
+// 9 nodes and returns an error if nothing that small matches. The available
+// rules are tabulated up front, so the engine lock is not held during the
+// search.
+func (e *Engine) SynthesizeRule(table map[string]Trit, arity int, available []string) (Expr, error) {
+  	if arity < 0 || arity > maxEnumArity {
+      		return Expr{}, fmt.Errorf("ternary: synthesis arity %d outside [0, %d]", arity, maxEnumArity)
+      	}
+  	if len(table) == 0 {
+      		return Expr{}, errors.New("ternary: empty truth table")
+      	}
+
+  	var (
+      		points [][]Trit
+      		target []byte
+      	)
+  	forEachInput(arity, func(inputs []Trit) bool {
+            		if v, ok := table[InputKey(inputs...)]; ok {
+                    			points = append(points, append([]Trit(nil), inputs...))
+                    			target = append(target, byte(v))
+                    		}
+            		return true
+            	})
+  	if len(points) != len(table) {
+      		return Expr{}, fmt.Errorf("ternary: truth table has keys that are not %d-input vectors", arity)
+      	}
+
+  	// Each connective is tabulated over every input vector it can be given
+  	// under the read lock, so the search itself runs without holding it.
+  	type connective struct {
+      		name    string
+      		arities []int
+      		tables  map[int][]Trit // outputs by synthIndex of the inputs, per arity
+      	}
+  	connectives := make([]connective, 0, len(available))
+  	e.mu.RLock()
+  	for _, name := range available {
+      		rule, failed, ok := e.ruleLocked(name)
+      		if !ok {
+            			e.mu.RUnlock()
+            			return Expr{}, fmt.Errorf("ternary: %s", failed.Reason)
+            		}
+      		arities := []int{1, 2}
+      		if rule.Arity > 0 {
+            			arities = []int{rule.Arity}
+            		}
+      		c := connective{name: name, tables: make(map[int][]Trit)}
+      		for _, k := range arities {
+            			// a call with k children needs at least k+1 nodes
+            			if k+1 > maxSynthSize {
+                    				continue
+                    			}
+            			table := make([]Trit, 0, synthIndexSize(k))
+            			forEachInput(k, func(inputs []Trit) bool {
+                              				v, _ := e.ruleValueLocked(name, rule, inputs)
+                              				table = append(table, v)
+                              				return true
+                              			})
+            			c.arities = append(c.arities, k)
+            			c.tables[k] = table
+            		}
+      		connectives = append(connectives, c)
+      	}
+  	e.mu.RUnlock()
+
+  	bySize := make([][]synthEntry, maxSynthSize+1)
+  	seen := make(map[string]bool)
+  	want := string(target)
+  	add := func(size int, x Expr, sig []byte) bool {
+      		s := string(sig)
+      		if seen[s] {
+            			return false
+            		}
+      		seen[s] = true
+      		bySize[size] = append(bySize[size], synthEntry{x, s})
+      		return s == want
+      	}
+
+  	for i := 0; i < arity; i++ {
+      		sig := make([]byte, len(points))
+      		for p, inputs := range points {
+            			sig[p] = byte(inputs[i])
+            		}
+      		x := Variable(fmt.Sprintf("x%d", i))
+      		if add(1, x, sig) {
+            			return x, nil
+            		}
+      	}
+  	for _, v := range []Trit{FALSE, UNKNOWN, TRUE} {
+      		sig := make([]byte, len(points))
+      		for p := range sig {
+            			sig[p] = byte(v)
+            		}
+      		if add(1, Leaf(v), sig) {
+            			return Leaf(v), nil
+            		}
+      	}
+
+  	candidates := 0
+  	for size := 2; size <= maxSynthSize; size++ {
+      		for _, c := range connectives {
+            			for _, k := range c.arities {
+                    				var found *Expr
+                    				table := c.tables[k]
+                    				forEachComposition(size-1, k, func(parts []int) bool {
+                                          					return forEachProduct(bySize, parts, func(children []synthEntry) bool {
+                                                                        						candidates++
+                                                                        						if candidates > maxSynthCandidates {
+                                                                                          							return false
+                                                                                          						}
+                                                                        						sig := make([]byte, len(points))
+                                                                        						for p := range points {
+                                                                                          							sig[p] = byte(table[synthIndex(children, p)])
+                                                                                          						}
+                                                                        						kids := make([]Expr, len(children))
+                                                                        						for i, ch := range children {
+                                                                                          							kids[i] = ch.expr
+                                                                                          						}
+                                                                        						x := Call(c.name, kids...)
+                                                                        						if add(size, x, sig) {
+                                                                                          							found = &x
+                                                                                          							return false
+                                                                                          						}
+                                                                        						return true
+                                                                        					})
+                                          				})
+                    				if found != nil {
+                              					return *found, nil
+                              				}
+                    				if candidates > maxSynthCandidates {
+                              					return Expr{}, fmt.Errorf("ternary: no expression found within %d candidates", maxSynthCandidates)
+                              				}
+                    			}
+            		}
+      	}
+  	return Expr{}, fmt.Errorf("ternary: no expression of up to %d nodes matches the truth table", maxSynthSize)
+  }
+
+// synthIndexSize is the number of input vectors of length k
+func synthIndexSize(k int) int {
+  	n := 1
+  	for i := 0; i < k; i++ {
+      		n *= 3
+      	}
+  	return n
+  }
+
+// synthIndex is the position, in forEachInput order, of the input vector
+// formed by the outputs of children at point p
+func synthIndex(children []synthEntry, p int) int {
+  	i := 0
+  	for _, ch := range children {
+      		i = i*3 + int(int8(ch.sig[p])) + 1
+      	}
+  	return i
+  }
+
+// forEachComposition calls fn with every way of writing total as k
+// positive parts, in order, until fn returns false. It reports whether the
+// enumeration ran to completion.
+func forEachComposition(total, k int, fn func(parts []int) bool) bool {
+  	parts := make([]int, k)
+  	var rec func(i, left int) bool
+  	rec = func(i, left int) bool {
+      		if i == k-1 {
+            			if left < 1 {
+                    				return true
+                    			}
+            			parts[i] = left
+            			return fn(parts)
+            		}
+      		for n := 1; n <= left-(k-1-i); n++ {
+            			parts[i] = n
+            			if !rec(i+1, left-n) {
+                    				return false
+                    			}
+            		}
+      		return true
+      	}
+  	if k == 0 {
+      		return total != 0 || fn(parts)
+      	}
+  	return rec(0, total)
+  }
+
+// forEachProduct calls fn with every choice of one entry of size parts[i]
+// per position i, until fn returns false. It reports whether the
+// enumeration ran to completion.
+func forEachProduct(bySize [][]synthEntry, parts []int, fn func(children []synthEntry) bool) bool {
+  	chosen := make([]synthEntry, len(parts))
+  	var rec func(i int) bool
+  	rec = func(i int) bool {
+      		if i == len(parts) {
+            			return fn(chosen)
+            		}
+      		for _, entry := range bySize[parts[i]] {
+            			chosen[i] = entry
+            			if !rec(i + 1) {
+                    				return false
+                    			}
+            		}
+      		return true
+      	}
+  	return rec(0)
+  }
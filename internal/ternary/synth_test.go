@@ -0,0 +1,151 @@
+package ternary
+
+import (
+  	"fmt"
+  	"strings"
+  	"testing"
+  )
+
+// bindVars replaces the variables xN of x with the constants in[N]
+func bindVars(x Expr, in []Trit) Expr {
+  	if x.IsVar() {
+      		var i int
+      		fmt.Sscanf(x.Var, "x%d", &i)
+      		return Leaf(in[i])
+      	}
+  	kids := make([]Expr, len(x.Children))
+  	for i, c := range x.Children {
+      		kids[i] = bindVars(c, in)
+      	}
+  	return Expr{Rule: x.Rule, Children: kids, Value: x.Value}
+  }
+
+// ruleTable returns the truth table of rule over arity inputs
+func ruleTable(e *Engine, rule string, arity int) map[string]Trit {
+  	table := map[string]Trit{}
+  	forEachInput(arity, func(in []Trit) bool {
+            		table[InputKey(in...)] = e.Evaluate(rule, in...).Value
+            		return true
+            	})
+  	return table
+  }
+
+func TestSynthesizeRule(t *testing.T) {
+  	e := NewEngine()
+  	tests := []struct {
+      		name      string
+      		table     map[string]Trit
+      		arity     int
+      		available []string
+      		want      string // expected expression, if the search has a unique smallest answer
+      	}{
+      		{name: "XOR", table: ruleTable(e, "XOR", 2), arity: 2, available: []string{"AND", "OR", "NOT"}},
+      		{name: "EQ", table: ruleTable(e, "EQ", 2), arity: 2, available: []string{"AND", "OR", "NOT"}},
+      		{name: "negation", table: ruleTable(e, "NOT", 1), arity: 1, available: []string{"AND", "NOT"}, want: "NOT(x0)"},
+      		{name: "identity", table: map[string]Trit{"TRUE": TRUE, "FALSE": FALSE, "UNKNOWN": UNKNOWN}, arity: 1, available: []string{"NOT"}, want: "x0"},
+      		{name: "constant", table: map[string]Trit{"TRUE": UNKNOWN, "FALSE": UNKNOWN}, arity: 1, want: "UNKNOWN"},
+      		{name: "don't-cares", table: map[string]Trit{"TRUE, FALSE": FALSE}, arity: 2, want: "x1"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			x, err := e.SynthesizeRule(tt.table, tt.arity, tt.available)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if tt.want != "" && x.String() != tt.want {
+                              				t.Errorf("SynthesizeRule = %v, want %s", x, tt.want)
+                              			}
+                    			forEachInput(tt.arity, func(in []Trit) bool {
+                                          				want, ok := tt.table[InputKey(in...)]
+                                          				if got := e.EvaluateExpr(bindVars(x, in)).Value; ok && got != want {
+                                                        					t.Errorf("%v at %v = %v, want %v", x, in, got, want)
+                                                        				}
+                                          				return true
+                                          			})
+                    		})
+      	}
+  }
+
+func TestSynthesizeRuleErrors(t *testing.T) {
+  	e := NewEngine()
+  	tests := []struct {
+      		name      string
+      		table     map[string]Trit
+      		arity     int
+      		available []string
+      		wantErr   string
+      	}{
+      		{name: "not expressible", table: ruleTable(e, "NOT", 1), arity: 1, available: []string{"AND", "OR"}, wantErr: "no expression"},
+      		{name: "beyond Kleene logic", table: ruleTable(e, "IMPLIES", 2), arity: 2, available: []string{"AND", "OR", "NOT"}, wantErr: "no expression of up to 9 nodes"},
+      		{name: "negative arity", table: map[string]Trit{"": TRUE}, arity: -1, wantErr: "synthesis arity -1"},
+      		{name: "arity too large", table: map[string]Trit{"": TRUE}, arity: maxEnumArity + 1, wantErr: "synthesis arity"},
+      		{name: "empty table", table: map[string]Trit{}, arity: 1, wantErr: "empty truth table"},
+      		{name: "bad key", table: map[string]Trit{"TRUE, TRUE": TRUE}, arity: 1, wantErr: "not 1-input vectors"},
+      		{name: "missing rule", table: ruleTable(e, "NOT", 1), arity: 1, available: []string{"nope"}, wantErr: "Rule 'nope' not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			x, err := e.SynthesizeRule(tt.table, tt.arity, tt.available)
+                    			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                              				t.Errorf("SynthesizeRule = %v, %v, want error %q", x, err, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestForEachComposition(t *testing.T) {
+  	tests := []struct {
+      		total, k int
+      		want     string
+      	}{
+      		{3, 1, "[3]"},
+      		{3, 2, "[1 2][2 1]"},
+      		{4, 3, "[1 1 2][1 2 1][2 1 1]"},
+      		{2, 3, ""},
+      		{0, 0, "[]"},
+      	}
+  	for _, tt := range tests {
+      		var b strings.Builder
+      		forEachComposition(tt.total, tt.k, func(parts []int) bool {
+                    			fmt.Fprint(&b, parts)
+                    			return true
+                    		})
+      		if b.String() != tt.want {
+            			t.Errorf("forEachComposition(%d, %d) = %s, want %s", tt.total, tt.k, b.String(), tt.want)
+            		}
+      	}
+  }
+
+func TestSynthesizeRuleTabulatesOnce(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		arity     int // of the counted rule, 0 for variadic
+      		wantCalls int // input vectors tabulated
+      	}{
+      		{name: "variadic", arity: 0, wantCalls: 3 + 9},
+      		{name: "binary", arity: 2, wantCalls: 9},
+      		{name: "too wide to fit", arity: maxSynthSize, wantCalls: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			calls := 0
+                    			e.AddRule("COUNTED", TernaryRule{Arity: tt.arity, Evaluate: func(in ...Trit) Trit {
+                                                        				calls++
+                                                        				v := TRUE
+                                                        				for _, x := range in {
+                                                                        					v = Min(v, x)
+                                                                        				}
+                                                        				return v
+                                                        			}})
+                    			// IMPLIES is out of reach, so the search runs to its size bound
+                    			_, err := e.SynthesizeRule(ruleTable(e, "IMPLIES", 2), 2, []string{"COUNTED", "OR", "NOT"})
+                    			if err == nil {
+                              				t.Fatal("SynthesizeRule matched IMPLIES, want no expression")
+                              			}
+                    			if calls != tt.wantCalls {
+                              				t.Errorf("COUNTED called %d times, want %d", calls, tt.wantCalls)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,63 @@
+// Package ternaryprom exports ternary engine metrics to Prometheus. It is
+// kept apart from package ternary so that only programs importing it
+// depend on the Prometheus client.
+package ternaryprom
+
+import (
+  	"github.com/biodoia/NEXUS-SWARM/internal/ternary"
+  	"github.com/prometheus/client_golang/prometheus"
+  )
+
+var (
+  	evaluationsDesc = prometheus.NewDesc(
+      		"nexus_ternary_evaluations_total",
+      		"Total evaluations performed by the ternary engine.",
+      		nil, nil,
+      	)
+  	invocationsDesc = prometheus.NewDesc(
+      		"nexus_ternary_rule_invocations_total",
+      		"Decisions produced per ternary rule.",
+      		[]string{"rule"}, nil,
+      	)
+  	confidenceDesc = prometheus.NewDesc(
+      		"nexus_ternary_confidence",
+      		"Confidence of ternary engine decisions.",
+      		nil, nil,
+      	)
+  )
+
+// Collector is a prometheus.Collector reading an engine's counters on
+// every scrape
+type Collector struct {
+  	engine *ternary.Engine
+  }
+
+// NewCollector returns a Collector for e. Register it with
+// prometheus.MustRegister to expose the nexus_ternary_* metrics.
+func NewCollector(e *ternary.Engine) *Collector {
+  	return &Collector{engine: e}
+  }
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+  	ch <- evaluationsDesc
+  	ch <- invocationsDesc
+  	ch <- confidenceDesc
+  }
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+  	m := c.engine.Metrics()
+
+  	ch <- prometheus.MustNewConstMetric(evaluationsDesc, prometheus.CounterValue, float64(m.Evaluations))
+  	for rule, n := range m.RuleInvocations {
+      		ch <- prometheus.MustNewConstMetric(invocationsDesc, prometheus.CounterValue, float64(n), rule)
+      	}
+
+  	bounds := ternary.ConfidenceBuckets()
+  	buckets := make(map[float64]uint64, len(bounds))
+  	for i, bound := range bounds {
+      		buckets[bound] = m.ConfidenceCounts[i]
+      	}
+  	ch <- prometheus.MustNewConstHistogram(confidenceDesc, m.ConfidenceCount, m.ConfidenceSum, buckets)
+  }
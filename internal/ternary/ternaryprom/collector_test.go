@@ -0,0 +1,84 @@
+package ternaryprom
+
+import (
+  	"strings"
+  	"testing"
+
+  	"github.com/biodoia/NEXUS-SWARM/internal/ternary"
+  	"github.com/prometheus/client_golang/prometheus"
+  	"github.com/prometheus/client_golang/prometheus/testutil"
+  )
+
+const counterHeader = `
+# HELP nexus_ternary_evaluations_total Total evaluations performed by the ternary engine.
+# TYPE nexus_ternary_evaluations_total counter
+`
+
+const invocationsHeader = `# HELP nexus_ternary_rule_invocations_total Decisions produced per ternary rule.
+# TYPE nexus_ternary_rule_invocations_total counter
+`
+
+func TestCollector(t *testing.T) {
+  	e := ternary.NewEngine()
+  	e.Evaluate("AND", ternary.TRUE, ternary.TRUE)
+  	e.Evaluate("AND", ternary.TRUE, ternary.FALSE)
+  	e.Evaluate("OR", ternary.TRUE)
+  	reg := prometheus.NewPedanticRegistry()
+  	reg.MustRegister(NewCollector(e))
+
+  	want := counterHeader + "nexus_ternary_evaluations_total 3\n" + invocationsHeader +
+  		`nexus_ternary_rule_invocations_total{rule="AND"} 2
+nexus_ternary_rule_invocations_total{rule="OR"} 1
+`
+  	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "nexus_ternary_evaluations_total", "nexus_ternary_rule_invocations_total"); err != nil {
+      		t.Fatal(err)
+      	}
+  	n, err := testutil.GatherAndCount(reg, "nexus_ternary_confidence")
+  	if err != nil || n != 1 {
+      		t.Fatal(n, err)
+      	}
+  	m := e.Metrics()
+  	if m.ConfidenceCount != 3 || m.ConfidenceCounts[len(m.ConfidenceCounts)-1] != 3 {
+      		t.Fatal(m)
+      	}
+  }
+
+func TestCollectorCountersNeverDecrease(t *testing.T) {
+  	e := ternary.NewEngine()
+  	e.Evaluate("AND", ternary.TRUE)
+  	snap, err := e.Snapshot()
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Evaluate("AND", ternary.TRUE)
+  	e.Evaluate("AND", ternary.FALSE)
+
+  	steps := []struct {
+      		name        string
+      		act         func()
+      		evaluations string
+      		and         string
+      	}{
+      		{"before reset", func() {}, "3", "3"},
+      		{"ResetStats", e.ResetStats, "3", "3"},
+      		{"Restore to earlier count", func() {
+                    			if err := e.Restore(snap); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    		}, "3", "3"},
+      		{"after more evaluations", func() { e.Evaluate("AND", ternary.TRUE) }, "4", "4"},
+      	}
+  	reg := prometheus.NewPedanticRegistry()
+  	reg.MustRegister(NewCollector(e))
+  	for _, s := range steps {
+      		s.act()
+      		want := counterHeader + "nexus_ternary_evaluations_total " + s.evaluations + "\n" + invocationsHeader +
+      			`nexus_ternary_rule_invocations_total{rule="AND"} ` + s.and + "\n"
+      		if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "nexus_ternary_evaluations_total", "nexus_ternary_rule_invocations_total"); err != nil {
+            			t.Errorf("%s: %v", s.name, err)
+            		}
+      	}
+  	if st := e.RuleStats()["AND"]; st.Invocations != 1 {
+      		t.Errorf("RuleStats after ResetStats = %+v, want only the later decision", st)
+      	}
+  }
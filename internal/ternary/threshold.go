@@ -0,0 +1,72 @@
+package ternary
+
+import "fmt"
+
+// Threshold returns TRUE if at least trueFraction of inputs are TRUE, else
+// FALSE if at least falseFraction are FALSE, else UNKNOWN. UNKNOWN inputs
+// count toward the denominator, and no inputs give UNKNOWN. Threshold
+// returns an error if either fraction is outside [0, 1].
+func Threshold(inputs []Trit, trueFraction, falseFraction float64) (Trit, error) {
+  	if err := checkFractions(trueFraction, falseFraction); err != nil {
+      		return UNKNOWN, err
+      	}
+  	return threshold(inputs, trueFraction, falseFraction), nil
+  }
+
+// ThresholdRule returns a rule named name that evaluates Threshold with the
+// given fractions, for registering with AddRule:
+//
+//	rule, err := ThresholdRule("SUPERMAJORITY", 0.6, 0.6, 1.0)
+//	if err != nil { ... }
+//	e.AddRule("SUPERMAJORITY", rule)
+//
+// It returns an error if either fraction is outside [0, 1].
+func ThresholdRule(name string, trueFraction, falseFraction, weight float64) (TernaryRule, error) {
+  	if err := checkFractions(trueFraction, falseFraction); err != nil {
+      		return TernaryRule{}, err
+      	}
+  	return TernaryRule{
+      		Name: name,
+      		Evaluate: func(inputs ...Trit) Trit {
+            			return threshold(inputs, trueFraction, falseFraction)
+            		},
+      		Weight: weight,
+      	}, nil
+  }
+
+// threshold is Threshold without the fraction checks
+func threshold(inputs []Trit, trueFraction, falseFraction float64) Trit {
+  	if len(inputs) == 0 {
+      		return UNKNOWN
+      	}
+
+  	trueCount, falseCount := 0, 0
+  	for _, inp := range inputs {
+      		switch inp {
+            		case TRUE:
+            			trueCount++
+            		case FALSE:
+            			falseCount++
+            		}
+      	}
+  	n := float64(len(inputs))
+  	switch {
+      	case float64(trueCount)/n >= trueFraction:
+      		return TRUE
+      	case float64(falseCount)/n >= falseFraction:
+      		return FALSE
+      	default:
+      		return UNKNOWN
+      	}
+  }
+
+// checkFractions reports an error unless both fractions are in [0, 1]
+func checkFractions(trueFraction, falseFraction float64) error {
+  	if !(trueFraction >= 0 && trueFraction <= 1) {
+      		return fmt.Errorf("ternary: true fraction %v outside [0, 1]", trueFraction)
+      	}
+  	if !(falseFraction >= 0 && falseFraction <= 1) {
+      		return fmt.Errorf("ternary: false fraction %v outside [0, 1]", falseFraction)
+      	}
+  	return nil
+  }
@@ -0,0 +1,89 @@
+package ternary
+
+import (
+  	"math"
+  	"strings"
+  	"testing"
+  )
+
+func TestThreshold(t *testing.T) {
+  	// 3 of 5 TRUE is exactly 60%, 1 of 5 FALSE is 20%
+  	in := []Trit{TRUE, TRUE, TRUE, FALSE, UNKNOWN}
+  	tests := []struct {
+      		name                string
+      		inputs              []Trit
+      		trueFrac, falseFrac float64
+      		want                Trit
+      	}{
+      		{name: "true fraction met exactly", inputs: in, trueFrac: 0.6, falseFrac: 0.6, want: TRUE},
+      		{name: "true fraction just missed", inputs: in, trueFrac: math.Nextafter(0.6, 1), falseFrac: 0.6, want: UNKNOWN},
+      		{name: "false fraction met exactly", inputs: in, trueFrac: 0.7, falseFrac: 0.2, want: FALSE},
+      		{name: "true checked before false", inputs: in, trueFrac: 0.6, falseFrac: 0.2, want: TRUE},
+      		{name: "neither", inputs: in, trueFrac: 0.7, falseFrac: 0.7, want: UNKNOWN},
+      		// without the UNKNOWNs TRUE would be 2 of 2
+      		{name: "unknowns in the denominator", inputs: []Trit{TRUE, TRUE, UNKNOWN, UNKNOWN}, trueFrac: 0.6, falseFrac: 1, want: UNKNOWN},
+      		{name: "all unknown", inputs: []Trit{UNKNOWN, UNKNOWN}, trueFrac: 0.5, falseFrac: 0.5, want: UNKNOWN},
+      		{name: "zero fraction always met", inputs: []Trit{FALSE}, trueFrac: 0, falseFrac: 1, want: TRUE},
+      		{name: "full fraction", inputs: []Trit{FALSE, FALSE}, trueFrac: 1, falseFrac: 1, want: FALSE},
+      		{name: "no inputs", trueFrac: 0, falseFrac: 0, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := Threshold(tt.inputs, tt.trueFrac, tt.falseFrac)
+                    			if err != nil || got != tt.want {
+                              				t.Errorf("Threshold(%v, %v, %v) = %v, %v, want %v", tt.inputs, tt.trueFrac, tt.falseFrac, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestThresholdFractionErrors(t *testing.T) {
+  	tests := []struct {
+      		trueFrac, falseFrac float64
+      		wantErr             string
+      	}{
+      		{trueFrac: 1.5, falseFrac: 0, wantErr: "true fraction 1.5 outside [0, 1]"},
+      		{trueFrac: -0.1, falseFrac: 0, wantErr: "true fraction -0.1 outside [0, 1]"},
+      		{trueFrac: 0.5, falseFrac: -0.1, wantErr: "false fraction -0.1 outside [0, 1]"},
+      		{trueFrac: 0.5, falseFrac: math.NaN(), wantErr: "false fraction NaN outside [0, 1]"},
+      	}
+  	for _, tt := range tests {
+      		if _, err := Threshold([]Trit{TRUE}, tt.trueFrac, tt.falseFrac); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+            			t.Errorf("Threshold(%v, %v) error = %v, want %q", tt.trueFrac, tt.falseFrac, err, tt.wantErr)
+            		}
+      		if _, err := ThresholdRule("BAD", tt.trueFrac, tt.falseFrac, 1); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+            			t.Errorf("ThresholdRule(%v, %v) error = %v, want %q", tt.trueFrac, tt.falseFrac, err, tt.wantErr)
+            		}
+      	}
+  }
+
+func TestThresholdRule(t *testing.T) {
+  	super, err := ThresholdRule("SUPERMAJORITY", 0.6, 0.6, 1.5)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	simple, err := ThresholdRule("SIMPLE", 0.5, 0.5, 1)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	e := NewEngine()
+  	e.AddRule("SUPERMAJORITY", super)
+  	e.AddRule("SIMPLE", simple)
+  	tests := []struct {
+      		rule     string
+      		inputs   []Trit
+      		want     Trit
+      		wantConf float64
+      	}{
+      		{rule: "SUPERMAJORITY", inputs: []Trit{TRUE, TRUE, TRUE, FALSE, UNKNOWN}, want: TRUE, wantConf: 1},
+      		{rule: "SUPERMAJORITY", inputs: []Trit{TRUE, TRUE, FALSE, FALSE}, want: UNKNOWN, wantConf: 0.75},
+      		{rule: "SIMPLE", inputs: []Trit{TRUE, TRUE, FALSE, FALSE}, want: TRUE, wantConf: 1},
+      		{rule: "SIMPLE", inputs: []Trit{FALSE, FALSE, UNKNOWN}, want: FALSE, wantConf: 0},
+      	}
+  	for _, tt := range tests {
+      		r := e.Evaluate(tt.rule, tt.inputs...)
+      		if r.Value != tt.want || r.Confidence != tt.wantConf {
+            			t.Errorf("%s%v = %v (%v), want %v (%v)", tt.rule, tt.inputs, r.Value, r.Confidence, tt.want, tt.wantConf)
+            		}
+      	}
+  }
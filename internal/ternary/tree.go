@@ -0,0 +1,88 @@
+package ternary
+
+import "fmt"
+
+// DefaultMaxTreeDepth is the EvaluateTree depth limit of a new engine
+const DefaultMaxTreeDepth = 64
+
+// RuleNode is a node of a rule tree for EvaluateTree. Its rule is applied
+// to the values of its Children followed by its literal Inputs.
+type RuleNode struct {
+  	RuleName string
+  	Inputs   []Trit
+  	Children []*RuleNode
+  }
+
+// SetMaxTreeDepth sets the depth beyond which EvaluateTree gives up. A
+// limit below 1 restores DefaultMaxTreeDepth.
+func (e *Engine) SetMaxTreeDepth(n int) {
+  	if n < 1 {
+      		n = DefaultMaxTreeDepth
+      	}
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.maxTreeDepth = n
+  }
+
+// EvaluateTree evaluates a rule tree bottom-up and records the root
+// decision with its Depth: 0 for a node with literal inputs only, else one
+// more than its deepest child. A tree deeper than the engine's limit (see
+// SetMaxTreeDepth), as a cyclic tree is, yields an unrecorded UNKNOWN.
+func (e *Engine) EvaluateTree(node *RuleNode) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	if node == nil {
+      		return e.failedResult("Rule tree is nil")
+      	}
+  	rule, inputs, depth, failed, ok := e.treeLocked(node, 0)
+  	if !ok {
+      		return failed
+      	}
+  	inputs, value, note, ok := e.invokeLocked(node.RuleName, rule, inputs)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+
+  	reason := withNote(fmt.Sprintf("Tree[%s] evaluated to depth %d", node.RuleName, depth), note)
+  	result := e.resultLocked(node.RuleName, rule.Weight, value, inputs, reason)
+  	result.Depth = depth
+  	return e.recordLocked(result)
+  }
+
+// treeLocked resolves the rule of node and evaluates its children, whose
+// root is level levels below the tree's. It returns the rule, its inputs
+// and node's depth. The caller must hold e.mu.
+func (e *Engine) treeLocked(node *RuleNode, level int) (TernaryRule, []Trit, int, TernaryResult, bool) {
+  	if level > e.maxTreeDepth {
+      		return TernaryRule{}, nil, 0, e.failedResult(fmt.Sprintf("Rule tree deeper than %d", e.maxTreeDepth)), false
+      	}
+  	rule, failed, ok := e.ruleLocked(node.RuleName)
+  	if !ok {
+      		return TernaryRule{}, nil, 0, failed, false
+      	}
+
+  	inputs := make([]Trit, 0, len(node.Children)+len(node.Inputs))
+  	depth := 0
+  	for _, child := range node.Children {
+      		if child == nil {
+            			return TernaryRule{}, nil, 0, e.failedResult(fmt.Sprintf("Rule tree node %s has a nil child", node.RuleName)), false
+            		}
+      		childRule, childInputs, d, failed, ok := e.treeLocked(child, level+1)
+      		if !ok {
+            			return TernaryRule{}, nil, 0, failed, false
+            		}
+      		_, v, note, ok := e.invokeLocked(child.RuleName, childRule, childInputs)
+      		if !ok {
+            			return TernaryRule{}, nil, 0, e.failedResult(note), false
+            		}
+      		inputs = append(inputs, v)
+      		if d+1 > depth {
+            			depth = d + 1
+            		}
+      	}
+  	inputs = append(inputs, node.Inputs...)
+  	return rule, inputs, depth, TernaryResult{}, true
+  }
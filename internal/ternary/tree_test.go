@@ -0,0 +1,62 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+// notChain returns depth NOT nodes nested over a TRUE input
+func notChain(depth int) *RuleNode {
+  	n := &RuleNode{RuleName: "NOT", Inputs: []Trit{TRUE}}
+  	for i := 0; i < depth; i++ {
+      		n = &RuleNode{RuleName: "NOT", Children: []*RuleNode{n}}
+      	}
+  	return n
+  }
+
+func TestEvaluateTree(t *testing.T) {
+  	leaf := &RuleNode{RuleName: "CONSENSUS", Inputs: []Trit{TRUE, TRUE, FALSE}}
+  	cycle := &RuleNode{RuleName: "AND"}
+  	cycle.Children = []*RuleNode{cycle}
+  	tests := []struct {
+      		name       string
+      		maxDepth   int
+      		tree       *RuleNode
+      		want       Trit
+      		wantDepth  int
+      		wantCount  int
+      		wantReason string
+      	}{
+      		{name: "leaf", tree: leaf, want: TRUE, wantCount: 3, wantReason: "Tree[CONSENSUS] evaluated to depth 0"},
+      		{
+            			name: "children before inputs",
+            			tree: &RuleNode{RuleName: "IMPLIES", Children: []*RuleNode{leaf}, Inputs: []Trit{FALSE}},
+            			want: FALSE, wantDepth: 1, wantCount: 2,
+            		},
+      		{
+            			name: "deepest child counts",
+            			tree: &RuleNode{RuleName: "AND", Children: []*RuleNode{{RuleName: "CONSENSUS", Children: []*RuleNode{leaf}, Inputs: []Trit{TRUE}}, leaf}},
+            			want: TRUE, wantDepth: 2, wantCount: 2,
+            		},
+      		{name: "at the limit", maxDepth: 3, tree: notChain(3), want: TRUE, wantDepth: 3, wantCount: 1},
+      		{name: "past the limit", maxDepth: 3, tree: notChain(4), want: UNKNOWN, wantReason: "Rule tree deeper than 3"},
+      		{name: "cycle", maxDepth: 5, tree: cycle, want: UNKNOWN, wantReason: "Rule tree deeper than 5"},
+      		{name: "limit reset", maxDepth: -1, tree: notChain(DefaultMaxTreeDepth + 1), want: UNKNOWN, wantReason: "deeper than 64"},
+      		{name: "nil tree", want: UNKNOWN, wantReason: "Rule tree is nil"},
+      		{name: "nil child", tree: &RuleNode{RuleName: "AND", Children: []*RuleNode{nil}}, want: UNKNOWN, wantReason: "Rule tree node AND has a nil child"},
+      		{name: "missing rule", tree: &RuleNode{RuleName: "AND", Children: []*RuleNode{{RuleName: "nope"}}}, want: UNKNOWN, wantReason: "Rule 'nope' not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if tt.maxDepth != 0 {
+                              				e.SetMaxTreeDepth(tt.maxDepth)
+                              			}
+                    			r := e.EvaluateTree(tt.tree)
+                    			if r.Value != tt.want || r.Depth != tt.wantDepth || r.InputCount != tt.wantCount || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateTree = %v depth %d over %d (%q), want %v depth %d over %d containing %q",
+                                          					r.Value, r.Depth, r.InputCount, r.Reason, tt.want, tt.wantDepth, tt.wantCount, tt.wantReason)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,57 @@
+package ternary
+
+import "fmt"
+
+// Trend classifies the direction of series by its least-squares slope per
+// step: TRUE when the slope exceeds noiseTol, FALSE when it is below
+// -noiseTol, and UNKNOWN inside that flat band or with fewer than two
+// points. Trend returns an error if noiseTol is negative.
+func Trend(series []float64, noiseTol float64) (Trit, error) {
+  	if !(noiseTol >= 0) {
+      		return UNKNOWN, fmt.Errorf("ternary: Trend tolerance %v is negative", noiseTol)
+      	}
+  	n := len(series)
+  	if n < 2 {
+      		return UNKNOWN, nil
+      	}
+
+  	// x runs 0..n-1, so its mean is (n-1)/2
+  	meanX := float64(n-1) / 2
+  	meanY := 0.0
+  	for _, y := range series {
+      		meanY += y
+      	}
+  	meanY /= float64(n)
+
+  	var cov, varX float64
+  	for i, y := range series {
+      		dx := float64(i) - meanX
+      		cov += dx * (y - meanY)
+      		varX += dx * dx
+      	}
+  	switch slope := cov / varX; {
+      	case slope > noiseTol:
+      		return TRUE, nil
+      	case slope < -noiseTol:
+      		return FALSE, nil
+      	default:
+      		return UNKNOWN, nil
+      	}
+  }
+
+// HistoryTrend returns Trend over the confidences of the last n retained
+// decisions, or of all of them when n is not positive
+func (e *Engine) HistoryTrend(n int, noiseTol float64) (Trit, error) {
+  	e.mu.RLock()
+  	history := e.historyLocked()
+  	if n > 0 && n < len(history) {
+      		history = history[len(history)-n:]
+      	}
+  	series := make([]float64, len(history))
+  	for i, r := range history {
+      		series[i] = r.Confidence
+      	}
+  	e.mu.RUnlock()
+
+  	return Trend(series, noiseTol)
+  }
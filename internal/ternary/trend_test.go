@@ -0,0 +1,77 @@
+package ternary
+
+import (
+  	"math"
+  	"strings"
+  	"testing"
+  )
+
+func TestTrend(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		series   []float64
+      		noiseTol float64
+      		want     Trit
+      	}{
+      		{name: "rising", series: []float64{0.1, 0.2, 0.25, 0.4}, noiseTol: 0.01, want: TRUE},
+      		{name: "falling", series: []float64{0.9, 0.7, 0.75, 0.5}, noiseTol: 0.01, want: FALSE},
+      		{name: "flat", series: []float64{0.5, 0.5, 0.5}, noiseTol: 0, want: UNKNOWN},
+      		{name: "noisy flat", series: []float64{0.5, 0.52, 0.49, 0.5}, noiseTol: 0.05, want: UNKNOWN},
+      		{name: "rise within tolerance", series: []float64{0.1, 0.2, 0.3}, noiseTol: 0.2, want: UNKNOWN},
+      		// the slope of 0, 0.25, 0.5 is exactly 0.25
+      		{name: "slope at tolerance is flat", series: []float64{0, 0.25, 0.5}, noiseTol: 0.25, want: UNKNOWN},
+      		{name: "slope above tolerance", series: []float64{0, 0.25, 0.5}, noiseTol: math.Nextafter(0.25, 0), want: TRUE},
+      		{name: "fall at tolerance is flat", series: []float64{0.5, 0.25, 0}, noiseTol: 0.25, want: UNKNOWN},
+      		{name: "fall beyond tolerance", series: []float64{0.5, 0.25, 0}, noiseTol: math.Nextafter(0.25, 0), want: FALSE},
+      		{name: "outlier outweighed by the fit", series: []float64{0.1, 0.9, 0.3, 0.4, 0.5}, noiseTol: 0.01, want: TRUE},
+      		{name: "one point", series: []float64{0.5}, want: UNKNOWN},
+      		{name: "empty", want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			got, err := Trend(tt.series, tt.noiseTol)
+                    			if err != nil || got != tt.want {
+                              				t.Errorf("Trend(%v, %v) = %v, %v, want %v", tt.series, tt.noiseTol, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestTrendToleranceErrors(t *testing.T) {
+  	for _, tol := range []float64{-0.1, math.NaN(), math.Inf(-1)} {
+      		if _, err := Trend([]float64{0.1, 0.2}, tol); err == nil || !strings.Contains(err.Error(), "Trend tolerance") {
+            			t.Errorf("Trend tolerance %v: error = %v, want a tolerance error", tol, err)
+            		}
+      		if _, err := NewEngine().HistoryTrend(0, tol); err == nil {
+            			t.Errorf("HistoryTrend tolerance %v: want error", tol)
+            		}
+      	}
+  }
+
+func TestHistoryTrend(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		values []Trit // OR over each, so confidences 0, 0.5 or 1
+      		n      int
+      		want   Trit
+      	}{
+      		{name: "rising", values: []Trit{FALSE, UNKNOWN, TRUE}, want: TRUE},
+      		{name: "falling", values: []Trit{TRUE, UNKNOWN, FALSE}, want: FALSE},
+      		{name: "flat", values: []Trit{UNKNOWN, UNKNOWN, UNKNOWN}, want: UNKNOWN},
+      		{name: "last n only", values: []Trit{FALSE, TRUE, TRUE, UNKNOWN, FALSE}, n: 3, want: FALSE},
+      		{name: "n beyond history", values: []Trit{FALSE, TRUE}, n: 10, want: TRUE},
+      		{name: "window of one", values: []Trit{FALSE, TRUE}, n: 1, want: UNKNOWN},
+      		{name: "no decisions", want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			for _, v := range tt.values {
+                              				e.Evaluate("OR", v)
+                              			}
+                    			if got, err := e.HistoryTrend(tt.n, 0.1); err != nil || got != tt.want {
+                              				t.Errorf("HistoryTrend(%d) = %v, %v, want %v", tt.n, got, err, tt.want)
+                              			}
+                    		})
+      	}
+  }
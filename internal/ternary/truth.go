@@ -0,0 +1,67 @@
+package ternary
+
+import (
+  	"fmt"
+  	"strings"
+  )
+
+// The truth table caches determinations that are expensive to make, such
+// as a network check, so that Cacheable rules can reuse them. Its keys
+// follow one contract: TruthKey(rule, inputs...) is the key under which
+// Evaluate looks up a Cacheable rule's value for those inputs. Other keys
+// may be stored freely but are only read back through LookupTruth. The
+// table is never evicted automatically; use ClearTruthTable to bound it.
+
+// TruthKey returns the truth table key of ruleName over inputs, in the call
+// form of Expr.String, e.g. "AND(TRUE, UNKNOWN)"
+func TruthKey(ruleName string, inputs ...Trit) string {
+  	names := make([]string, len(inputs))
+  	for i, inp := range inputs {
+      		names[i] = tritName(inp)
+      	}
+  	return ruleName + "(" + strings.Join(names, ", ") + ")"
+  }
+
+// Memoize stores value in the truth table under key
+func (e *Engine) Memoize(key string, value Trit) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.truthTable[key] = value
+  }
+
+// LookupTruth returns the truth table value stored under key, and whether
+// there is one
+func (e *Engine) LookupTruth(key string) (Trit, bool) {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+  	v, ok := e.truthTable[key]
+  	return v, ok
+  }
+
+// ClearTruthTable removes every truth table entry and returns how many
+// there were
+func (e *Engine) ClearTruthTable() int {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	n := len(e.truthTable)
+  	clear(e.truthTable)
+  	return n
+  }
+
+// ruleValueLocked computes rule over inputs, answering from the truth table
+// for Cacheable rules; every evaluation path calls rules through it. Along
+// with the value it returns a note explaining a value the rule itself did
+// not compute, one from the truth table or forced to UNKNOWN by an arity
+// mismatch, or "" otherwise. The caller must hold e.mu, for reading at least.
+func (e *Engine) ruleValueLocked(ruleName string, rule TernaryRule, inputs []Trit) (Trit, string) {
+  	if rule.Cacheable {
+      		key := TruthKey(ruleName, inputs...)
+      		if v, ok := e.truthTable[key]; ok {
+            			return v, fmt.Sprintf("Rule[%s] from truth table %s", ruleName, key)
+            		}
+      	}
+  	if rule.Arity > 0 && len(inputs) != rule.Arity {
+      		return UNKNOWN, fmt.Sprintf("Rule[%s] takes %d inputs, got %d", ruleName, rule.Arity, len(inputs))
+      	}
+  	return e.computeLocked(rule, inputs), ""
+  }
@@ -0,0 +1,135 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestTruthTable(t *testing.T) {
+  	e := NewEngine()
+  	calls := 0
+  	e.AddRule("PING", TernaryRule{Name: "PING", Weight: 1, Cacheable: true, Evaluate: func(in ...Trit) Trit {
+                    		calls++
+                    		return UNKNOWN
+                    	}})
+  	if e.Evaluate("PING", TRUE).Value != UNKNOWN || calls != 1 {
+      		t.Fatalf("calls = %d", calls)
+      	}
+  	if TruthKey("PING", TRUE, UNKNOWN) != "PING(TRUE, UNKNOWN)" || TruthKey("X") != "X()" {
+      		t.Fatal(TruthKey("PING", TRUE, UNKNOWN))
+      	}
+  	e.Memoize(TruthKey("PING", TRUE), TRUE)
+  	r := e.Evaluate("PING", TRUE)
+  	if r.Value != TRUE || calls != 1 || !strings.Contains(r.Reason, "truth table") {
+      		t.Fatal(r, calls)
+      	}
+  	if v, ok := e.LookupTruth("PING(TRUE)"); !ok || v != TRUE {
+      		t.Fatal(v)
+      	}
+  	e.Memoize(TruthKey("AND", TRUE), FALSE)
+  	if e.Evaluate("AND", TRUE).Value != TRUE {
+      		t.Fatal("non-cacheable rule answered from the truth table")
+      	}
+  	if e.ClearTruthTable() != 2 {
+      		t.Fatal("clear")
+      	}
+  	if _, ok := e.LookupTruth("PING(TRUE)"); ok || e.Evaluate("PING", TRUE).Value != UNKNOWN || calls != 2 {
+      		t.Fatal("cleared")
+      	}
+  }
+
+// evaluationPaths runs rule over inputs through each engine entry point
+// that invokes a rule and returns the resulting value and Reason
+var evaluationPaths = []struct {
+  	name string
+  	run  func(e *Engine, rule string, inputs []Trit) (Trit, string)
+  }{
+  	{"Evaluate", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.Evaluate(rule, in...)
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateMeta", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateMeta(map[string]string{"k": "v"}, rule, in...)
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateConfidence", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateConfidence(rule, in...)
+            		return r.Value, r.Reason
+            	}},
+  	{"ConcurrentEvaluate", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.ConcurrentEvaluate([]EvalRequest{{Rule: rule, Inputs: in}})[0]
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateTree", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateTree(&RuleNode{RuleName: rule, Inputs: in})
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateTree child", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateTree(&RuleNode{RuleName: "NOT", Children: []*RuleNode{{RuleName: rule, Inputs: in}}})
+            		return -r.Value, r.Reason
+            	}},
+  	{"EvaluateExpr", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		args := make([]Expr, len(in))
+            		for i, v := range in {
+                    			args[i] = Leaf(v)
+                    		}
+            		r := e.EvaluateExpr(Call(rule, args...))
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateDAG", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		children := make([]*Node, len(in))
+            		for i, v := range in {
+                    			children[i] = &Node{Value: v}
+                    		}
+            		r := e.EvaluateDAG(&Node{Rule: rule, Children: children})
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateIf", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateIf("AND", []Trit{TRUE}, rule, in)
+            		return r.Value, r.Reason
+            	}},
+  	{"EvaluateWithFallback", func(e *Engine, rule string, in []Trit) (Trit, string) {
+            		r := e.EvaluateWithFallback([]string{rule}, in...)
+            		return r.Value, r.Reason
+            	}},
+  }
+
+func TestTruthTableOnEveryPath(t *testing.T) {
+  	for _, p := range evaluationPaths {
+      		t.Run(p.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			calls := 0
+                    			e.AddRule("PING", TernaryRule{Name: "PING", Weight: 1, Cacheable: true, Evaluate: func(in ...Trit) Trit {
+                                                        				calls++
+                                                        				return FALSE
+                                                        			}})
+                    			e.Memoize(TruthKey("PING", TRUE, UNKNOWN), TRUE)
+                    			v, reason := p.run(e, "PING", []Trit{TRUE, UNKNOWN})
+                    			if v != TRUE || calls != 0 {
+                              				t.Errorf("value = %v after %d calls, want TRUE from the truth table", v, calls)
+                              			}
+                    			if p.name != "EvaluateTree child" && !strings.Contains(reason, "from truth table PING(TRUE, UNKNOWN)") {
+                              				t.Errorf("reason = %q", reason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestArityReasonOnEveryPath(t *testing.T) {
+  	for _, p := range evaluationPaths {
+      		if p.name == "EvaluateWithFallback" {
+            			continue // an UNKNOWN value falls through to the next rule
+            		}
+      		t.Run(p.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("PAIR", TernaryRule{Name: "PAIR", Weight: 1, Arity: 2, Evaluate: func(in ...Trit) Trit { return TRUE }})
+                    			v, reason := p.run(e, "PAIR", []Trit{TRUE, TRUE, TRUE})
+                    			if v != UNKNOWN {
+                              				t.Errorf("value = %v, want UNKNOWN", v)
+                              			}
+                    			if p.name != "EvaluateTree child" && !strings.Contains(reason, "Rule[PAIR] takes 2 inputs, got 3") {
+                              				t.Errorf("reason = %q", reason)
+                              			}
+                    		})
+      	}
+  }
@@ -0,0 +1,60 @@
+package ternary
+
+import "fmt"
+
+// TypedRuleSpec is a rule over inputs of some domain type, built with
+// TypedRule and registered with Engine.AddTypedRule
+type TypedRuleSpec interface {
+  	typedRuleName() string
+  }
+
+// typedRule is a rule whose inputs are of type T
+type typedRule[T any] struct {
+  	name   string
+  	fn     func(inputs ...T) Trit
+  	weight float64
+  }
+
+func (r typedRule[T]) typedRuleName() string { return r.name }
+
+// TypedRule builds a rule that takes inputs of type T and produces a trit
+func TypedRule[T any](name string, fn func(inputs ...T) Trit, weight float64) TypedRuleSpec {
+  	return typedRule[T]{name: name, fn: fn, weight: weight}
+  }
+
+// AddTypedRule registers a typed rule. Typed rules live in a registry of
+// their own: they are evaluated only with EvaluateTyped and neither shadow
+// nor appear among the trit rules managed by Rules and SetRuleEnabled.
+func (e *Engine) AddTypedRule(rule TypedRuleSpec) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.typed[rule.typedRuleName()] = rule
+  }
+
+// EvaluateTyped evaluates the typed rule name over inputs and records the
+// decision. The input type is checked at run time, not compile time: if
+// the rule was built for a type other than T the result is an unrecorded
+// UNKNOWN saying so, just as for an unknown or disabled rule. Typed inputs
+// are never captured.
+func EvaluateTyped[T any](e *Engine, name string, inputs ...T) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	spec, ok := e.typed[name]
+  	if !ok {
+      		return e.ruleNotFound(name)
+      	}
+  	rule, ok := spec.(typedRule[T])
+  	if !ok {
+      		var zero T
+      		return e.failedResult(fmt.Sprintf("Rule '%s' does not take %T inputs", name, zero))
+      	}
+
+  	value := rule.fn(inputs...)
+  	reason := fmt.Sprintf("Rule[%s] evaluated %d typed inputs", name, len(inputs))
+  	result := e.resultLocked(name, rule.weight, value, nil, reason)
+  	result.InputCount = len(inputs)
+  	return e.recordLocked(result)
+  }
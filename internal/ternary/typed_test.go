@@ -0,0 +1,87 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+// reading is a sensor sample for the typed rule tests
+type reading struct {
+  	Sensor string
+  	Value  float64
+  }
+
+// hotRule is TRUE if any reading is above 30
+var hotRule = TypedRule("HOT", func(rs ...reading) Trit {
+      	if len(rs) == 0 {
+            		return UNKNOWN
+            	}
+      	for _, r := range rs {
+            		if r.Value > 30 {
+                    			return TRUE
+                    		}
+            	}
+      	return FALSE
+      }, 0.5)
+
+func TestEvaluateTyped(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		readings []reading
+      		want     Trit
+      		wantConf float64
+      	}{
+      		{name: "hot", readings: []reading{{"a", 20}, {"b", 35}}, want: TRUE, wantConf: 0.5},
+      		{name: "cold", readings: []reading{{"a", 20}, {"b", 30}}, want: FALSE, wantConf: 0},
+      		{name: "no readings", want: UNKNOWN, wantConf: 0.25},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(CaptureInputs())
+                    			e.AddTypedRule(hotRule)
+                    			r := EvaluateTyped(e, "HOT", tt.readings...)
+                    			if r.Value != tt.want || r.Confidence != tt.wantConf || r.Rule != "HOT" || r.InputCount != len(tt.readings) || r.Inputs != nil {
+                              				t.Errorf("EvaluateTyped = %+v, want %v conf %v over %d inputs", r, tt.want, tt.wantConf, len(tt.readings))
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{RuleName: "HOT"})); n != 1 {
+                              				t.Errorf("recorded %d decisions, want 1", n)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateTypedErrors(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		eval       func(e *Engine) TernaryResult
+      		wantReason string
+      	}{
+      		{name: "wrong type", eval: func(e *Engine) TernaryResult { return EvaluateTyped(e, "HOT", 1, 2) }, wantReason: "Rule 'HOT' does not take int inputs"},
+      		{name: "missing rule", eval: func(e *Engine) TernaryResult { return EvaluateTyped[reading](e, "COLD") }, wantReason: "Rule 'COLD' not found"},
+      		{name: "trit rule", eval: func(e *Engine) TernaryResult { return EvaluateTyped(e, "AND", TRUE) }, wantReason: "Rule 'AND' not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddTypedRule(hotRule)
+                    			r := tt.eval(e)
+                    			if r.Value != UNKNOWN || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateTyped = %v %q, want UNKNOWN containing %q", r.Value, r.Reason, tt.wantReason)
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{})); n != 0 {
+                              				t.Errorf("recorded %d decisions, want 0", n)
+                              			}
+                    		})
+      	}
+  }
+
+func TestTypedRuleSeparateRegistry(t *testing.T) {
+  	e := NewEngine()
+  	e.AddTypedRule(TypedRule("AND", func(rs ...reading) Trit { return FALSE }, 1))
+  	if r := e.Evaluate("AND", TRUE); r.Value != TRUE {
+      		t.Errorf("typed AND shadowed the trit rule: AND(TRUE) = %v", r.Value)
+      	}
+  	if r := EvaluateTyped(e, "AND", reading{}); r.Value != FALSE {
+      		t.Errorf("EvaluateTyped(AND) = %v, want FALSE", r.Value)
+      	}
+  }
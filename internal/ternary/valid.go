@@ -0,0 +1,109 @@
+package ternary
+
+import "fmt"
+
+// IsValid reports whether t is TRUE, FALSE or UNKNOWN. Trit is an int8, so
+// other values can be constructed; String shows them as "? INVALID" and
+// the operators would mistreat them, Max ranking Trit(42) above TRUE.
+func IsValid(t Trit) bool {
+  	return t >= FALSE && t <= TRUE
+  }
+
+// InvalidInputs is the policy for invalid input trits, see
+// SetInvalidInputs
+type InvalidInputs int
+
+const (
+  	// RejectInvalid fails the evaluation with an unrecorded UNKNOWN result
+  	RejectInvalid InvalidInputs = iota
+  	// CoerceInvalid evaluates with every invalid input replaced by UNKNOWN
+  	CoerceInvalid
+  )
+
+// SetInvalidInputs sets how Evaluate and its variants treat input trits
+// that are not IsValid. By default, RejectInvalid, such an evaluation
+// returns an UNKNOWN result naming the invalid input, like an unknown rule
+// does, and records nothing.
+func (e *Engine) SetInvalidInputs(p InvalidInputs) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.invalidMode = p
+  }
+
+// sanitizeLocked applies the invalid input policy to inputs, returning the
+// inputs to evaluate or, when they are rejected, false and the reason. The
+// inputs are copied before being changed. The caller must hold e.mu, for
+// reading at least.
+func (e *Engine) sanitizeLocked(ruleName string, inputs []Trit) ([]Trit, string, bool) {
+  	for i, inp := range inputs {
+      		if IsValid(inp) {
+            			continue
+            		}
+      		if e.invalidMode != CoerceInvalid {
+            			return nil, invalidInputReason(ruleName, inp, i), false
+            		}
+
+      		coerced := append([]Trit(nil), inputs...)
+      		for j := i; j < len(coerced); j++ {
+            			if !IsValid(coerced[j]) {
+                    				coerced[j] = UNKNOWN
+                    			}
+            		}
+      		return coerced, "", true
+      	}
+  	return inputs, "", true
+  }
+
+// invalidInputReason is the Reason of a result rejecting the invalid input
+// inp at position i
+func invalidInputReason(ruleName string, inp Trit, i int) string {
+  	return fmt.Sprintf("Rule '%s' rejected invalid input %d at position %d", ruleName, int(inp), i)
+  }
+
+// sanitizeVotesLocked is sanitizeLocked for the values of votes
+func (e *Engine) sanitizeVotesLocked(ruleName string, votes []WeightedTrit) ([]WeightedTrit, string, bool) {
+  	values := make([]Trit, len(votes))
+  	for i, v := range votes {
+      		values[i] = v.Value
+      	}
+  	values, reason, ok := e.sanitizeLocked(ruleName, values)
+  	if !ok {
+      		return nil, reason, false
+      	}
+  	for i, v := range votes {
+      		if v.Value != values[i] {
+            			votes = append([]WeightedTrit(nil), votes...)
+            			for j := i; j < len(votes); j++ {
+                    				votes[j].Value = values[j]
+                    			}
+            			break
+            		}
+      	}
+  	return votes, "", true
+  }
+
+// constantLocked applies the invalid input policy to the value of a
+// constant expression or DAG node. The caller must hold e.mu.
+func (e *Engine) constantLocked(v Trit) (Trit, string, bool) {
+  	if IsValid(v) {
+      		return v, "", true
+      	}
+  	if e.invalidMode != CoerceInvalid {
+      		return UNKNOWN, fmt.Sprintf("Constant rejected invalid value %d", int(v)), false
+      	}
+  	return UNKNOWN, "", true
+  }
+
+// invokeLocked applies the invalid input policy to inputs and runs rule
+// over them with ruleValueLocked. It returns the inputs evaluated with the
+// value and its note, or false and the reason the inputs were rejected. It
+// creates no result, so read-only paths may call it too. The caller must
+// hold e.mu, for reading at least.
+func (e *Engine) invokeLocked(ruleName string, rule TernaryRule, inputs []Trit) ([]Trit, Trit, string, bool) {
+  	inputs, reason, ok := e.sanitizeLocked(ruleName, inputs)
+  	if !ok {
+      		return nil, UNKNOWN, reason, false
+      	}
+  	value, note := e.ruleValueLocked(ruleName, rule, inputs)
+  	return inputs, value, note, true
+  }
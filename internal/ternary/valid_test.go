@@ -0,0 +1,217 @@
+package ternary
+
+import (
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestIsValid(t *testing.T) {
+  	tests := []struct {
+      		in   Trit
+      		want bool
+      	}{
+      		{FALSE, true}, {UNKNOWN, true}, {TRUE, true},
+      		{Trit(2), false}, {Trit(-2), false}, {Trit(5), false}, {Trit(-128), false}, {Trit(127), false},
+      	}
+  	for _, tt := range tests {
+      		if got := IsValid(tt.in); got != tt.want {
+            			t.Errorf("IsValid(%d) = %v, want %v", tt.in, got, tt.want)
+            		}
+      	}
+  }
+
+func TestInvalidInputs(t *testing.T) {
+  	rules := []string{"AND", "OR", "CONSENSUS"}
+  	e := NewEngine()
+  	for _, name := range rules {
+      		for _, bad := range []Trit{5, -3} {
+            			r := e.Evaluate(name, TRUE, bad, TRUE)
+            			if r.Value != UNKNOWN || r.Rule != "" || !strings.Contains(r.Reason, "invalid input") {
+                    				t.Fatal(name, r)
+                    			}
+            		}
+      	}
+  	if len(e.GetDecisions(DecisionFilter{})) != 0 {
+      		t.Fatal("rejected evaluations were recorded")
+      	}
+
+  	c := NewEngine(WithInvalidInputs(CoerceInvalid), CaptureInputs())
+  	in := []Trit{TRUE, 5, TRUE}
+  	want := map[string]Trit{"AND": UNKNOWN, "OR": TRUE, "CONSENSUS": TRUE}
+  	for _, name := range rules {
+      		r := c.Evaluate(name, in...)
+      		if r.Value != want[name] || !reflect.DeepEqual(r.Inputs, []Trit{TRUE, UNKNOWN, TRUE}) {
+            			t.Fatal(name, r)
+            		}
+      	}
+  	if in[1] != 5 {
+      		t.Fatal("caller slice changed")
+      	}
+  	if r := c.Evaluate("OR", FALSE, -3); r.Value != UNKNOWN {
+      		t.Fatal(r)
+      	}
+  	c.SetInvalidInputs(RejectInvalid)
+  	if r := c.Evaluate("OR", TRUE, -3); r.Rule != "" {
+      		t.Fatal(r)
+      	}
+  }
+
+// invalidInputPaths extends evaluationPaths with the entry points that take
+// their inputs in other shapes
+var invalidInputPaths = append(append([]struct {
+            	name string
+            	run  func(e *Engine, rule string, inputs []Trit) (Trit, string)
+            }(nil), evaluationPaths...), []struct {
+      	name string
+      	run  func(e *Engine, rule string, inputs []Trit) (Trit, string)
+      }{
+      	{"EvaluateByPriority", func(e *Engine, rule string, in []Trit) (Trit, string) {
+                    		r := e.EvaluateByPriority([]string{rule}, in...)
+                    		return r.Value, r.Reason
+                    	}},
+      	{"EvaluateNamed", func(e *Engine, rule string, in []Trit) (Trit, string) {
+                    		named := map[string]Trit{}
+                    		for i, v := range in {
+                              			named[string(rune('a'+i))] = v
+                              		}
+                    		r := e.EvaluateNamed(rule, named)
+                    		return r.Value, r.Reason
+                    	}},
+      	{"EvaluateWeighted", func(e *Engine, rule string, in []Trit) (Trit, string) {
+                    		r := e.EvaluateWeighted(rule, in, make([]float64, len(in)))
+                    		return r.Value, r.Reason
+                    	}},
+      	{"EvaluateWithPrior", func(e *Engine, rule string, in []Trit) (Trit, string) {
+                    		e.Evaluate(rule, TRUE, TRUE)
+                    		r := e.EvaluateWithPrior(rule, 1, in...)
+                    		return r.Value, r.Reason
+                    	}},
+      	{"EvaluateStream", func(e *Engine, rule string, in []Trit) (Trit, string) {
+                    		s := &evalStream{engine: e, ruleName: rule}
+                    		var r TernaryResult
+                    		for _, v := range in {
+                              			if r = s.next(v); r.Rule == "" {
+                                          				break
+                                          			}
+                              		}
+                    		return r.Value, r.Reason
+                    	}},
+      }...)
+
+func TestInvalidInputsOnEveryPath(t *testing.T) {
+  	guard := func(t *testing.T) TernaryRule {
+      		check := func(in []Trit) Trit {
+            			for _, v := range in {
+                    				if !IsValid(v) {
+                              					t.Errorf("rule saw invalid input %d", v)
+                              				}
+                    			}
+            			return TRUE
+            		}
+      		return TernaryRule{
+            			Name:     "GUARD",
+            			Weight:   1,
+            			Evaluate: func(in ...Trit) Trit { return check(in) },
+            			Weighted: func(in []Trit, _ []float64) Trit { return check(in) },
+            		}
+      	}
+  	for _, p := range invalidInputPaths {
+      		t.Run(p.name+"/reject", func(t *testing.T) {
+                    			e := NewEngine()
+                    			e.AddRule("GUARD", guard(t))
+                    			v, reason := p.run(e, "GUARD", []Trit{TRUE, Trit(7)})
+                    			if v != UNKNOWN || !strings.Contains(reason, "rejected invalid input 7") {
+                              				t.Errorf("got %v %q, want an invalid input rejection", v, reason)
+                              			}
+                    		})
+      		t.Run(p.name+"/coerce", func(t *testing.T) {
+                    			e := NewEngine(WithInvalidInputs(CoerceInvalid))
+                    			e.AddRule("GUARD", guard(t))
+                    			if v, reason := p.run(e, "GUARD", []Trit{TRUE, Trit(7)}); v != TRUE {
+                              				t.Errorf("got %v %q, want TRUE", v, reason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestInvalidConstants(t *testing.T) {
+  	tests := []struct {
+      		name string
+      		run  func(e *Engine) TernaryResult
+      	}{
+      		{"EvaluateExpr leaf", func(e *Engine) TernaryResult { return e.EvaluateExpr(Leaf(Trit(9))) }},
+      		{"EvaluateDAG constant", func(e *Engine) TernaryResult { return e.EvaluateDAG(&Node{Value: Trit(9)}) }},
+      		{"EvaluateExpr child", func(e *Engine) TernaryResult { return e.EvaluateExpr(Call("OR", Leaf(FALSE), Leaf(Trit(9)))) }},
+      		{"EvaluateDAG child", func(e *Engine) TernaryResult {
+                    			return e.EvaluateDAG(&Node{Rule: "OR", Children: []*Node{{Value: FALSE}, {Value: Trit(9)}}})
+                    		}},
+      		{"EvaluateTree leaf", func(e *Engine) TernaryResult {
+                    			return e.EvaluateTree(&RuleNode{RuleName: "OR", Inputs: []Trit{FALSE, Trit(9)}})
+                    		}},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := tt.run(NewEngine())
+                    			if r.Value != UNKNOWN || r.Rule != "" || !strings.Contains(r.Reason, "invalid") {
+                              				t.Errorf("reject: got %+v", r)
+                              			}
+                    			r = tt.run(NewEngine(WithInvalidInputs(CoerceInvalid)))
+                    			if r.Value != UNKNOWN || !strings.Contains(r.Reason, "Constant UNKNOWN") && r.Rule != "OR" {
+                              				t.Errorf("coerce: got %+v", r)
+                              			}
+                    		})
+      	}
+  }
+
+func TestInvalidVotes(t *testing.T) {
+  	votes := []WeightedTrit{{Value: TRUE, Weight: 1}, {Value: Trit(4), Weight: 1}}
+  	tests := []struct {
+      		name string
+      		run  func(e *Engine) TernaryResult
+      	}{
+      		{"EvaluateNot", func(e *Engine) TernaryResult { return e.EvaluateNot(votes[1]) }},
+      		{"EvaluateEvolveWeighted", func(e *Engine) TernaryResult { return e.EvaluateEvolveWeighted(votes) }},
+      		{"EvaluateConfidenceQuorum", func(e *Engine) TernaryResult { return e.EvaluateConfidenceQuorum(0.5, votes) }},
+      		{"EvaluateMargin", func(e *Engine) TernaryResult { return e.EvaluateMargin(1, TRUE, Trit(4)) }},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if r := tt.run(NewEngine()); r.Rule != "" || !strings.Contains(r.Reason, "rejected invalid input 4") {
+                              				t.Errorf("reject: got %+v", r)
+                              			}
+                    			r := tt.run(NewEngine(WithInvalidInputs(CoerceInvalid), CaptureInputs()))
+                    			for _, v := range r.Inputs {
+                              				if !IsValid(v) {
+                                          					t.Errorf("coerce: recorded invalid input in %+v", r)
+                                          				}
+                              			}
+                    			if r.Rule == "" {
+                              				t.Errorf("coerce: got failure %+v", r)
+                              			}
+                    		})
+      	}
+  	if votes[1].Value != Trit(4) {
+      		t.Fatal("caller votes changed")
+      	}
+  }
+
+func TestCheckDeterminismInvalidInputs(t *testing.T) {
+  	if ok, values := NewEngine().CheckDeterminism("AND", []Trit{TRUE, 3}, 2); ok || values != nil {
+      		t.Errorf("reject: got %v %v", ok, values)
+      	}
+  	e := NewEngine(WithInvalidInputs(CoerceInvalid))
+  	if ok, values := e.CheckDeterminism("AND", []Trit{TRUE, 3}, 2); !ok || !reflect.DeepEqual(values, []Trit{UNKNOWN}) {
+      		t.Errorf("coerce: got %v %v", ok, values)
+      	}
+  }
+
+func TestEvaluateIfSkippedInvalidInputs(t *testing.T) {
+  	if r := NewEngine().EvaluateIf("AND", []Trit{FALSE}, "OR", []Trit{Trit(6)}); r.Rule != "" || !strings.Contains(r.Reason, "rejected invalid input 6") {
+      		t.Errorf("reject: got %+v", r)
+      	}
+  	r := NewEngine(WithInvalidInputs(CoerceInvalid), CaptureInputs()).EvaluateIf("AND", []Trit{FALSE}, "OR", []Trit{Trit(6)})
+  	if r.Rule != "OR" || !reflect.DeepEqual(r.Inputs, []Trit{UNKNOWN}) {
+      		t.Errorf("coerce: got %+v", r)
+      	}
+  }
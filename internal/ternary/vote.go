@@ -0,0 +1,226 @@
+package ternary
+
+import (
+  	"fmt"
+  	"sync"
+  	"time"
+
+  	"github.com/google/uuid"
+  )
+
+// WeightedTrit is a single vote together with its weight, typically the
+// voter's trust or confidence
+//
+// Voters can be undecided in two different ways. A vote of UNKNOWN is an
+// indeterminate vote: it is cast and counts toward totals and quorums, so
+// it makes a definite majority harder to reach. An abstention (Abstain set,
+// Value and Weight ignored) is a vote not cast at all: the vote helpers
+// drop it before counting, shrinking the electorate instead. FALSE, the
+// third state, is a definite vote against.
+type WeightedTrit struct {
+  	Value   Trit    `json:"value"`
+  	Weight  float64 `json:"weight"`
+  	Abstain bool    `json:"abstain,omitempty"`
+  }
+
+// castVotes returns votes without the abstentions, reusing votes when
+// there are none
+func castVotes(votes []WeightedTrit) []WeightedTrit {
+  	for i, v := range votes {
+      		if !v.Abstain {
+            			continue
+            		}
+      		cast := append([]WeightedTrit(nil), votes[:i]...)
+      		for _, v := range votes[i+1:] {
+            			if !v.Abstain {
+                    				cast = append(cast, v)
+                    			}
+            		}
+      		return cast
+      	}
+  	return votes
+  }
+
+// weightedMajority is CONSENSUS over weights: TRUE or FALSE wins with more
+// than half of the total weight of the cast votes, anything else is UNKNOWN
+func weightedMajority(votes []WeightedTrit) Trit {
+  	var trueW, falseW, total float64
+  	for _, v := range votes {
+      		if v.Abstain {
+            			continue
+            		}
+      		switch v.Value {
+            		case TRUE:
+            			trueW += v.Weight
+            		case FALSE:
+            			falseW += v.Weight
+            		}
+      		total += v.Weight
+      	}
+  	if total <= 0 {
+      		return UNKNOWN
+      	}
+  	if trueW > total/2 {
+      		return TRUE
+      	}
+  	if falseW > total/2 {
+      		return FALSE
+      	}
+  	return UNKNOWN
+  }
+
+// timedVote is a WeightedTrit stamped with its arrival time
+type timedVote struct {
+  	vote WeightedTrit
+  	at   time.Time
+  }
+
+// WeightedWindow collects weighted votes over time and decides by weighted
+// majority over a trailing window
+type WeightedWindow struct {
+  	mu    sync.Mutex
+  	now   func() time.Time
+  	votes []timedVote
+  }
+
+// NewWeightedWindow creates an empty window. now supplies the current time;
+// nil uses time.Now.
+func NewWeightedWindow(now func() time.Time) *WeightedWindow {
+  	if now == nil {
+      		now = time.Now
+      	}
+  	return &WeightedWindow{now: now}
+  }
+
+// Push records a vote that arrived at the given time
+func (w *WeightedWindow) Push(vote WeightedTrit, at time.Time) {
+  	w.mu.Lock()
+  	defer w.mu.Unlock()
+  	w.votes = append(w.votes, timedVote{vote: vote, at: at})
+  }
+
+// WindowConsensus applies weighted majority to the votes that arrived within
+// window of the current time. Votes older than the window are discarded.
+func (w *WeightedWindow) WindowConsensus(window time.Duration) TernaryResult {
+  	w.mu.Lock()
+  	defer w.mu.Unlock()
+
+  	now := w.now()
+  	cutoff := now.Add(-window)
+
+  	kept := w.votes[:0]
+  	active := make([]WeightedTrit, 0, len(w.votes))
+  	for _, tv := range w.votes {
+      		if tv.at.Before(cutoff) {
+            			continue
+            		}
+      		kept = append(kept, tv)
+      		if !tv.at.After(now) {
+            			active = append(active, tv.vote)
+            		}
+      	}
+  	w.votes = kept
+
+  	value := weightedMajority(active)
+  	return TernaryResult{
+      		ID:         uuid.New().String(),
+      		Value:      value,
+      		Confidence: value.Confidence(),
+      		Reason:     fmt.Sprintf("WindowConsensus over %d votes in %s", len(active), window),
+      		Timestamp:  now,
+      	}
+  }
+
+// EvaluateConfidenceFiltered treats every vote whose weight (the voter's
+// confidence) is below floor as UNKNOWN and evaluates CONSENSUS over the
+// cast votes. Unlike abstentions, which are dropped, such votes still count
+// toward the majority.
+func (e *Engine) EvaluateConfidenceFiltered(floor float64, inputs []WeightedTrit) TernaryResult {
+  	inputs = castVotes(inputs)
+  	trits := make([]Trit, len(inputs))
+  	for i, in := range inputs {
+      		if in.Weight < floor {
+            			trits[i] = UNKNOWN
+            		} else {
+            			trits[i] = in.Value
+            		}
+      	}
+  	return e.Evaluate("CONSENSUS", trits...)
+  }
+
+// EvaluateNot negates a vote while keeping its certainty. Evaluate derives
+// confidence from the output trit, so NOT of a confident TRUE would come out
+// as FALSE with confidence 0; here the result's Confidence is instead the
+// input's weight (scaled by NOT's rule weight), making NOT of a 0.9-certain
+// TRUE a 0.9-certain FALSE. Read it signed through Score, which gives -0.9.
+func (e *Engine) EvaluateNot(input WeightedTrit) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	rule, failed, ok := e.ruleLocked("NOT")
+  	if !ok {
+      		return failed
+      	}
+
+  	inputs := []Trit{input.Value}
+  	inputs, value, note, ok := e.invokeLocked("NOT", rule, inputs)
+  	if !ok {
+      		return e.failedResult(note)
+      	}
+  	result := e.resultLocked("NOT", rule.Weight, value, inputs,
+      		withNote(fmt.Sprintf("Rule[NOT] negated %s with certainty %.2f", tritName(input.Value), input.Weight), note))
+  	e.setConfidenceLocked(&result, clamp01(input.Weight)*rule.Weight)
+  	return e.recordLocked(result)
+  }
+
+// ConfidenceQuorum returns the side whose summed confidence first exceeds
+// threshold, taking votes in order with each weight clamped to [0,1] as a
+// confidence, or UNKNOWN if neither side gets there. UNKNOWN votes and
+// abstentions add to neither side.
+func ConfidenceQuorum(threshold float64, votes []WeightedTrit) Trit {
+  	var trueSum, falseSum float64
+  	for _, v := range votes {
+      		if v.Abstain {
+            			continue
+            		}
+      		switch v.Value {
+            		case TRUE:
+            			trueSum += clamp01(v.Weight)
+            			if trueSum > threshold {
+                    				return TRUE
+                    			}
+            		case FALSE:
+            			falseSum += clamp01(v.Weight)
+            			if falseSum > threshold {
+                    				return FALSE
+                    			}
+            		}
+      	}
+  	return UNKNOWN
+  }
+
+// EvaluateConfidenceQuorum records a CONFIDENCE_QUORUM decision over votes
+// weighted by the voters' confidence; see ConfidenceQuorum. The decision is
+// named CONFIDENCE_QUORUM, but as it needs a threshold there is no rule of
+// that name to Evaluate; this method is the only way to reach it.
+func (e *Engine) EvaluateConfidenceQuorum(threshold float64, inputs []WeightedTrit) TernaryResult {
+  	inputs = castVotes(inputs)
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+  	inputs, reason, ok := e.sanitizeVotesLocked("CONFIDENCE_QUORUM", inputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	trits := make([]Trit, len(inputs))
+  	for i, in := range inputs {
+      		trits[i] = in.Value
+      	}
+  	value := ConfidenceQuorum(threshold, inputs)
+  	reason = fmt.Sprintf("Rule[CONFIDENCE_QUORUM] evaluated %d inputs against threshold %g", len(inputs), threshold)
+  	return e.recordLocked(e.resultLocked("CONFIDENCE_QUORUM", 1.0, value, trits, reason))
+  }
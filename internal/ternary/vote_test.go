@@ -0,0 +1,241 @@
+package ternary
+
+import (
+  	"fmt"
+  	"reflect"
+  	"strings"
+  	"testing"
+  	"time"
+  )
+
+func TestEvaluateNot(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		bounds   [2]float64
+      		raw      bool
+      		input    WeightedTrit
+      		want     Trit
+      		wantConf float64
+      		wantRaw  float64
+      	}{
+      		{name: "TRUE", bounds: [2]float64{0, 1}, input: WeightedTrit{Value: TRUE, Weight: 0.9}, want: FALSE, wantConf: 0.9},
+      		{name: "FALSE", bounds: [2]float64{0, 1}, input: WeightedTrit{Value: FALSE, Weight: 0.4}, want: TRUE, wantConf: 0.4},
+      		{name: "UNKNOWN", bounds: [2]float64{0, 1}, input: WeightedTrit{Value: UNKNOWN, Weight: 1}, want: UNKNOWN, wantConf: 1},
+      		{name: "weight clamped", bounds: [2]float64{0, 1}, input: WeightedTrit{Value: TRUE, Weight: 3}, want: FALSE, wantConf: 1},
+      		{name: "upper bound", bounds: [2]float64{0, 0.8}, input: WeightedTrit{Value: TRUE, Weight: 0.9}, want: FALSE, wantConf: 0.8},
+      		{name: "lower bound", bounds: [2]float64{0.2, 1}, input: WeightedTrit{Value: TRUE, Weight: 0.1}, want: FALSE, wantConf: 0.2},
+      		{name: "raw confidence", bounds: [2]float64{0, 0.5}, raw: true, input: WeightedTrit{Value: FALSE, Weight: 0.7}, want: TRUE, wantConf: 0.5, wantRaw: 0.7},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if err := e.SetConfidenceBounds(tt.bounds[0], tt.bounds[1]); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			e.SetRawConfidence(tt.raw)
+                    			r := e.EvaluateNot(tt.input)
+                    			if r.Value != tt.want || r.Confidence != tt.wantConf || r.RawConfidence != tt.wantRaw {
+                              				t.Errorf("EvaluateNot(%+v) = %v conf %v raw %v, want %v conf %v raw %v",
+                                          					tt.input, r.Value, r.Confidence, r.RawConfidence, tt.want, tt.wantConf, tt.wantRaw)
+                              			}
+                    		})
+      	}
+  }
+
+func TestWeightedWindow(t *testing.T) {
+  	now := time.Unix(1000, 0)
+  	type vote struct {
+      		value  Trit
+      		weight float64
+      		age    time.Duration
+      	}
+  	tests := []struct {
+      		name      string
+      		votes     []vote
+      		window    time.Duration
+      		want      Trit
+      		wantCount int
+      	}{
+      		{name: "old heavy vote excluded", votes: []vote{{FALSE, 10, time.Minute}, {TRUE, 1, time.Second}}, window: 10 * time.Second, want: TRUE, wantCount: 1},
+      		{name: "old heavy vote included", votes: []vote{{FALSE, 10, time.Minute}, {TRUE, 1, time.Second}}, window: 2 * time.Minute, want: FALSE, wantCount: 2},
+      		{name: "vote at cutoff kept", votes: []vote{{TRUE, 1, 10 * time.Second}}, window: 10 * time.Second, want: TRUE, wantCount: 1},
+      		{name: "future vote ignored", votes: []vote{{TRUE, 1, -time.Second}}, window: time.Minute, want: UNKNOWN, wantCount: 0},
+      		{name: "weighted tie", votes: []vote{{TRUE, 1, 0}, {FALSE, 1, 0}}, window: time.Minute, want: UNKNOWN, wantCount: 2},
+      		{name: "empty", window: time.Minute, want: UNKNOWN, wantCount: 0},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			w := NewWeightedWindow(func() time.Time { return now })
+                    			for _, v := range tt.votes {
+                              				w.Push(WeightedTrit{Value: v.value, Weight: v.weight}, now.Add(-v.age))
+                              			}
+                    			r := w.WindowConsensus(tt.window)
+                    			wantReason := fmt.Sprintf("WindowConsensus over %d votes", tt.wantCount)
+                    			if r.Value != tt.want || !strings.HasPrefix(r.Reason, wantReason) || !r.Timestamp.Equal(now) {
+                              				t.Errorf("WindowConsensus = %v %q at %v, want %v %q", r.Value, r.Reason, r.Timestamp, tt.want, wantReason)
+                              			}
+                    		})
+      	}
+  }
+
+func TestWeightedWindowAgesOut(t *testing.T) {
+  	now := time.Unix(1000, 0)
+  	w := NewWeightedWindow(func() time.Time { return now })
+  	w.Push(WeightedTrit{Value: FALSE, Weight: 10}, now)
+  	now = now.Add(time.Minute)
+  	w.Push(WeightedTrit{Value: TRUE, Weight: 1}, now)
+
+  	if r := w.WindowConsensus(10 * time.Second); r.Value != TRUE {
+      		t.Fatalf("WindowConsensus = %v, want TRUE", r.Value)
+      	}
+  	// the aged-out vote is gone even for a wider window
+  	if r := w.WindowConsensus(time.Hour); r.Value != TRUE {
+      		t.Errorf("WindowConsensus after aging out = %v, want TRUE", r.Value)
+      	}
+  }
+
+func TestEvaluateConfidenceFiltered(t *testing.T) {
+  	tests := []struct {
+      		name      string
+      		floor     float64
+      		inputs    []WeightedTrit
+      		want      Trit
+      		wantCount int
+      	}{
+      		{name: "no floor", floor: 0, inputs: []WeightedTrit{{Value: TRUE, Weight: 0.9}, {Value: FALSE, Weight: 0.1}, {Value: FALSE, Weight: 0.9}}, want: FALSE, wantCount: 3},
+      		{name: "weak vote becomes UNKNOWN", floor: 0.5, inputs: []WeightedTrit{{Value: TRUE, Weight: 0.9}, {Value: FALSE, Weight: 0.1}, {Value: FALSE, Weight: 0.9}}, want: UNKNOWN, wantCount: 3},
+      		{name: "weak dissent filtered", floor: 0.5, inputs: []WeightedTrit{{Value: TRUE, Weight: 0.9}, {Value: TRUE, Weight: 0.8}, {Value: FALSE, Weight: 0.1}}, want: TRUE, wantCount: 3},
+      		{name: "floor is inclusive", floor: 0.5, inputs: []WeightedTrit{{Value: TRUE, Weight: 0.5}, {Value: TRUE, Weight: 0.5}}, want: TRUE, wantCount: 2},
+      		{name: "UNKNOWN vote", floor: 0, inputs: []WeightedTrit{{Value: TRUE, Weight: 0.9}, {Value: FALSE, Weight: 0.1}, {Value: UNKNOWN, Weight: 0.9}}, want: UNKNOWN, wantCount: 3},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := NewEngine().EvaluateConfidenceFiltered(tt.floor, tt.inputs)
+                    			if r.Value != tt.want || r.InputCount != tt.wantCount {
+                              				t.Errorf("EvaluateConfidenceFiltered(%v, %+v) = %v over %d, want %v over %d",
+                                          					tt.floor, tt.inputs, r.Value, r.InputCount, tt.want, tt.wantCount)
+                              			}
+                    		})
+      	}
+  }
+
+func TestConfidenceQuorum(t *testing.T) {
+  	w := func(v Trit, weight float64) WeightedTrit { return WeightedTrit{Value: v, Weight: weight} }
+  	tenWeak := make([]WeightedTrit, 10)
+  	for i := range tenWeak {
+      		tenWeak[i] = w(TRUE, 0.1)
+      	}
+  	tests := []struct {
+      		name      string
+      		threshold float64
+      		votes     []WeightedTrit
+      		want      Trit
+      	}{
+      		{name: "no votes", threshold: 0.5, want: UNKNOWN},
+      		{name: "many weak votes short", threshold: 1.5, votes: tenWeak, want: UNKNOWN},
+      		{name: "few strong votes", threshold: 1.5, votes: []WeightedTrit{w(FALSE, 0.3), w(TRUE, 0.9), w(TRUE, 0.8)}, want: TRUE},
+      		{name: "must exceed", threshold: 1, votes: []WeightedTrit{w(FALSE, 0.5), w(FALSE, 0.5)}, want: UNKNOWN},
+      		{name: "first to cross wins", threshold: 0.5, votes: []WeightedTrit{w(FALSE, 0.6), w(TRUE, 1), w(TRUE, 1)}, want: FALSE},
+      		{name: "weights clamped", threshold: 1.5, votes: []WeightedTrit{w(TRUE, 5)}, want: UNKNOWN},
+      		{name: "negative weights clamped", threshold: 0.5, votes: []WeightedTrit{w(TRUE, -3), w(TRUE, 0.6)}, want: TRUE},
+      		{name: "UNKNOWN adds to neither", threshold: 0.5, votes: []WeightedTrit{w(UNKNOWN, 1), w(UNKNOWN, 1)}, want: UNKNOWN},
+      		{name: "abstentions add to neither", threshold: 0.5, votes: []WeightedTrit{{Value: TRUE, Weight: 1, Abstain: true}, w(FALSE, 0.6)}, want: FALSE},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := ConfidenceQuorum(tt.threshold, tt.votes); got != tt.want {
+                              				t.Errorf("ConfidenceQuorum(%v, %+v) = %v, want %v", tt.threshold, tt.votes, got, tt.want)
+                              			}
+                    			r := NewEngine().EvaluateConfidenceQuorum(tt.threshold, tt.votes)
+                    			if r.Value != tt.want || r.Rule != "CONFIDENCE_QUORUM" {
+                              				t.Errorf("EvaluateConfidenceQuorum = %v from %q, want %v", r.Value, r.Rule, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestEvaluateConfidenceQuorumInputs(t *testing.T) {
+  	votes := []WeightedTrit{{Value: TRUE, Weight: 0.9}, {Value: Trit(7), Weight: 1}, {Value: TRUE, Weight: 1, Abstain: true}, {Value: TRUE, Weight: 0.8}}
+  	tests := []struct {
+      		name       string
+      		mode       InvalidInputs
+      		want       Trit
+      		wantInputs []Trit
+      		recorded   int
+      	}{
+      		{name: "coerced", mode: CoerceInvalid, want: TRUE, wantInputs: []Trit{TRUE, UNKNOWN, TRUE}, recorded: 1},
+      		{name: "rejected", mode: RejectInvalid, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine(WithInvalidInputs(tt.mode), CaptureInputs())
+                    			r := e.EvaluateConfidenceQuorum(1.5, votes)
+                    			if r.Value != tt.want || !reflect.DeepEqual(r.Inputs, tt.wantInputs) {
+                              				t.Errorf("EvaluateConfidenceQuorum = %v over %v, want %v over %v", r.Value, r.Inputs, tt.want, tt.wantInputs)
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{})); n != tt.recorded {
+                              				t.Errorf("recorded %d decisions, want %d", n, tt.recorded)
+                              			}
+                    		})
+      	}
+  }
+
+func TestConfidenceQuorumNotARule(t *testing.T) {
+  	r := NewEngine().Evaluate("CONFIDENCE_QUORUM", TRUE)
+  	if r.Value != UNKNOWN || r.Reason != "Rule 'CONFIDENCE_QUORUM' not found" {
+      		t.Errorf("Evaluate(CONFIDENCE_QUORUM) = %v %q, want not found", r.Value, r.Reason)
+      	}
+  }
+
+func TestAbstain(t *testing.T) {
+  	tr := WeightedTrit{Value: TRUE, Weight: 1}
+  	fa := WeightedTrit{Value: FALSE, Weight: 1}
+  	un := WeightedTrit{Value: UNKNOWN, Weight: 1}
+  	ab := WeightedTrit{Abstain: true, Weight: 1}
+  	tests := []struct {
+      		name      string
+      		votes     []WeightedTrit
+      		want      Trit
+      		wantCount int
+      	}{
+      		{name: "UNKNOWN votes dilute", votes: []WeightedTrit{tr, tr, fa, un, un}, want: UNKNOWN, wantCount: 5},
+      		{name: "abstentions do not", votes: []WeightedTrit{tr, tr, fa, ab, ab}, want: TRUE, wantCount: 3},
+      		{name: "only abstentions", votes: []WeightedTrit{ab, ab}, want: UNKNOWN, wantCount: 0},
+      		{name: "abstaining TRUE ignored", votes: []WeightedTrit{{Value: TRUE, Weight: 5, Abstain: true}, fa}, want: FALSE, wantCount: 1},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if got := weightedMajority(tt.votes); got != tt.want {
+                              				t.Errorf("weightedMajority = %v, want %v", got, tt.want)
+                              			}
+                    			r := NewEngine().EvaluateConfidenceFiltered(0, tt.votes)
+                    			if r.Value != tt.want || r.InputCount != tt.wantCount {
+                              				t.Errorf("EvaluateConfidenceFiltered = %v over %d, want %v over %d", r.Value, r.InputCount, tt.want, tt.wantCount)
+                              			}
+                    		})
+      	}
+  }
+
+func TestCastVotes(t *testing.T) {
+  	tr := WeightedTrit{Value: TRUE, Weight: 1}
+  	ab := WeightedTrit{Abstain: true, Weight: 1}
+  	tests := []struct {
+      		votes []WeightedTrit
+      		want  []WeightedTrit
+      	}{
+      		{nil, nil},
+      		{[]WeightedTrit{tr}, []WeightedTrit{tr}},
+      		{[]WeightedTrit{ab, tr, ab, tr}, []WeightedTrit{tr, tr}},
+      		{[]WeightedTrit{ab}, nil},
+      	}
+  	for _, tt := range tests {
+      		in := append([]WeightedTrit(nil), tt.votes...)
+      		got := castVotes(in)
+      		if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+            			t.Errorf("castVotes(%+v) = %+v, want %+v", tt.votes, got, tt.want)
+            		}
+      		if !reflect.DeepEqual(in, tt.votes) {
+            			t.Errorf("castVotes modified its input: %+v", in)
+            		}
+      	}
+  }
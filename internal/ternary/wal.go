@@ -0,0 +1,169 @@
+package ternary
+
+import (
+  	"encoding/binary"
+  	"encoding/json"
+  	"errors"
+  	"fmt"
+  	"io"
+  	"os"
+  )
+
+// walMaxRecord bounds a single WAL record so a corrupt length prefix cannot
+// trigger a huge allocation during recovery
+const walMaxRecord = 16 * 1024 * 1024
+
+// walLog is an open write-ahead log
+type walLog struct {
+  	f   *os.File
+  	err error // first write error, sticky
+  }
+
+// EnableWAL appends every decision to the file at path before it enters the
+// in-memory history. Each record is a 4-byte big-endian length followed by
+// the JSON-encoded TernaryResult. Records are not fsynced unless
+// SetWALSync(true) is called, before or after EnableWAL. A previously
+// enabled log is closed.
+func (e *Engine) EnableWAL(path string) error {
+  	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+  	if err != nil {
+      		return fmt.Errorf("ternary: open WAL: %w", err)
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if e.wal != nil {
+      		e.wal.f.Close()
+      	}
+  	e.wal = &walLog{f: f}
+  	return nil
+  }
+
+// SetWALSync sets whether each WAL record is fsynced before the decision is
+// recorded. Syncing makes decisions durable across power loss at the cost
+// of a disk flush per evaluation. The setting applies to any log enabled
+// later as well.
+func (e *Engine) SetWALSync(enabled bool) {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.walSync = enabled
+  }
+
+// DisableWAL closes the write-ahead log and returns the first error met
+// while writing it, if any
+func (e *Engine) DisableWAL() error {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	if e.wal == nil {
+      		return nil
+      	}
+  	err := e.wal.err
+  	if cerr := e.wal.f.Close(); err == nil && cerr != nil {
+      		err = fmt.Errorf("ternary: close WAL: %w", cerr)
+      	}
+  	e.wal = nil
+  	return err
+  }
+
+// WALErr returns the first error met while writing the write-ahead log.
+// Decisions are still recorded in memory after a write error, but the log
+// stops growing.
+func (e *Engine) WALErr() error {
+  	e.mu.RLock()
+  	defer e.mu.RUnlock()
+  	if e.wal == nil {
+      		return nil
+      	}
+  	return e.wal.err
+  }
+
+// writeWALLocked appends result to the write-ahead log, if enabled. The
+// caller must hold e.mu.
+func (e *Engine) writeWALLocked(result TernaryResult) {
+  	w := e.wal
+  	if w == nil || w.err != nil {
+      		return
+      	}
+
+  	data, err := json.Marshal(result)
+  	if err != nil {
+      		w.err = fmt.Errorf("ternary: encode WAL record: %w", err)
+      		return
+      	}
+  	record := make([]byte, 4+len(data))
+  	binary.BigEndian.PutUint32(record, uint32(len(data)))
+  	copy(record[4:], data)
+
+  	if _, err := w.f.Write(record); err != nil {
+      		w.err = fmt.Errorf("ternary: write WAL: %w", err)
+      		return
+      	}
+  	if e.walSync {
+      		if err := w.f.Sync(); err != nil {
+            			w.err = fmt.Errorf("ternary: sync WAL: %w", err)
+            		}
+      	}
+  }
+
+// RecoverWAL replaces the decision history with the records of the
+// write-ahead log at path. A truncated trailing record, as left by a crash
+// mid-write, is ignored and cut from the file so the log can be appended to
+// again. A complete record that does not decode is an error and leaves the
+// history unchanged.
+func (e *Engine) RecoverWAL(path string) error {
+  	f, err := os.OpenFile(path, os.O_RDWR, 0)
+  	if err != nil {
+      		return fmt.Errorf("ternary: open WAL: %w", err)
+      	}
+  	defer f.Close()
+
+  	var (
+      		recovered []TernaryResult
+      		good      int64 // offset just past the last complete record
+      		header    [4]byte
+      	)
+  	for {
+      		if _, err := io.ReadFull(f, header[:]); err != nil {
+            			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+                    				break
+                    			}
+            			return fmt.Errorf("ternary: read WAL: %w", err)
+            		}
+      		n := binary.BigEndian.Uint32(header[:])
+      		if n > walMaxRecord {
+            			return fmt.Errorf("ternary: WAL record at offset %d too large (%d bytes)", good, n)
+            		}
+      		data := make([]byte, n)
+      		if _, err := io.ReadFull(f, data); err != nil {
+            			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+                    				break
+                    			}
+            			return fmt.Errorf("ternary: read WAL: %w", err)
+            		}
+
+      		var result TernaryResult
+      		if err := json.Unmarshal(data, &result); err != nil {
+            			return fmt.Errorf("ternary: WAL record at offset %d: %w", good, err)
+            		}
+      		recovered = append(recovered, result)
+      		good += 4 + int64(n)
+      	}
+
+  	if info, err := f.Stat(); err == nil && info.Size() > good {
+      		if err := f.Truncate(good); err != nil {
+            			return fmt.Errorf("ternary: truncate WAL: %w", err)
+            		}
+      	}
+
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.resetHistoryLocked(recovered)
+  	clear(e.lastValue)
+  	for _, r := range recovered {
+      		e.lastValue[r.Rule] = r.Value
+      	}
+  	return nil
+  }
@@ -0,0 +1,146 @@
+package ternary
+
+import (
+  	"os"
+  	"path/filepath"
+  	"testing"
+  )
+
+// writeWAL records AND(TRUE, FALSE) and OR(TRUE, FALSE) to a new WAL and
+// returns its path
+func writeWAL(t *testing.T) string {
+  	t.Helper()
+  	path := filepath.Join(t.TempDir(), "wal")
+  	e := NewEngine()
+  	if err := e.EnableWAL(path); err != nil {
+      		t.Fatal(err)
+      	}
+  	e.Evaluate("AND", TRUE, FALSE)
+  	e.Evaluate("OR", TRUE, FALSE)
+  	e.Evaluate("missing", TRUE) // failures are not recorded
+  	if err := e.DisableWAL(); err != nil {
+      		t.Fatal(err)
+      	}
+  	return path
+  }
+
+func TestRecoverWAL(t *testing.T) {
+  	tests := []struct {
+      		name     string
+      		tail     []byte
+      		wantErr  bool
+      		wantTrim bool
+      	}{
+      		{name: "clean"},
+      		{name: "truncated header", tail: []byte{0, 0}, wantTrim: true},
+      		{name: "truncated record", tail: []byte{0, 0, 0, 50, '{'}, wantTrim: true},
+      		{name: "corrupt record", tail: []byte{0, 0, 0, 1, '{'}, wantErr: true},
+      		{name: "oversized record", tail: []byte{0xff, 0xff, 0xff, 0xff}, wantErr: true},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			path := writeWAL(t)
+                    			clean, err := os.Stat(path)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+                    			if err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			f.Write(tt.tail)
+                    			f.Close()
+
+                    			r := NewEngine()
+                    			r.Evaluate("NOT", TRUE)
+                    			err = r.RecoverWAL(path)
+                    			if (err != nil) != tt.wantErr {
+                              				t.Fatalf("RecoverWAL error = %v, wantErr %v", err, tt.wantErr)
+                              			}
+                    			h := r.GetDecisions(DecisionFilter{})
+                    			if tt.wantErr {
+                              				if len(h) != 1 || h[0].Rule != "NOT" {
+                                          					t.Errorf("failed recovery changed the history: %v", h)
+                                          				}
+                              				return
+                              			}
+                    			if len(h) != 2 || h[0].Rule != "AND" || h[1].Rule != "OR" || h[1].Value != TRUE {
+                              				t.Errorf("recovered %v, want AND then OR", h)
+                              			}
+                    			if info, _ := os.Stat(path); tt.wantTrim && info.Size() != clean.Size() {
+                              				t.Errorf("WAL is %d bytes after recovery, want truncated to %d", info.Size(), clean.Size())
+                              			}
+                    		})
+      	}
+  }
+
+func TestRecoverWALAppend(t *testing.T) {
+  	path := writeWAL(t)
+  	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+  	if err != nil {
+      		t.Fatal(err)
+      	}
+  	f.Write([]byte{0, 0, 0, 50, '{'})
+  	f.Close()
+
+  	r := NewEngine()
+  	if err := r.RecoverWAL(path); err != nil {
+      		t.Fatal(err)
+      	}
+  	if err := r.EnableWAL(path); err != nil {
+      		t.Fatal(err)
+      	}
+  	r.Evaluate("NOT", TRUE)
+  	if err := r.DisableWAL(); err != nil {
+      		t.Fatal(err)
+      	}
+
+  	r2 := NewEngine()
+  	if err := r2.RecoverWAL(path); err != nil {
+      		t.Fatal(err)
+      	}
+  	if h := r2.GetDecisions(DecisionFilter{}); len(h) != 3 || h[2].Rule != "NOT" {
+      		t.Errorf("recovered %v, want AND, OR, NOT", h)
+      	}
+  }
+
+func TestSetWALSync(t *testing.T) {
+  	tests := []struct {
+      		name   string
+      		before bool // call SetWALSync before EnableWAL
+      	}{
+      		{name: "before EnableWAL", before: true},
+      		{name: "after EnableWAL"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			path := filepath.Join(t.TempDir(), "wal")
+                    			e := NewEngine()
+                    			if tt.before {
+                              				e.SetWALSync(true)
+                              			}
+                    			if err := e.EnableWAL(path); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if !tt.before {
+                              				e.SetWALSync(true)
+                              			}
+                    			if !e.walSync {
+                              				t.Fatal("sync setting lost")
+                              			}
+                    			e.Evaluate("AND", TRUE)
+                    			if err := e.DisableWAL(); err != nil {
+                              				t.Fatal(err)
+                              			}
+
+                    			// a reopened log keeps syncing
+                    			if err := e.EnableWAL(path); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			if !e.walSync {
+                              				t.Error("sync setting lost on reopen")
+                              			}
+                    			e.DisableWAL()
+                    		})
+      	}
+  }
@@ -0,0 +1,84 @@
+package ternary
+
+import "fmt"
+
+// DefaultUnknownBlock is the share of UNKNOWN weight above which
+// WEIGHTED_CONSENSUS abstains
+const DefaultUnknownBlock = 0.5
+
+// weightedConsensus tallies the weight behind TRUE, FALSE and UNKNOWN.
+// When the UNKNOWN share of the total exceeds block, a few heavy
+// abstentions block the decision and the result is UNKNOWN; otherwise the
+// heavier of TRUE and FALSE wins, UNKNOWN on a tie. Negative weights count
+// as zero.
+func weightedConsensus(block float64, inputs []Trit, weights []float64) Trit {
+  	var trueW, falseW, unknownW float64
+  	for i, inp := range inputs {
+      		w := weights[i]
+      		if w < 0 {
+            			w = 0
+            		}
+      		switch inp {
+            		case TRUE:
+            			trueW += w
+            		case FALSE:
+            			falseW += w
+            		default:
+            			unknownW += w
+            		}
+      	}
+
+  	total := trueW + falseW + unknownW
+  	switch {
+      	case total <= 0 || unknownW/total > block:
+      		return UNKNOWN
+      	case trueW > falseW:
+      		return TRUE
+      	case falseW > trueW:
+      		return FALSE
+      	default:
+      		return UNKNOWN
+      	}
+  }
+
+// SetUnknownBlock sets the share of UNKNOWN weight, in [0,1], above which
+// WEIGHTED_CONSENSUS returns UNKNOWN
+func (e *Engine) SetUnknownBlock(share float64) error {
+  	if !(share >= 0 && share <= 1) {
+      		return fmt.Errorf("ternary: unknown block share %v outside [0, 1]", share)
+      	}
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+  	e.unknownBlock = share
+  	return nil
+  }
+
+// EvaluateWeighted evaluates a rule that accepts per-input weights, such as
+// WEIGHTED_CONSENSUS, and records the decision. Mismatched lengths or a
+// rule without a Weighted function yield an unrecorded UNKNOWN result
+// carrying the error.
+func (e *Engine) EvaluateWeighted(ruleName string, inputs []Trit, weights []float64) TernaryResult {
+  	e.mu.Lock()
+  	defer e.mu.Unlock()
+
+  	e.evalCount++
+
+  	if len(inputs) != len(weights) {
+      		return e.failedResult(fmt.Sprintf("Rule[%s] got %d inputs but %d weights", ruleName, len(inputs), len(weights)))
+      	}
+  	rule, failed, ok := e.ruleLocked(ruleName)
+  	if !ok {
+      		return failed
+      	}
+  	if rule.Weighted == nil {
+      		return e.failedResult(fmt.Sprintf("Rule '%s' does not accept weights", ruleName))
+      	}
+
+  	inputs, reason, ok := e.sanitizeLocked(ruleName, inputs)
+  	if !ok {
+      		return e.failedResult(reason)
+      	}
+  	value := e.weightedLocked(rule, inputs, weights)
+  	reason = fmt.Sprintf("Rule[%s] evaluated %d weighted inputs", ruleName, len(inputs))
+  	return e.recordLocked(e.resultLocked(ruleName, rule.Weight, value, inputs, reason))
+  }
@@ -0,0 +1,89 @@
+package ternary
+
+import (
+  	"strings"
+  	"testing"
+  )
+
+func TestWeightedConsensus(t *testing.T) {
+  	tests := []struct {
+      		name    string
+      		block   float64
+      		inputs  []Trit
+      		weights []float64
+      		want    Trit
+      	}{
+      		{name: "equal weights", block: DefaultUnknownBlock, inputs: []Trit{TRUE, TRUE, TRUE, FALSE}, weights: []float64{1, 1, 1, 1}, want: TRUE},
+      		{name: "heavy dissent", block: DefaultUnknownBlock, inputs: []Trit{TRUE, TRUE, TRUE, FALSE}, weights: []float64{1, 1, 1, 5}, want: FALSE},
+      		{name: "tie", block: DefaultUnknownBlock, inputs: []Trit{TRUE, FALSE}, weights: []float64{2, 2}, want: UNKNOWN},
+      		{name: "heavy abstention blocks", block: DefaultUnknownBlock, inputs: []Trit{TRUE, TRUE, UNKNOWN}, weights: []float64{1, 1, 3}, want: UNKNOWN},
+      		{name: "half UNKNOWN does not block", block: DefaultUnknownBlock, inputs: []Trit{TRUE, UNKNOWN}, weights: []float64{1, 1}, want: TRUE},
+      		{name: "raised block", block: 0.8, inputs: []Trit{TRUE, TRUE, UNKNOWN}, weights: []float64{1, 1, 3}, want: TRUE},
+      		{name: "zero block", block: 0, inputs: []Trit{TRUE, UNKNOWN}, weights: []float64{5, 0.1}, want: UNKNOWN},
+      		{name: "negative weights count as zero", block: DefaultUnknownBlock, inputs: []Trit{TRUE, FALSE}, weights: []float64{1, -5}, want: TRUE},
+      		{name: "zero total", block: DefaultUnknownBlock, inputs: []Trit{TRUE, FALSE}, weights: []float64{0, 0}, want: UNKNOWN},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			if err := e.SetUnknownBlock(tt.block); err != nil {
+                              				t.Fatal(err)
+                              			}
+                    			r := e.EvaluateWeighted("WEIGHTED_CONSENSUS", tt.inputs, tt.weights)
+                    			if r.Value != tt.want || r.Rule != "WEIGHTED_CONSENSUS" {
+                              				t.Errorf("EvaluateWeighted(%v, %v) = %v from %q, want %v", tt.inputs, tt.weights, r.Value, r.Rule, tt.want)
+                              			}
+                    		})
+      	}
+  }
+
+func TestWeightedConsensusUnweighted(t *testing.T) {
+  	tests := []struct {
+      		inputs []Trit
+      		want   Trit
+      	}{
+      		{[]Trit{TRUE, TRUE, FALSE}, TRUE},
+      		{[]Trit{FALSE, FALSE, UNKNOWN}, FALSE},
+      		{[]Trit{TRUE, UNKNOWN, UNKNOWN}, UNKNOWN},
+      		{nil, UNKNOWN},
+      	}
+  	e := NewEngine()
+  	for _, tt := range tests {
+      		if got := e.Evaluate("WEIGHTED_CONSENSUS", tt.inputs...).Value; got != tt.want {
+            			t.Errorf("WEIGHTED_CONSENSUS%v = %v, want %v", tt.inputs, got, tt.want)
+            		}
+      	}
+  }
+
+func TestEvaluateWeightedErrors(t *testing.T) {
+  	tests := []struct {
+      		name       string
+      		rule       string
+      		weights    []float64
+      		wantReason string
+      	}{
+      		{name: "length mismatch", rule: "WEIGHTED_CONSENSUS", weights: []float64{1}, wantReason: "got 2 inputs but 1 weights"},
+      		{name: "unweighted rule", rule: "AND", weights: []float64{1, 1}, wantReason: "Rule 'AND' does not accept weights"},
+      		{name: "missing rule", rule: "missing", weights: []float64{1, 1}, wantReason: "Rule 'missing' not found"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			e := NewEngine()
+                    			r := e.EvaluateWeighted(tt.rule, []Trit{TRUE, FALSE}, tt.weights)
+                    			if r.Value != UNKNOWN || !strings.Contains(r.Reason, tt.wantReason) {
+                              				t.Errorf("EvaluateWeighted = %v %q, want UNKNOWN containing %q", r.Value, r.Reason, tt.wantReason)
+                              			}
+                    			if n := len(e.GetDecisions(DecisionFilter{})); n != 0 {
+                              				t.Errorf("recorded %d decisions, want 0", n)
+                              			}
+                    		})
+      	}
+  }
+
+func TestSetUnknownBlock(t *testing.T) {
+  	for _, share := range []float64{-0.1, 1.1} {
+      		if err := NewEngine().SetUnknownBlock(share); err == nil {
+            			t.Errorf("SetUnknownBlock(%v) accepted", share)
+            		}
+      	}
+  }
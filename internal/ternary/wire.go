@@ -0,0 +1,171 @@
+package ternary
+
+import (
+  	"encoding/binary"
+  	"errors"
+  	"fmt"
+  	"io"
+  	"math"
+  )
+
+// Binary wire format for remote evaluation. Every frame is
+//
+//	length  uint32  bytes that follow
+//	version uint8   wireVersion
+//	kind    uint8   wireRequest or wireResult
+//	payload
+//
+// with all integers big-endian. A request payload is the rule name (uint16
+// length, bytes) followed by the inputs (uint32 count, one int8 each); a
+// result payload is the value (int8), the confidence (float64 bits) and
+// the ID (uint16 length, bytes).
+const (
+  	wireVersion = 1
+
+  	wireRequest = 1
+  	wireResult  = 2
+
+  	// wireMaxFrame bounds frames accepted by ReadFrame
+  	wireMaxFrame = 16 * 1024 * 1024
+  )
+
+// EncodeRequest encodes an evaluation request as a binary frame
+func EncodeRequest(ruleName string, inputs []Trit) []byte {
+  	payload := make([]byte, 0, 2+len(ruleName)+4+len(inputs))
+  	payload = appendString16(payload, ruleName)
+  	payload = binary.BigEndian.AppendUint32(payload, uint32(len(inputs)))
+  	for _, inp := range inputs {
+      		payload = append(payload, byte(inp))
+      	}
+  	return wireFrame(wireRequest, payload)
+  }
+
+// DecodeRequest decodes a frame written by EncodeRequest
+func DecodeRequest(frame []byte) (string, []Trit, error) {
+  	payload, err := wirePayload(frame, wireRequest)
+  	if err != nil {
+      		return "", nil, err
+      	}
+  	ruleName, payload, err := readString16(payload)
+  	if err != nil {
+      		return "", nil, err
+      	}
+  	if len(payload) < 4 {
+      		return "", nil, errWireShort
+      	}
+  	n := binary.BigEndian.Uint32(payload)
+  	payload = payload[4:]
+  	if uint64(len(payload)) != uint64(n) {
+      		return "", nil, fmt.Errorf("ternary: wire request has %d input bytes, want %d", len(payload), n)
+      	}
+  	inputs := make([]Trit, n)
+  	for i, b := range payload {
+      		inputs[i] = Trit(int8(b))
+      	}
+  	return ruleName, inputs, nil
+  }
+
+// EncodeResult encodes the value, confidence and ID of r as a binary frame
+func EncodeResult(r TernaryResult) []byte {
+  	payload := make([]byte, 0, 1+8+2+len(r.ID))
+  	payload = append(payload, byte(r.Value))
+  	payload = binary.BigEndian.AppendUint64(payload, math.Float64bits(r.Confidence))
+  	payload = appendString16(payload, r.ID)
+  	return wireFrame(wireResult, payload)
+  }
+
+// DecodeResult decodes a frame written by EncodeResult. Only the value,
+// confidence and ID are set.
+func DecodeResult(frame []byte) (TernaryResult, error) {
+  	payload, err := wirePayload(frame, wireResult)
+  	if err != nil {
+      		return TernaryResult{}, err
+      	}
+  	if len(payload) < 9 {
+      		return TernaryResult{}, errWireShort
+      	}
+  	r := TernaryResult{
+      		Value:      Trit(int8(payload[0])),
+      		Confidence: math.Float64frombits(binary.BigEndian.Uint64(payload[1:9])),
+      	}
+  	id, rest, err := readString16(payload[9:])
+  	if err != nil {
+      		return TernaryResult{}, err
+      	}
+  	if len(rest) != 0 {
+      		return TernaryResult{}, fmt.Errorf("ternary: wire result has %d trailing bytes", len(rest))
+      	}
+  	r.ID = id
+  	return r, nil
+  }
+
+// ReadFrame reads one length-prefixed frame from r, for passing to
+// DecodeRequest or DecodeResult
+func ReadFrame(r io.Reader) ([]byte, error) {
+  	var header [4]byte
+  	if _, err := io.ReadFull(r, header[:]); err != nil {
+      		return nil, err
+      	}
+  	n := binary.BigEndian.Uint32(header[:])
+  	if n > wireMaxFrame {
+      		return nil, fmt.Errorf("ternary: wire frame of %d bytes too large", n)
+      	}
+  	frame := make([]byte, 4+n)
+  	copy(frame, header[:])
+  	if _, err := io.ReadFull(r, frame[4:]); err != nil {
+      		if errors.Is(err, io.EOF) {
+            			err = io.ErrUnexpectedEOF
+            		}
+      		return nil, err
+      	}
+  	return frame, nil
+  }
+
+var errWireShort = errors.New("ternary: wire frame truncated")
+
+// wireFrame prefixes payload with the frame header
+func wireFrame(kind byte, payload []byte) []byte {
+  	frame := make([]byte, 0, 6+len(payload))
+  	frame = binary.BigEndian.AppendUint32(frame, uint32(2+len(payload)))
+  	frame = append(frame, wireVersion, kind)
+  	return append(frame, payload...)
+  }
+
+// wirePayload checks the header of frame and returns its payload
+func wirePayload(frame []byte, kind byte) ([]byte, error) {
+  	if len(frame) < 6 {
+      		return nil, errWireShort
+      	}
+  	if n := binary.BigEndian.Uint32(frame); uint64(n) != uint64(len(frame)-4) {
+      		return nil, fmt.Errorf("ternary: wire frame length %d, have %d bytes", n, len(frame)-4)
+      	}
+  	if frame[4] != wireVersion {
+      		return nil, fmt.Errorf("ternary: unsupported wire version %d", frame[4])
+      	}
+  	if frame[5] != kind {
+      		return nil, fmt.Errorf("ternary: wire frame kind %d, want %d", frame[5], kind)
+      	}
+  	return frame[6:], nil
+  }
+
+// appendString16 appends s with a uint16 length prefix, truncating s to
+// 65535 bytes
+func appendString16(b []byte, s string) []byte {
+  	if len(s) > math.MaxUint16 {
+      		s = s[:math.MaxUint16]
+      	}
+  	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+  	return append(b, s...)
+  }
+
+// readString16 reads a string written by appendString16
+func readString16(b []byte) (string, []byte, error) {
+  	if len(b) < 2 {
+      		return "", nil, errWireShort
+      	}
+  	n := int(binary.BigEndian.Uint16(b))
+  	if len(b) < 2+n {
+      		return "", nil, errWireShort
+      	}
+  	return string(b[2 : 2+n]), b[2+n:], nil
+  }
@@ -0,0 +1,126 @@
+package ternary
+
+import (
+  	"bytes"
+  	"errors"
+  	"io"
+  	"reflect"
+  	"strings"
+  	"testing"
+  )
+
+func TestEncodeRequestGolden(t *testing.T) {
+  	tests := []struct {
+      		rule   string
+      		inputs []Trit
+      		want   []byte
+      	}{
+      		{"AND", []Trit{TRUE, FALSE, UNKNOWN}, []byte{0, 0, 0, 14, 1, 1, 0, 3, 'A', 'N', 'D', 0, 0, 0, 3, 0x01, 0xff, 0x00}},
+      		{"", nil, []byte{0, 0, 0, 8, 1, 1, 0, 0, 0, 0, 0, 0}},
+      	}
+  	for _, tt := range tests {
+      		got := EncodeRequest(tt.rule, tt.inputs)
+      		if !bytes.Equal(got, tt.want) {
+            			t.Errorf("EncodeRequest(%q, %v) = % x, want % x", tt.rule, tt.inputs, got, tt.want)
+            		}
+      		name, inputs, err := DecodeRequest(got)
+      		if err != nil || name != tt.rule || len(inputs) != len(tt.inputs) || (len(inputs) > 0 && !reflect.DeepEqual(inputs, tt.inputs)) {
+            			t.Errorf("DecodeRequest = %q, %v, %v, want %q, %v", name, inputs, err, tt.rule, tt.inputs)
+            		}
+      	}
+  }
+
+func TestEncodeResultGolden(t *testing.T) {
+  	r := TernaryResult{ID: "x", Value: TRUE, Confidence: 0.5, Reason: "not encoded"}
+  	want := []byte{0, 0, 0, 14, 1, 2, 0x01, 0x3f, 0xe0, 0, 0, 0, 0, 0, 0, 0, 1, 'x'}
+  	got := EncodeResult(r)
+  	if !bytes.Equal(got, want) {
+      		t.Fatalf("EncodeResult = % x, want % x", got, want)
+      	}
+  	back, err := DecodeResult(got)
+  	if err != nil || !reflect.DeepEqual(back, TernaryResult{ID: "x", Value: TRUE, Confidence: 0.5}) {
+      		t.Errorf("DecodeResult = %+v, %v", back, err)
+      	}
+  }
+
+func TestDecodeErrors(t *testing.T) {
+  	request := EncodeRequest("AND", []Trit{TRUE})
+  	result := EncodeResult(TernaryResult{ID: "x", Value: TRUE})
+  	patch := func(frame []byte, i int, b byte) []byte {
+      		frame = append([]byte(nil), frame...)
+      		frame[i] = b
+      		return frame
+      	}
+  	tests := []struct {
+      		name    string
+      		decode  func([]byte) error
+      		frame   []byte
+      		wantErr string
+      	}{
+      		{name: "short header", decode: decodeRequestErr, frame: request[:5], wantErr: "truncated"},
+      		{name: "length mismatch", decode: decodeRequestErr, frame: request[:len(request)-1], wantErr: "wire frame length"},
+      		{name: "version", decode: decodeRequestErr, frame: patch(request, 4, 9), wantErr: "unsupported wire version 9"},
+      		{name: "request as result", decode: decodeResultErr, frame: request, wantErr: "wire frame kind 1, want 2"},
+      		{name: "result as request", decode: decodeRequestErr, frame: result, wantErr: "wire frame kind 2, want 1"},
+      		{name: "input count", decode: decodeRequestErr, frame: patch(request, 14, 2), wantErr: "has 1 input bytes, want 2"},
+      		{name: "rule name length", decode: decodeRequestErr, frame: patch(request, 7, 200), wantErr: "truncated"},
+      		{name: "result ID length", decode: decodeResultErr, frame: patch(result, 16, 0), wantErr: "1 trailing bytes"},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			if err := tt.decode(tt.frame); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                              				t.Errorf("decode(% x) error = %v, want %q", tt.frame, err, tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func decodeRequestErr(frame []byte) error {
+  	_, _, err := DecodeRequest(frame)
+  	return err
+  }
+
+func decodeResultErr(frame []byte) error {
+  	_, err := DecodeResult(frame)
+  	return err
+  }
+
+func TestReadFrame(t *testing.T) {
+  	a := EncodeRequest("AND", []Trit{TRUE})
+  	b := EncodeResult(TernaryResult{ID: "id", Value: FALSE})
+  	tests := []struct {
+      		name    string
+      		stream  []byte
+      		want    [][]byte
+      		wantErr error
+      	}{
+      		{name: "two frames", stream: append(append([]byte(nil), a...), b...), want: [][]byte{a, b}, wantErr: io.EOF},
+      		{name: "empty", wantErr: io.EOF},
+      		{name: "truncated body", stream: a[:len(a)-1], wantErr: io.ErrUnexpectedEOF},
+      		{name: "truncated header", stream: a[:2], wantErr: io.ErrUnexpectedEOF},
+      	}
+  	for _, tt := range tests {
+      		t.Run(tt.name, func(t *testing.T) {
+                    			r := bytes.NewReader(tt.stream)
+                    			var got [][]byte
+                    			var err error
+                    			for {
+                              				var frame []byte
+                              				if frame, err = ReadFrame(r); err != nil {
+                                          					break
+                                          				}
+                              				got = append(got, frame)
+                              			}
+                    			if !reflect.DeepEqual(got, tt.want) || !errors.Is(err, tt.wantErr) {
+                              				t.Errorf("ReadFrame = %d frames, %v, want %d, %v", len(got), err, len(tt.want), tt.wantErr)
+                              			}
+                    		})
+      	}
+  }
+
+func TestReadFrameTooLarge(t *testing.T) {
+  	_, err := ReadFrame(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+  	if err == nil || !strings.Contains(err.Error(), "too large") {
+      		t.Errorf("ReadFrame error = %v, want too large", err)
+      	}
+  }